@@ -39,6 +39,7 @@ const (
 const (
 	EnvPodName         = "MY_POD_NAME"
 	EnvClusterCompName = "MY_CLUSTER_COMP_NAME"
+	EnvNamespace       = "MY_NAMESPACE"
 )
 
 // old envs for KB 0.9
@@ -61,6 +62,17 @@ func GetPodName() string {
 	}
 }
 
+func GetNamespace() string {
+	switch {
+	case viper.IsSet(KBEnvNamespace):
+		return viper.GetString(KBEnvNamespace)
+	case viper.IsSet(EnvNamespace):
+		return viper.GetString(EnvNamespace)
+	default:
+		return ""
+	}
+}
+
 func GetClusterCompName() string {
 	switch {
 	case viper.IsSet(KBEnvClusterCompName):
@@ -0,0 +1,119 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/operations"
+)
+
+type ConfigDiffOptions struct {
+	OptionsBase
+	file   string
+	output string
+}
+
+func (options *ConfigDiffOptions) Validate() error {
+	if options.output != "" && options.output != operations.FormatJSON {
+		return errors.Errorf("unsupported output format %s", options.output)
+	}
+	return options.OptionsBase.Validate()
+}
+
+func (options *ConfigDiffOptions) Run() error {
+	req := &operations.OpsRequest{Parameters: map[string]any{"file": options.file}}
+	resp, err := options.Do(context.Background(), req)
+	if err != nil {
+		return err
+	}
+	report, ok := resp.Data["report"].(*models.ConfigDriftReport)
+	if !ok {
+		return errors.New("no config drift report")
+	}
+
+	if options.output == operations.FormatJSON {
+		data, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+	printConfigDrift(report)
+	return nil
+}
+
+func printConfigDrift(report *models.ConfigDriftReport) {
+	if len(report.Drifts) == 0 {
+		fmt.Println("no drift found")
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "NAME\tFILE VALUE\tLIVE VALUE\tFILE")
+		for _, drift := range report.Drifts {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", drift.Name, drift.FileValue, drift.LiveValue, drift.File)
+		}
+		_ = w.Flush()
+	}
+	if len(report.Unknown) > 0 {
+		fmt.Printf("unknown parameters: %v\n", report.Unknown)
+	}
+}
+
+var configDiffOptions = &ConfigDiffOptions{
+	OptionsBase: OptionsBase{
+		Action: "configdrift",
+	},
+}
+
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the config files of the database.",
+}
+
+var ConfigDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare the config files with the parameters the database is running with.",
+	Example: `
+dbctl mysql config diff
+
+# compare another config file, and print the result in json
+dbctl postgresql config diff --file /etc/postgresql/postgresql.conf -o json
+  `,
+	Args: cobra.MinimumNArgs(0),
+	Run:  CmdRunner(configDiffOptions),
+}
+
+func init() {
+	ConfigDiffCmd.Flags().StringVar(&configDiffOptions.file, "file", "", "The config file to compare, the one the database is started with by default")
+	ConfigDiffCmd.Flags().StringVarP(&configDiffOptions.output, "output", "o", "", "The output format, json for the drift report")
+	ConfigDiffCmd.Flags().BoolP("help", "h", false, "Print this help message")
+
+	ConfigCmd.AddCommand(ConfigDiffCmd)
+	DatabaseCmd.AddCommand(ConfigCmd)
+}
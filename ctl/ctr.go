@@ -68,9 +68,12 @@ type dbctlVersion struct {
 }
 
 var (
-	cliVersion  string
-	versionFlag bool
-	dbctlVer    dbctlVersion
+	cliVersion     string
+	versionFlag    bool
+	configFile     string
+	credentialsDir string
+	pluginDir      string
+	dbctlVer       dbctlVersion
 )
 
 // Execute adds all child commands to the root command.
@@ -84,6 +87,11 @@ func Execute(cliVersion, apiVersion string) {
 
 	setVersion()
 
+	if err := loadPlugins(os.Args[1:]); err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+
 	if err := RootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(-1)
@@ -111,6 +119,10 @@ func initConfig() {
 }
 
 func init() {
+	RootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Path to the dbctl config file, e.g. dbctl.yaml. Precedence: credentials dir > env > config file.")
+	RootCmd.PersistentFlags().StringVar(&credentialsDir, "credentials-dir", "", "Directory of the mounted credentials with the username and password files, which are reloaded on rotation.")
+	RootCmd.PersistentFlags().StringVar(&pluginDir, "plugin-dir", "", "Directory of the engine plugins, each subdirectory named after the engine type holds an executable per method.")
+
 	klog.InitFlags(flag.CommandLine)
 	opts.BindFlags(flag.CommandLine)
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
@@ -29,8 +29,12 @@ import (
 
 	"github.com/apecloud/dbctl/engines/models"
 	"github.com/apecloud/dbctl/engines/register"
+	utilconfig "github.com/apecloud/dbctl/util/config"
 )
 
+// engineType is the database type specified by the command, e.g. mysql.
+var engineType string
+
 var DatabaseCmd = &cobra.Command{
 	Use:     "database",
 	Aliases: models.GetEngineTypeListStr(),
@@ -49,7 +53,17 @@ dbctl mongodb createuser --username root --password password
 			return errors.New("please specify a database type supported by dbctl, the valid types are: " + strings.Join(models.GetEngineTypeListStr(), ", "))
 		}
 
+		if configFile != "" {
+			if err := utilconfig.LoadFile(configFile); err != nil {
+				return err
+			}
+		}
+		if credentialsDir != "" {
+			utilconfig.SetCredentialsDir(credentialsDir)
+		}
+
 		// Initialize DB Manager
+		engineType = dbType
 		err := register.InitDBManager(dbType)
 		if err != nil {
 			return errors.Wrap(err, "DB manager initialize failed")
@@ -21,26 +21,49 @@ package ctl
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
-	"github.com/apecloud/dbctl/operations/replica"
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/register"
+	"github.com/apecloud/dbctl/operations"
 )
 
 type GetRoleOptions struct {
 	OptionsBase
+	output string
+}
+
+func (options *GetRoleOptions) Validate() error {
+	if options.output != "" && options.output != operations.FormatJSON {
+		return errors.Errorf("unsupported output format %s", options.output)
+	}
+	return options.OptionsBase.Validate()
 }
 
 func (options *GetRoleOptions) Run() error {
-	getRole, ok := getRoleOptions.Operation.(*replica.GetRole)
-	if !ok {
-		return errors.New("getrole operation not found")
+	dbManager, err := register.GetDBManager()
+	if err != nil {
+		return errors.Wrap(err, "get manager failed")
+	}
 
+	if options.output == operations.FormatJSON {
+		info, err := engines.GetReplicaRoleInfo(context.Background(), dbManager)
+		if err != nil {
+			return errors.Wrap(err, "executing getrole failed")
+		}
+		data, err := json.Marshal(info)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+		return nil
 	}
 
-	role, err := getRole.DBManager.GetReplicaRole(context.Background())
+	role, err := dbManager.GetReplicaRole(context.Background())
 	if err != nil {
 		return errors.Wrap(err, "executing getrole failed")
 	}
@@ -59,12 +82,16 @@ var GetRoleCmd = &cobra.Command{
 	Short: "get role of the replica.",
 	Example: `
 dbctl database getrole 
+
+# print the normalized role, the native role, writability, voting membership and health hint
+dbctl database getrole -o json
   `,
 	Args: cobra.MinimumNArgs(0),
 	Run:  CmdRunner(getRoleOptions),
 }
 
 func init() {
+	GetRoleCmd.Flags().StringVarP(&getRoleOptions.output, "output", "o", "", "The output format, json for the structured role")
 	GetRoleCmd.Flags().BoolP("help", "h", false, "Print this help message")
 
 	DatabaseCmd.AddCommand(GetRoleCmd)
@@ -0,0 +1,49 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ctl
+
+import (
+	"io"
+
+	"github.com/spf13/pflag"
+
+	"github.com/apecloud/dbctl/engines/register"
+)
+
+// loadPlugins registers the engine plugins before the commands are parsed,
+// so that the engine types of the plugins are accepted as database subcommands.
+func loadPlugins(args []string) error {
+	fs := pflag.NewFlagSet("plugins", pflag.ContinueOnError)
+	fs.ParseErrorsWhitelist.UnknownFlags = true
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&pluginDir, "plugin-dir", "", "")
+	// errors of the other flags are reported when the commands are parsed
+	_ = fs.Parse(args)
+	if pluginDir == "" {
+		return nil
+	}
+
+	engineTypes, err := register.LoadPlugins(pluginDir)
+	if err != nil {
+		return err
+	}
+	DatabaseCmd.Aliases = append(DatabaseCmd.Aliases, engineTypes...)
+	return nil
+}
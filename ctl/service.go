@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package ctl
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"strings"
@@ -32,8 +33,15 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	kzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/register"
+	"github.com/apecloud/dbctl/ha"
 	"github.com/apecloud/dbctl/httpserver"
+	"github.com/apecloud/dbctl/kube"
+	opsfake "github.com/apecloud/dbctl/operations/fake"
 	opsregister "github.com/apecloud/dbctl/operations/register"
+	utilconfig "github.com/apecloud/dbctl/util/config"
 )
 
 var ServiceCmd = &cobra.Command{
@@ -41,6 +49,15 @@ var ServiceCmd = &cobra.Command{
 	Short: "Run dbctl as a daemon and provide api service.",
 	Example: `
 dbctl service
+
+# serve the redis sentinel in the same pod by /v1.0/sentinel/<operation>
+dbctl redis service --instance sentinel=redis
+
+# fail over the mysql replicas by the leader election on etcd
+dbctl mysql service --ha --ha-etcd-endpoints etcd-0.etcd-headless:2379
+
+# label the pod with its role, and emit the events of the pod
+dbctl postgresql service --role-label --k8s-events
   `,
 	Args: cobra.MinimumNArgs(0),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -51,6 +68,34 @@ dbctl service
 		}
 		ctrl.SetLogger(kzap.New(kOpts...))
 
+		// Initialize the DB managers of the named instances
+		instanceEngines := getInstanceEngines()
+		if err := initInstanceDBManagers(instanceEngines); err != nil {
+			panic(errors.Wrap(err, "DB manager initialize failed"))
+		}
+		// the fakecontrol operation is served only for the fake engine
+		if usesFakeEngine(instanceEngines) {
+			if err := opsfake.Register(); err != nil {
+				panic(errors.Wrap(err, "fakecontrol operation initialize failed"))
+			}
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// label the pod with the role, and emit the events of the pod
+		if kube.Enabled() {
+			dbManagers, err := getDBManagers(instanceEngines)
+			if err != nil {
+				panic(errors.Wrap(err, "kubernetes reporter initialize failed"))
+			}
+			reporter, err := kube.Start(ctx, dbManagers)
+			if err != nil {
+				panic(errors.Wrap(err, "kubernetes reporter initialize failed"))
+			}
+			httpserver.OnOperationFailure(reporter.ReportOperationFailure)
+		}
+
 		// start HTTP Server
 		ops := opsregister.Operations()
 		httpServer := httpserver.NewServer(ops)
@@ -59,14 +104,86 @@ dbctl service
 			panic(errors.Wrap(err, "HTTP server initialize failed"))
 		}
 
+		// reload the credentials when the mounted secrets are rotated
+		if err = utilconfig.WatchCredentials(ctx); err != nil {
+			panic(errors.Wrap(err, "credentials watcher initialize failed"))
+		}
+
+		// run the failover loop of the local database
+		if ha.Enabled() {
+			dbManager, err := register.GetDBManager()
+			if err != nil {
+				panic(errors.Wrap(err, "HA controller initialize failed"))
+			}
+			if err = ha.Start(ctx, engineType, dbManager); err != nil {
+				panic(errors.Wrap(err, "HA controller initialize failed"))
+			}
+		}
+
 		stop := make(chan os.Signal, 1)
 		signal.Notify(stop, syscall.SIGTERM, os.Interrupt)
 		<-stop
 	},
 }
 
+var instances map[string]string
+
+// getInstanceEngines returns the engine types of the named instances from the
+// config file and the --instance flags, where the flags take precedence.
+func getInstanceEngines() map[string]string {
+	instanceEngines := utilconfig.GetInstanceEngines()
+	for instance, engineType := range instances {
+		instanceEngines[instance] = engineType
+	}
+	return instanceEngines
+}
+
+// initInstanceDBManagers initializes the named instances.
+func initInstanceDBManagers(instanceEngines map[string]string) error {
+	for instance, engineType := range instanceEngines {
+		if err := register.InitInstanceDBManager(instance, engineType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getDBManagers returns the managers of the default instance and the named
+// instances, keyed by the instance name, empty for the default one.
+func getDBManagers(instanceEngines map[string]string) (map[string]engines.DBManager, error) {
+	dbManager, err := register.GetDBManager()
+	if err != nil {
+		return nil, err
+	}
+	dbManagers := map[string]engines.DBManager{"": dbManager}
+	for instance := range instanceEngines {
+		if dbManagers[instance], err = register.GetInstanceDBManager(instance); err != nil {
+			return nil, err
+		}
+	}
+	return dbManagers, nil
+}
+
+// usesFakeEngine tells whether the default instance or any named instance runs
+// the fake engine.
+func usesFakeEngine(instanceEngines map[string]string) bool {
+	if engineType == string(models.Fake) {
+		return true
+	}
+	for _, instanceEngine := range instanceEngines {
+		if instanceEngine == string(models.Fake) {
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
 	httpserver.InitFlags(ServiceCmd.Flags())
+	ha.InitFlags(ServiceCmd.Flags())
+	kube.InitFlags(ServiceCmd.Flags())
+	ServiceCmd.Flags().StringToStringVar(&instances, "instance", nil, "Named engine instances served besides the default one, e.g. --instance sentinel=redis. "+
+		"The operations of an instance are served by /v1.0/<instance>/<operation>, and its envs are prefixed with the upper case instance name, e.g. SENTINEL_.")
 	ServiceCmd.Flags().BoolP("help", "h", false, "Print this help message")
 
 	DatabaseCmd.AddCommand(ServiceCmd)
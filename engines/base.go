@@ -62,6 +62,22 @@ func (mgr *DBManagerBase) GetReplicaRole(context.Context) (string, error) {
 	return "", models.ErrNotImplemented
 }
 
+func (mgr *DBManagerBase) GetReplicationStatus(context.Context) (*models.ReplicationStatus, error) {
+	return nil, models.ErrNotImplemented
+}
+
+func (mgr *DBManagerBase) GetTopology(context.Context) (*models.Topology, error) {
+	return nil, models.ErrNotImplemented
+}
+
+func (mgr *DBManagerBase) GetMemberView(context.Context, string) (*models.MemberView, error) {
+	return nil, models.ErrNotImplemented
+}
+
+func (mgr *DBManagerBase) FenceMember(context.Context, string) error {
+	return models.ErrNotImplemented
+}
+
 func (mgr *DBManagerBase) Exec(context.Context, string) (int64, error) {
 	return 0, models.ErrNotImplemented
 }
@@ -0,0 +1,112 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package clickhouse
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/apecloud/dbctl/constant"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+const (
+	// configSection is the config file section of ClickHouse, the address is of
+	// the HTTP interface.
+	configSection = "clickhouse"
+
+	defaultHost       = "127.0.0.1"
+	defaultPort       = 8123
+	defaultTLSPort    = 8443
+	defaultKeeperPort = 9181
+	defaultUser       = "default"
+	defaultTimeout    = 10 * time.Second
+
+	// EnvAdminUser and EnvAdminPassword are the credentials of the bitnami images.
+	EnvAdminUser     = "CLICKHOUSE_ADMIN_USER"
+	EnvAdminPassword = "CLICKHOUSE_ADMIN_PASSWORD"
+	// EnvKeeperPort is the tcp_port of the keeper_server, which is embedded in the
+	// server if it's set for a server.
+	EnvKeeperPort = "CLICKHOUSE_KEEPER_TCP_PORT"
+)
+
+// the ClickHouse components, a keeper is a standalone ClickHouse Keeper.
+const (
+	Server = "server"
+	Keeper = "keeper"
+)
+
+type Config struct {
+	// Component is the component of the local pod, it's a keeper if the component
+	// name ends with keeper, e.g. ch-keeper.
+	Component string
+	// URL is the base URL of the HTTP interface.
+	URL      string
+	Database string
+	// KeeperAddr is the client port of the local keeper, empty if the server has
+	// no keeper embedded.
+	KeeperAddr string
+
+	TLSConfig *tls.Config
+	Timeout   time.Duration
+
+	*utilconfig.EngineCredentials
+}
+
+func NewConfig() (*Config, error) {
+	engineConfig, err := utilconfig.GetEngineConfig(configSection)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{
+		EngineCredentials: utilconfig.NewEngineCredentials(configSection,
+			utilconfig.CredentialLookup{Default: defaultUser, Name: constant.ConfigKeyUserName, Envs: []string{constant.KBEnvServiceUser, EnvAdminUser}},
+			utilconfig.CredentialLookup{Name: constant.ConfigKeyPassword, Envs: []string{constant.KBEnvServicePassword, EnvAdminPassword}}),
+		Component: Server,
+		Database:  engineConfig.Database,
+		Timeout:   defaultTimeout,
+	}
+	if strings.HasSuffix(constant.GetClusterCompName(), Keeper) {
+		config.Component = Keeper
+		config.KeeperAddr = fmt.Sprintf("%s:%d", defaultHost, defaultKeeperPort)
+	}
+	if viper.IsSet(EnvKeeperPort) {
+		config.KeeperAddr = fmt.Sprintf("%s:%d", defaultHost, viper.GetInt(EnvKeeperPort))
+	}
+
+	if config.TLSConfig, err = engineConfig.TLS.ClientConfig(); err != nil {
+		return nil, err
+	}
+	scheme, port := "http", defaultPort
+	if config.TLSConfig != nil {
+		scheme, port = "https", defaultTLSPort
+	}
+	config.URL = fmt.Sprintf("%s://%s", scheme, engineConfig.Addr(defaultHost, port))
+	if engineConfig.ReadTimeout != 0 {
+		config.Timeout = engineConfig.ReadTimeout
+	}
+	config.LoadCredentials(engineConfig)
+	return config, nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package clickhouse
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/zookeeper"
+)
+
+const (
+	// replicasSQL lists the replicated tables of the local server.
+	replicasSQL = "SELECT database, table, is_readonly, is_session_expired, queue_size, absolute_delay FROM system.replicas"
+
+	// maxAbsoluteDelay is the delay in seconds of a replica which is too stale to be
+	// queried, the same as the default max_replica_delay_for_distributed_queries.
+	maxAbsoluteDelay = 300
+	// maxQueueSize is the size of the replication queue of a replica which is
+	// falling behind in fetching the parts.
+	maxQueueSize = 100
+)
+
+// replicaStatus is a row of system.replicas.
+type replicaStatus struct {
+	Database         string `json:"database"`
+	Table            string `json:"table"`
+	IsReadonly       uint8  `json:"is_readonly"`
+	IsSessionExpired uint8  `json:"is_session_expired"`
+	QueueSize        uint32 `json:"queue_size"`
+	AbsoluteDelay    uint64 `json:"absolute_delay"`
+}
+
+// GetReplicaRole returns the server state of the local keeper by mntr, one of
+// leader, follower, observer and standalone. The replicas of ClickHouse are all
+// writable, so a server without the keeper embedded has no role.
+func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
+	if mgr.config.KeeperAddr == "" {
+		return "", models.ErrNotImplemented
+	}
+	return mgr.getKeeperState(ctx)
+}
+
+// GetReplicaRoleInfo returns the role of the keeper, and the health of the
+// replicated tables of a server from system.replicas: unhealthy and not writable
+// if any is read-only, e.g. on the expired keeper session, or recovering if any
+// is more than maxAbsoluteDelay seconds behind, or has more than maxQueueSize
+// entries queued.
+func (mgr *Manager) GetReplicaRoleInfo(ctx context.Context) (*models.RoleInfo, error) {
+	info := models.NewRoleInfo("")
+	if mgr.config.KeeperAddr != "" {
+		state, err := mgr.getKeeperState(ctx)
+		if err != nil {
+			return nil, err
+		}
+		info = zookeeper.NewRoleInfo(state)
+	}
+	if mgr.config.Component == Keeper {
+		return info, nil
+	}
+
+	data, err := mgr.query(ctx, replicasSQL)
+	if err != nil {
+		return nil, err
+	}
+	var replicas []replicaStatus
+	if err = json.Unmarshal(data, &replicas); err != nil {
+		return nil, errors.Wrap(err, "decode system.replicas failed")
+	}
+	info.Writable, info.Health = replicaHealth(replicas)
+	return info, nil
+}
+
+func replicaHealth(replicas []replicaStatus) (bool, string) {
+	health := models.HealthHealthy
+	for _, replica := range replicas {
+		if replica.IsReadonly != 0 || replica.IsSessionExpired != 0 {
+			return false, models.HealthUnhealthy
+		}
+		if replica.AbsoluteDelay > maxAbsoluteDelay || replica.QueueSize > maxQueueSize {
+			health = models.HealthRecovering
+		}
+	}
+	return true, health
+}
+
+// getKeeperState returns zk_server_state of mntr.
+func (mgr *Manager) getKeeperState(ctx context.Context) (string, error) {
+	resp, err := mgr.fourLetterWord(ctx, "mntr")
+	if err != nil {
+		return "", err
+	}
+	state, ok := parseMntr(resp)["zk_server_state"]
+	if !ok {
+		return "", errors.Errorf("zk_server_state is not found in the response of mntr: %s", resp)
+	}
+	return state, nil
+}
+
+// parseMntr parses the tab-separated lines of mntr.
+func parseMntr(resp string) map[string]string {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(resp))
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), "\t"); ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,184 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package clickhouse
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/zookeeper"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+const (
+	pingPath = "/ping"
+	// pingResponse is the response of /ping of a server which accepts queries.
+	pingResponse = "Ok."
+	// keeperOK is the response of ruok of a keeper which serves requests.
+	keeperOK = "imok"
+)
+
+// Manager runs the SQL by the HTTP interface of the local server, and probes the
+// local keeper by the four-letter words.
+type Manager struct {
+	engines.DBManagerBase
+	config *Config
+	client *http.Client
+}
+
+var _ engines.DBManager = &Manager{}
+
+func NewManager() (engines.DBManager, error) {
+	logger := ctrl.Log.WithName("ClickHouse")
+	config, err := NewConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	managerBase, err := engines.NewDBManagerBase(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := &Manager{
+		DBManagerBase: *managerBase,
+		config:        config,
+		client: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: &http.Transport{TLSClientConfig: config.TLSConfig},
+		},
+	}
+
+	utilconfig.OnCredentialsChange(mgr.reloadCredentials)
+	return mgr, nil
+}
+
+// reloadCredentials reloads the credentials, which are read for every request.
+func (mgr *Manager) reloadCredentials() {
+	if err := mgr.config.ReloadCredentials(); err != nil {
+		mgr.Logger.Info("reload credentials failed", "error", err.Error())
+		return
+	}
+	mgr.Logger.Info("credentials reloaded")
+}
+
+// IsDBStartupReady checks /ping of a server, or ruok of a keeper.
+func (mgr *Manager) IsDBStartupReady() bool {
+	if mgr.DBStartupReady {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mgr.config.Timeout)
+	defer cancel()
+	var resp string
+	var err error
+	expected := pingResponse
+	if mgr.config.Component == Keeper {
+		expected = keeperOK
+		resp, err = mgr.fourLetterWord(ctx, "ruok")
+	} else {
+		resp, err = mgr.ping(ctx)
+	}
+	if err == nil && resp != expected {
+		err = errors.Errorf("unexpected response %s", resp)
+	}
+	if err != nil {
+		mgr.Logger.Info("DB is not ready", "error", err.Error())
+		return false
+	}
+
+	mgr.DBStartupReady = true
+	mgr.Logger.Info("DB startup ready")
+	return true
+}
+
+func (mgr *Manager) ping(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mgr.config.URL+pingPath, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := mgr.client.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "GET %s failed", pingPath)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrapf(err, "read the response of %s failed", pingPath)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// request runs the SQL, the numbers in the results of the JSON formats are not
+// quoted, and the error is the exception ClickHouse responds with.
+func (mgr *Manager) request(ctx context.Context, sql string) ([]byte, http.Header, error) {
+	params := url.Values{}
+	params.Set("default_format", "JSON")
+	params.Set("output_format_json_quote_64bit_integers", "0")
+	params.Set("wait_end_of_query", "1")
+	if mgr.config.Database != "" {
+		params.Set("database", mgr.config.Database)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, mgr.config.URL+"/?"+params.Encode(), strings.NewReader(sql))
+	if err != nil {
+		return nil, nil, err
+	}
+	username, password := mgr.config.GetCredentials()
+	req.Header.Set("X-ClickHouse-User", username)
+	if password != "" {
+		req.Header.Set("X-ClickHouse-Key", password)
+	}
+
+	resp, err := mgr.client.Do(req)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "request ClickHouse failed")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "read the response of ClickHouse failed")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, errors.Errorf("request ClickHouse failed, status %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, resp.Header, nil
+}
+
+func (mgr *Manager) fourLetterWord(ctx context.Context, word string) (string, error) {
+	if mgr.config.KeeperAddr == "" {
+		return "", errors.Errorf("no keeper is embedded, set %s if the keeper is enabled", EnvKeeperPort)
+	}
+	return zookeeper.FourLetterWord(ctx, mgr.config.KeeperAddr, nil, mgr.config.Timeout, word)
+}
+
+func (mgr *Manager) ShutDownWithWait() {
+	mgr.client.CloseIdleConnections()
+}
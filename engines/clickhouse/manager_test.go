@@ -0,0 +1,252 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package clickhouse
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/conformance"
+	"github.com/apecloud/dbctl/engines/models"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+const mntrFollower = "zk_version\tv24.3.1.2672-lts\nzk_avg_latency\t0\nzk_server_state\tfollower\nzk_znode_count\t12\n"
+
+// mockServer serves the HTTP interface, the SQL are answered by the responses
+// and recorded.
+type mockServer struct {
+	ready     bool
+	responses map[string]string
+	summary   string
+	sqls      []string
+}
+
+func (m *mockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == pingPath {
+		if !m.ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("Ok.\n"))
+		return
+	}
+	if r.Header.Get("X-ClickHouse-User") != "admin" || r.Header.Get("X-ClickHouse-Key") != "secret" {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("Code: 516. DB::Exception: admin: Authentication failed. (AUTHENTICATION_FAILED)"))
+		return
+	}
+	if r.URL.Query().Get("output_format_json_quote_64bit_integers") != "0" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	sql := string(body)
+	m.sqls = append(m.sqls, sql)
+	resp, ok := m.responses[sql]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("Code: 60. DB::Exception: Table default.missing does not exist. (UNKNOWN_TABLE)"))
+		return
+	}
+	if m.summary != "" {
+		w.Header().Set(summaryHeader, m.summary)
+	}
+	_, _ = w.Write([]byte(resp))
+}
+
+// serveFourLetterWords serves the four-letter words of a keeper on a local port.
+func serveFourLetterWords(t *testing.T, responses map[string]string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			word := make([]byte, 4)
+			if _, err = conn.Read(word); err == nil {
+				_, _ = conn.Write([]byte(responses[string(word)]))
+			}
+			_ = conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func mockManager(t *testing.T) (*Manager, *mockServer) {
+	server := &mockServer{ready: true, responses: map[string]string{}}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	mgr := &Manager{
+		DBManagerBase: engines.DBManagerBase{
+			CurrentMemberName: "clickhouse-0",
+			Logger:            ctrl.Log.WithName("ClickHouse-TEST"),
+		},
+		config: &Config{
+			Component:         Server,
+			URL:               httpServer.URL,
+			Timeout:           time.Second,
+			EngineCredentials: utilconfig.NewEngineCredentials(configSection, utilconfig.CredentialLookup{Default: "admin"}, utilconfig.CredentialLookup{Default: "secret"}),
+		},
+		client: httpServer.Client(),
+	}
+	return mgr, server
+}
+
+func TestIsDBStartupReady(t *testing.T) {
+	mgr, server := mockManager(t)
+
+	server.ready = false
+	assert.False(t, mgr.IsDBStartupReady())
+	server.ready = true
+	assert.True(t, mgr.IsDBStartupReady())
+
+	// a keeper is probed by ruok
+	mgr, _ = mockManager(t)
+	mgr.config.Component = Keeper
+	mgr.config.KeeperAddr = serveFourLetterWords(t, map[string]string{})
+	assert.False(t, mgr.IsDBStartupReady())
+	mgr.config.KeeperAddr = serveFourLetterWords(t, map[string]string{"ruok": "imok"})
+	assert.True(t, mgr.IsDBStartupReady())
+}
+
+func TestGetReplicaRole(t *testing.T) {
+	ctx := context.TODO()
+	mgr, _ := mockManager(t)
+
+	_, err := mgr.GetReplicaRole(ctx)
+	assert.ErrorIs(t, err, models.ErrNotImplemented)
+
+	mgr.config.KeeperAddr = serveFourLetterWords(t, map[string]string{"mntr": mntrFollower})
+	role, err := mgr.GetReplicaRole(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.FOLLOWER, role)
+}
+
+func TestGetReplicaRoleInfo(t *testing.T) {
+	ctx := context.TODO()
+	mgr, server := mockManager(t)
+	mgr.config.KeeperAddr = serveFourLetterWords(t, map[string]string{"mntr": mntrFollower})
+
+	setReplicas := func(rows string) {
+		server.responses[replicasSQL] = `{"meta":[],"data":[` + rows + `],"rows":1}`
+	}
+	setReplicas(`{"database":"default","table":"events","is_readonly":0,"is_session_expired":0,"queue_size":3,"absolute_delay":1}`)
+	info, err := mgr.GetReplicaRoleInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &models.RoleInfo{
+		Role:       models.FOLLOWER,
+		NativeRole: "follower",
+		Writable:   true,
+		Voter:      true,
+		Health:     models.HealthHealthy,
+	}, info)
+
+	setReplicas(`{"database":"default","table":"events","is_readonly":0,"is_session_expired":0,"queue_size":3,"absolute_delay":600}`)
+	info, err = mgr.GetReplicaRoleInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.HealthRecovering, info.Health)
+
+	setReplicas(`{"database":"default","table":"events","is_readonly":1,"is_session_expired":1,"queue_size":0,"absolute_delay":0}`)
+	info, err = mgr.GetReplicaRoleInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.HealthUnhealthy, info.Health)
+	assert.False(t, info.Writable)
+
+	// a server without the keeper embedded has no role
+	mgr.config.KeeperAddr = ""
+	setReplicas("")
+	info, err = mgr.GetReplicaRoleInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &models.RoleInfo{Writable: true, Health: models.HealthHealthy}, info)
+}
+
+func TestQuery(t *testing.T) {
+	ctx := context.TODO()
+	mgr, server := mockManager(t)
+
+	server.responses["SELECT id, name, tags FROM events"] = `{
+	"meta": [{"name": "id", "type": "UInt64"}, {"name": "name", "type": "Nullable(String)"}, {"name": "tags", "type": "Array(String)"}],
+	"data": [{"id": 18446744073709551615, "name": null, "tags": ["a", "b"]}],
+	"rows": 1
+}`
+	data, err := mgr.Query(ctx, "SELECT id, name, tags FROM events")
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id": 18446744073709551615, "name": null, "tags": ["a", "b"]}]`, string(data))
+
+	server.responses["SELECT 1 FORMAT CSV"] = "1\n"
+	_, err = mgr.Query(ctx, "SELECT 1 FORMAT CSV")
+	assert.ErrorContains(t, err, "remove the FORMAT clause")
+
+	_, err = mgr.Query(ctx, "SELECT * FROM missing")
+	assert.ErrorContains(t, err, "UNKNOWN_TABLE")
+
+	mgr.config.SetCredentials("admin", "wrong")
+	_, err = mgr.Query(ctx, "SELECT id, name, tags FROM events")
+	assert.ErrorContains(t, err, "AUTHENTICATION_FAILED")
+}
+
+func TestExec(t *testing.T) {
+	ctx := context.TODO()
+	mgr, server := mockManager(t)
+
+	server.responses["INSERT INTO events VALUES (1, 'a', []), (2, 'b', [])"] = ""
+	server.summary = `{"read_rows":"2","read_bytes":"40","written_rows":"2","written_bytes":"40","total_rows_to_read":"0"}`
+	n, err := mgr.Exec(ctx, "INSERT INTO events VALUES (1, 'a', []), (2, 'b', [])")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	server.responses["OPTIMIZE TABLE events FINAL"] = ""
+	server.summary = ""
+	n, err = mgr.Exec(ctx, "OPTIMIZE TABLE events FINAL")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+}
+
+func TestConformance(t *testing.T) {
+	mgr, server := mockManager(t)
+	mgr.config.KeeperAddr = serveFourLetterWords(t, map[string]string{"mntr": mntrFollower})
+	server.responses["INSERT INTO events VALUES (1, 'a', [])"] = ""
+	server.responses["SELECT 1"] = `{"meta":[{"name":"1","type":"UInt8"}],"data":[{"1":1}],"rows":1}`
+	server.responses[replicasSQL] = `{"meta":[],"data":[],"rows":0}`
+
+	conformance.Run(t, conformance.Suite{
+		Manager:  mgr,
+		ExecSQL:  "INSERT INTO events VALUES (1, 'a', [])",
+		QuerySQL: "SELECT 1",
+	})
+}
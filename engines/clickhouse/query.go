@@ -0,0 +1,75 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package clickhouse
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// summaryHeader is the progress of the query, which has the written rows.
+const summaryHeader = "X-ClickHouse-Summary"
+
+// jsonResult is the result in the JSON format, the values of the rows are typed
+// by the columns, e.g. a Nullable is null and an Array is an array.
+type jsonResult struct {
+	Data json.RawMessage `json:"data"`
+}
+
+// Query returns the rows in JSON, keyed by the column names. The SQL must not
+// have a FORMAT clause, the results are in the JSON format.
+func (mgr *Manager) Query(ctx context.Context, sql string) ([]byte, error) {
+	return mgr.query(ctx, sql)
+}
+
+func (mgr *Manager) query(ctx context.Context, sql string) ([]byte, error) {
+	data, _, err := mgr.request(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	// the statements without results, e.g. DDLs, respond nothing
+	if len(data) == 0 {
+		return []byte("[]"), nil
+	}
+	result := &jsonResult{}
+	if err = json.Unmarshal(data, result); err != nil || result.Data == nil {
+		return nil, errors.Errorf("the result is not in the JSON format, remove the FORMAT clause: %.100s", data)
+	}
+	return result.Data, nil
+}
+
+// Exec returns the written rows of X-ClickHouse-Summary, which is zero for the
+// statements other than INSERTs.
+func (mgr *Manager) Exec(ctx context.Context, sql string) (int64, error) {
+	_, header, err := mgr.request(ctx, sql)
+	if err != nil {
+		return 0, err
+	}
+	// the summary is sent by ClickHouse 22.x and later only, and nothing is
+	// reported without it
+	summary := map[string]string{}
+	if json.Unmarshal([]byte(header.Get(summaryHeader)), &summary) != nil {
+		return 0, nil
+	}
+	return cast.ToInt64(summary["written_rows"]), nil
+}
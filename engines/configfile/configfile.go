@@ -0,0 +1,159 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configfile
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// Parameter is a parameter in the config files, with the file it's set in last.
+type Parameter struct {
+	Value string
+	File  string
+}
+
+// Config is the parameters parsed from a config file and the files it includes.
+type Config struct {
+	// Files are the parsed files in order, the main file first.
+	Files      []string
+	Parameters map[string]Parameter
+}
+
+func newConfig() *Config {
+	return &Config{Parameters: map[string]Parameter{}}
+}
+
+func (c *Config) set(name, value, file string) {
+	c.Parameters[name] = Parameter{Value: value, File: file}
+}
+
+// EqualFunc tells whether the value in the file takes effect as the live value.
+type EqualFunc func(name, fileValue, liveValue string) bool
+
+// Diff compares the parameters in the config with the live values, the parameters
+// the database doesn't report are listed as unknown.
+func Diff(config *Config, live map[string]string, equal EqualFunc) *models.ConfigDriftReport {
+	names := make([]string, 0, len(config.Parameters))
+	for name := range config.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	report := &models.ConfigDriftReport{
+		Files:  config.Files,
+		Drifts: []models.ParameterDrift{},
+	}
+	for _, name := range names {
+		param := config.Parameters[name]
+		liveValue, ok := live[name]
+		if !ok {
+			report.Unknown = append(report.Unknown, name)
+			continue
+		}
+		if equal(name, param.Value, liveValue) {
+			continue
+		}
+		report.Drifts = append(report.Drifts, models.ParameterDrift{
+			Name:      name,
+			FileValue: param.Value,
+			LiveValue: liveValue,
+			File:      param.File,
+		})
+	}
+	return report
+}
+
+// ParseBool parses the boolean words of the config files.
+func ParseBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "on", "true", "yes", "1":
+		return true, true
+	case "off", "false", "no", "0":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// ParseSize parses a number with an optional unit suffix, the units are matched
+// case-insensitively.
+func ParseSize(value string, units map[string]float64) (float64, bool) {
+	value = strings.TrimSpace(value)
+	i := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != '-' && r != '+'
+	})
+	number, unit := value, ""
+	if i >= 0 {
+		number, unit = value[:i], strings.ToLower(strings.TrimSpace(value[i:]))
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, false
+	}
+	if unit == "" {
+		return n, true
+	}
+	multiplier, ok := units[unit]
+	if !ok {
+		return 0, false
+	}
+	return n * multiplier, true
+}
+
+// EqualValues compares the values as strings, sizes or booleans, the values are
+// equal if they are equal in any form.
+func EqualValues(a, b string, units map[string]float64) bool {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	if x, ok := ParseSize(a, units); ok {
+		if y, ok := ParseSize(b, units); ok {
+			return x == y
+		}
+	}
+	if x, ok := ParseBool(a); ok {
+		if y, ok := ParseBool(b); ok {
+			return x == y
+		}
+	}
+	return false
+}
+
+// Unquote strips the single or double quotes around the value.
+func Unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// resolvePath resolves the path included by the file relative to its directory.
+func resolvePath(file, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(file), path)
+}
@@ -0,0 +1,196 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func writeFile(t *testing.T, path, content string) string {
+	assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestParseMyCnf(t *testing.T) {
+	dir := t.TempDir()
+	main := writeFile(t, filepath.Join(dir, "my.cnf"), `
+# comment
+[client]
+port = 3307
+
+[mysqld]
+port = 3306
+max-connections = 100 # inline comment
+skip-name-resolve
+loose-rpl_semi_sync_source_enabled = ON
+sql_mode = "STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION"
+
+!include extra.cnf
+!includedir conf.d
+`)
+	writeFile(t, filepath.Join(dir, "extra.cnf"), "[mysqld]\ninnodb_buffer_pool_size=1G\n")
+	writeFile(t, filepath.Join(dir, "conf.d", "b.cnf"), "[mysqld-8.0]\nmax_connections=300\n")
+	writeFile(t, filepath.Join(dir, "conf.d", "a.cnf"), "[mysqld]\nmax_connections=200\n!include ../my.cnf\n")
+	writeFile(t, filepath.Join(dir, "conf.d", "ignored.txt"), "[mysqld]\nport=1\n")
+
+	config, err := ParseMyCnf(main)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{main, filepath.Join(dir, "extra.cnf"), filepath.Join(dir, "conf.d", "a.cnf"), filepath.Join(dir, "conf.d", "b.cnf")}, config.Files)
+	assert.Equal(t, map[string]Parameter{
+		"port":                         {Value: "3306", File: main},
+		"max_connections":              {Value: "300", File: filepath.Join(dir, "conf.d", "b.cnf")},
+		"skip_name_resolve":            {Value: "ON", File: main},
+		"rpl_semi_sync_source_enabled": {Value: "ON", File: main},
+		"sql_mode":                     {Value: "STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION", File: main},
+		"innodb_buffer_pool_size":      {Value: "1G", File: filepath.Join(dir, "extra.cnf")},
+	}, config.Parameters)
+
+	_, err = ParseMyCnf(filepath.Join(dir, "missing.cnf"))
+	assert.NotNil(t, err)
+}
+
+func TestParsePostgresqlConf(t *testing.T) {
+	dir := t.TempDir()
+	main := writeFile(t, filepath.Join(dir, "postgresql.conf"), `
+# comment
+shared_buffers = 128MB # inline comment
+max_connections 100
+log_line_prefix = '%m [%p] ''quoted'' # not a comment'
+include 'extra.conf'
+include_if_exists 'missing.conf'
+include_dir 'conf.d'
+`)
+	writeFile(t, filepath.Join(dir, "extra.conf"), "work_mem = '4MB'\n")
+	writeFile(t, filepath.Join(dir, "conf.d", "01.conf"), "max_connections = 200\n")
+	writeFile(t, filepath.Join(dir, "conf.d", ".hidden.conf"), "max_connections = 1\n")
+	auto := writeFile(t, filepath.Join(dir, "postgresql.auto.conf"), "work_mem = '64MB'\n")
+
+	config, err := ParsePostgresqlConf(main, auto)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{main, filepath.Join(dir, "extra.conf"), filepath.Join(dir, "conf.d", "01.conf"), auto}, config.Files)
+	assert.Equal(t, map[string]Parameter{
+		"shared_buffers":  {Value: "128MB", File: main},
+		"max_connections": {Value: "200", File: filepath.Join(dir, "conf.d", "01.conf")},
+		"log_line_prefix": {Value: "%m [%p] 'quoted' # not a comment", File: main},
+		"work_mem":        {Value: "64MB", File: auto},
+	}, config.Parameters)
+
+	t.Run("recursive include", func(t *testing.T) {
+		loop := writeFile(t, filepath.Join(dir, "loop.conf"), "include 'loop.conf'\n")
+		_, err := ParsePostgresqlConf(loop)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestParseRedisConf(t *testing.T) {
+	dir := t.TempDir()
+	main := writeFile(t, filepath.Join(dir, "redis.conf"), `
+# comment
+port 6379
+maxmemory 1gb
+save 3600 1
+save 300 100
+requirepass "pass word\"quoted"
+include `+filepath.Join(dir, "conf.d", "*.conf")+`
+`)
+	writeFile(t, filepath.Join(dir, "conf.d", "extra.conf"), "MaxMemory-Policy allkeys-lru\n")
+
+	config, err := ParseRedisConf(main)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{main, filepath.Join(dir, "conf.d", "extra.conf")}, config.Files)
+	assert.Equal(t, "6379", config.Parameters["port"].Value)
+	assert.Equal(t, "3600 1 300 100", config.Parameters["save"].Value)
+	assert.Equal(t, `pass word"quoted`, config.Parameters["requirepass"].Value)
+	assert.Equal(t, Parameter{Value: "allkeys-lru", File: filepath.Join(dir, "conf.d", "extra.conf")}, config.Parameters["maxmemory-policy"])
+}
+
+func TestParseMongodConf(t *testing.T) {
+	path := writeFile(t, filepath.Join(t.TempDir(), "mongod.conf"), `
+net:
+  port: 27017
+storage:
+  wiredTiger:
+    engineConfig:
+      cacheSizeGB: 0.5
+setParameter:
+  enableLocalhostAuthBypass: false
+  ttlMonitorSleepSecs: 60
+`)
+
+	config, err := ParseMongodConf(path)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]Parameter{
+		"net.port": {Value: "27017", File: path},
+		"storage.wiredTiger.engineConfig.cacheSizeGB": {Value: "0.5", File: path},
+		"setParameter.enableLocalhostAuthBypass":      {Value: "false", File: path},
+		"setParameter.ttlMonitorSleepSecs":            {Value: "60", File: path},
+	}, config.Parameters)
+}
+
+func TestDiff(t *testing.T) {
+	units := map[string]float64{"k": 1024, "m": 1024 * 1024, "g": 1024 * 1024 * 1024}
+	config := &Config{
+		Files: []string{"my.cnf"},
+		Parameters: map[string]Parameter{
+			"innodb_buffer_pool_size": {Value: "1G", File: "my.cnf"},
+			"max_connections":         {Value: "200", File: "my.cnf"},
+			"skip_name_resolve":       {Value: "ON", File: "my.cnf"},
+			"log_bin":                 {Value: "1", File: "my.cnf"},
+			"foo":                     {Value: "bar", File: "my.cnf"},
+		},
+	}
+	live := map[string]string{
+		"innodb_buffer_pool_size": "1073741824",
+		"max_connections":         "151",
+		"skip_name_resolve":       "ON",
+		"log_bin":                 "ON",
+	}
+
+	report := Diff(config, live, func(_, fileValue, liveValue string) bool {
+		return EqualValues(fileValue, liveValue, units)
+	})
+	assert.Equal(t, []models.ParameterDrift{
+		{Name: "max_connections", FileValue: "200", LiveValue: "151", File: "my.cnf"},
+	}, report.Drifts)
+	assert.Equal(t, []string{"foo"}, report.Unknown)
+}
+
+func TestParseSize(t *testing.T) {
+	units := map[string]float64{"kb": 1024, "mb": 1024 * 1024}
+	size, ok := ParseSize("128MB", units)
+	assert.True(t, ok)
+	assert.Equal(t, float64(128*1024*1024), size)
+
+	size, ok = ParseSize("16384", units)
+	assert.True(t, ok)
+	assert.Equal(t, float64(16384), size)
+
+	_, ok = ParseSize("128XB", units)
+	assert.False(t, ok)
+	_, ok = ParseSize("on", units)
+	assert.False(t, ok)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configfile
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// ParseMongodConf parses the YAML mongod.conf, the nested options are flattened
+// to the dotted names, e.g. storage.wiredTiger.engineConfig.cacheSizeGB, and
+// setParameter.<name> for the server parameters.
+func ParseMongodConf(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read mongod.conf failed")
+	}
+	var options map[string]any
+	if err = yaml.Unmarshal(data, &options); err != nil {
+		return nil, errors.Wrap(err, "parse mongod.conf failed")
+	}
+
+	config := newConfig()
+	config.Files = []string{path}
+	flatten(config, "", options, path)
+	return config, nil
+}
+
+func flatten(config *Config, prefix string, options map[string]any, path string) {
+	for key, value := range options {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]any); ok {
+			flatten(config, name, nested, path)
+			continue
+		}
+		config.set(name, fmt.Sprint(value), path)
+	}
+}
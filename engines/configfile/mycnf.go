@@ -0,0 +1,114 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configfile
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ParseMyCnf parses the options of the mysqld and server groups in my.cnf, and the
+// files included by `!include` and `!includedir`. The option names are normalized
+// to the variable names, e.g. loose-max-connections to max_connections.
+func ParseMyCnf(path string) (*Config, error) {
+	config := newConfig()
+	if err := parseMyCnf(config, path, map[string]bool{}); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func parseMyCnf(config *Config, path string, visited map[string]bool) error {
+	if visited[path] {
+		return nil
+	}
+	visited[path] = true
+	config.Files = append(config.Files, path)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "open my.cnf failed")
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	// the options of an included file belong to the groups it declares
+	group := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+		case strings.HasPrefix(line, "!includedir"):
+			dir := resolvePath(path, strings.TrimSpace(strings.TrimPrefix(line, "!includedir")))
+			files, err := filepath.Glob(filepath.Join(dir, "*.cnf"))
+			if err != nil {
+				return errors.Wrapf(err, "list %s failed", dir)
+			}
+			sort.Strings(files)
+			for _, f := range files {
+				if err = parseMyCnf(config, f, visited); err != nil {
+					return err
+				}
+			}
+		case strings.HasPrefix(line, "!include"):
+			included := resolvePath(path, strings.TrimSpace(strings.TrimPrefix(line, "!include")))
+			if err = parseMyCnf(config, included, visited); err != nil {
+				return err
+			}
+		case line[0] == '[':
+			group = strings.ToLower(strings.TrimSpace(strings.Trim(line, "[]")))
+		case isMysqldGroup(group):
+			name, value := parseMyCnfOption(line)
+			config.set(name, value, path)
+		}
+	}
+	return errors.Wrapf(scanner.Err(), "read %s failed", path)
+}
+
+func isMysqldGroup(group string) bool {
+	return group == "mysqld" || group == "server" || strings.HasPrefix(group, "mysqld-")
+}
+
+// parseMyCnfOption parses `name = value`, an option without a value is a switch
+// turned on.
+func parseMyCnfOption(line string) (string, string) {
+	name, value, found := strings.Cut(line, "=")
+	name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_")
+	name = strings.TrimPrefix(name, "loose_")
+	if !found {
+		return name, "ON"
+	}
+
+	value = strings.TrimSpace(value)
+	if unquoted := Unquote(value); unquoted != value {
+		return name, unquoted
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return name, value
+}
@@ -0,0 +1,137 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configfile
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ParsePostgresqlConf parses postgresql.conf, and the files included by `include`,
+// `include_if_exists` and `include_dir`. The files are parsed in order, so the later
+// ones override the former, e.g. postgresql.auto.conf after postgresql.conf.
+func ParsePostgresqlConf(paths ...string) (*Config, error) {
+	config := newConfig()
+	for _, path := range paths {
+		if err := parsePostgresqlConf(config, path, map[string]bool{}); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+func parsePostgresqlConf(config *Config, path string, visited map[string]bool) error {
+	if visited[path] {
+		return errors.Errorf("%s is included recursively", path)
+	}
+	visited[path] = true
+	defer delete(visited, path)
+	config.Files = append(config.Files, path)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "open postgresql.conf failed")
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		name, value, ok := parsePostgresqlConfLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		switch name {
+		case "include", "include_if_exists":
+			included := resolvePath(path, value)
+			if _, err = os.Stat(included); os.IsNotExist(err) && name == "include_if_exists" {
+				continue
+			}
+			if err = parsePostgresqlConf(config, included, visited); err != nil {
+				return err
+			}
+		case "include_dir":
+			dir := resolvePath(path, value)
+			files, err := filepath.Glob(filepath.Join(dir, "*.conf"))
+			if err != nil {
+				return errors.Wrapf(err, "list %s failed", dir)
+			}
+			sort.Strings(files)
+			for _, f := range files {
+				if strings.HasPrefix(filepath.Base(f), ".") {
+					continue
+				}
+				if err = parsePostgresqlConf(config, f, visited); err != nil {
+					return err
+				}
+			}
+		default:
+			config.set(name, value, path)
+		}
+	}
+	return errors.Wrapf(scanner.Err(), "read %s failed", path)
+}
+
+// parsePostgresqlConfLine parses `name = value` or `name value`, the value may be
+// single quoted, in which a quote is escaped by doubling or a backslash.
+func parsePostgresqlConfLine(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || line[0] == '#' {
+		return "", "", false
+	}
+	i := strings.IndexFunc(line, func(r rune) bool {
+		return r == '=' || r == ' ' || r == '\t'
+	})
+	if i < 0 {
+		return "", "", false
+	}
+	name := strings.ToLower(line[:i])
+	rest := strings.TrimSpace(line[i:])
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, "="))
+
+	if !strings.HasPrefix(rest, "'") {
+		value, _, _ := strings.Cut(rest, "#")
+		return name, strings.TrimSpace(value), true
+	}
+
+	var value strings.Builder
+	for j := 1; j < len(rest); j++ {
+		switch {
+		case rest[j] == '\\' && j+1 < len(rest):
+			j++
+			value.WriteByte(rest[j])
+		case rest[j] == '\'' && j+1 < len(rest) && rest[j+1] == '\'':
+			j++
+			value.WriteByte('\'')
+		case rest[j] == '\'':
+			return name, value.String(), true
+		default:
+			value.WriteByte(rest[j])
+		}
+	}
+	// the quote is not closed
+	return "", "", false
+}
@@ -0,0 +1,131 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configfile
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// redisMultiDirectives are the directives whose occurrences are accumulated, as
+// CONFIG GET reports them in one value.
+var redisMultiDirectives = map[string]bool{
+	"save":                       true,
+	"client-output-buffer-limit": true,
+}
+
+// ParseRedisConf parses redis.conf, and the files included by `include`, which
+// may be a glob pattern.
+func ParseRedisConf(path string) (*Config, error) {
+	config := newConfig()
+	if err := parseRedisConf(config, path, map[string]bool{}); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func parseRedisConf(config *Config, path string, visited map[string]bool) error {
+	if visited[path] {
+		return nil
+	}
+	visited[path] = true
+	config.Files = append(config.Files, path)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "open redis.conf failed")
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		args := splitRedisArgs(line)
+		if len(args) == 0 {
+			continue
+		}
+		name, value := strings.ToLower(args[0]), strings.Join(args[1:], " ")
+		switch {
+		case name == "include":
+			files, err := filepath.Glob(resolvePath(path, value))
+			if err != nil {
+				return errors.Wrapf(err, "include %s failed", value)
+			}
+			for _, f := range files {
+				if err = parseRedisConf(config, f, visited); err != nil {
+					return err
+				}
+			}
+		case redisMultiDirectives[name] && value != "":
+			if param, ok := config.Parameters[name]; ok && param.Value != "" {
+				value = param.Value + " " + value
+			}
+			config.set(name, value, path)
+		default:
+			config.set(name, value, path)
+		}
+	}
+	return errors.Wrapf(scanner.Err(), "read %s failed", path)
+}
+
+// splitRedisArgs splits the line by spaces, an argument may be quoted by double
+// quotes with escapes, or by single quotes.
+func splitRedisArgs(line string) []string {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0 && c == '\\' && i+1 < len(line):
+			i++
+			arg.WriteByte(line[i])
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteByte(c)
+		case c == '"' || c == '\'':
+			quote, inArg = c, true
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args
+}
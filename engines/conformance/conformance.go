@@ -0,0 +1,231 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package conformance checks that an engines.DBManager behaves the same as the
+// other engines, it's meant to be run by the tests of each engine against a
+// local stand-in of the database, e.g. sqlmock, pgxmock, miniredis, httptest,
+// kfake or an embedded etcd.
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+type Method string
+
+const (
+	IsDBStartupReady   Method = "IsDBStartupReady"
+	GetReplicaRole     Method = "GetReplicaRole"
+	GetReplicaRoleInfo Method = "GetReplicaRoleInfo"
+	Exec               Method = "Exec"
+	Query              Method = "Query"
+
+	defaultTimeout = 5 * time.Second
+)
+
+// RoleVocabulary are the normalized roles of engines.GetReplicaRoleInfo, which
+// the native role of GetReplicaRole is normalized to.
+var RoleVocabulary = []string{
+	models.PRIMARY,
+	models.SECONDARY,
+	models.LEADER,
+	models.FOLLOWER,
+	models.LEARNER,
+	models.CANDIDATE,
+	models.ARBITER,
+}
+
+// Suite describes the manager under test and its stand-in.
+type Suite struct {
+	// Manager is the manager under test, connected to the stand-in.
+	Manager engines.DBManager
+	// Prepare primes the stand-in before the method is called, e.g. sets the
+	// sqlmock expectations. It can be nil if the stand-in needs nothing.
+	Prepare func(method Method)
+	// ExecSQL and QuerySQL are the statements of Exec and Query.
+	ExecSQL  string
+	QuerySQL string
+	// NotImplemented are the methods the engine doesn't support, which must
+	// return models.ErrNotImplemented. GetReplicaRoleInfo follows GetReplicaRole.
+	NotImplemented []Method
+	// Timeout bounds each call, 5s is used if zero.
+	Timeout time.Duration
+}
+
+// Run checks the role vocabulary, the readiness semantics, the result shapes of
+// Exec and Query, the error typing and the context cancellation of the manager.
+func Run(t *testing.T, suite Suite) {
+	t.Helper()
+	if suite.Timeout == 0 {
+		suite.Timeout = defaultTimeout
+	}
+
+	t.Run("readiness", suite.testReadiness)
+	t.Run("role vocabulary", suite.testRoleVocabulary)
+	t.Run("exec result", suite.testExec)
+	t.Run("query result", suite.testQuery)
+	t.Run("context cancellation", suite.testContextCancellation)
+}
+
+func (s *Suite) prepare(method Method) {
+	if s.Prepare != nil {
+		s.Prepare(method)
+	}
+}
+
+func (s *Suite) implemented(method Method) bool {
+	if method == GetReplicaRoleInfo {
+		method = GetReplicaRole
+	}
+	return !slices.Contains(s.NotImplemented, method)
+}
+
+// call runs the method with ctx, and fails the test if it doesn't return in time.
+func (s *Suite) call(t *testing.T, ctx context.Context, method Method) (any, error) {
+	type result struct {
+		value any
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var r result
+		switch method {
+		case IsDBStartupReady:
+			r.value = s.Manager.IsDBStartupReady()
+		case GetReplicaRole:
+			r.value, r.err = s.Manager.GetReplicaRole(ctx)
+		case GetReplicaRoleInfo:
+			r.value, r.err = engines.GetReplicaRoleInfo(ctx, s.Manager)
+		case Exec:
+			r.value, r.err = s.Manager.Exec(ctx, s.ExecSQL)
+		case Query:
+			r.value, r.err = s.Manager.Query(ctx, s.QuerySQL)
+		}
+		done <- r
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-time.After(s.Timeout):
+		t.Fatalf("%s doesn't return in %s", method, s.Timeout)
+		return nil, nil
+	}
+}
+
+// checkError checks the error of a method, which must be models.ErrNotImplemented
+// if and only if the method is not implemented. It returns whether to go on checking.
+func (s *Suite) checkError(t *testing.T, method Method, err error) bool {
+	if !s.implemented(method) {
+		if !errors.Is(err, models.ErrNotImplemented) {
+			t.Errorf("%s is not implemented, but returns %v instead of ErrNotImplemented", method, err)
+		}
+		return false
+	}
+	if errors.Is(err, models.ErrNotImplemented) {
+		t.Errorf("%s is implemented, but returns ErrNotImplemented", method)
+		return false
+	}
+	if err != nil {
+		t.Errorf("%s failed: %v", method, err)
+		return false
+	}
+	return true
+}
+
+func (s *Suite) testReadiness(t *testing.T) {
+	s.prepare(IsDBStartupReady)
+	ready, _ := s.call(t, context.Background(), IsDBStartupReady)
+	if ready != true {
+		t.Fatal("IsDBStartupReady returns false while the database is up")
+	}
+
+	// the readiness is about the startup, so it's kept once the database is ready
+	ready, _ = s.call(t, context.Background(), IsDBStartupReady)
+	if ready != true {
+		t.Error("IsDBStartupReady returns false after the database is ready")
+	}
+}
+
+// testRoleVocabulary checks that the native role is normalized to the vocabulary.
+func (s *Suite) testRoleVocabulary(t *testing.T) {
+	s.prepare(GetReplicaRole)
+	role, err := s.call(t, context.Background(), GetReplicaRole)
+	if !s.checkError(t, GetReplicaRole, err) {
+		return
+	}
+
+	s.prepare(GetReplicaRoleInfo)
+	info, err := s.call(t, context.Background(), GetReplicaRoleInfo)
+	if !s.checkError(t, GetReplicaRoleInfo, err) {
+		return
+	}
+	if normalized := info.(*models.RoleInfo).Role; !slices.Contains(RoleVocabulary, normalized) {
+		t.Errorf("role %q of the native role %q is not one of %v", normalized, role, RoleVocabulary)
+	}
+}
+
+func (s *Suite) testExec(t *testing.T) {
+	s.prepare(Exec)
+	count, err := s.call(t, context.Background(), Exec)
+	if !s.checkError(t, Exec, err) {
+		return
+	}
+	if count.(int64) < 0 {
+		t.Errorf("Exec returns negative count %d", count)
+	}
+}
+
+func (s *Suite) testQuery(t *testing.T) {
+	s.prepare(Query)
+	result, err := s.call(t, context.Background(), Query)
+	if !s.checkError(t, Query, err) {
+		return
+	}
+	if !json.Valid(result.([]byte)) {
+		t.Errorf("Query returns invalid JSON: %s", result)
+	}
+}
+
+func (s *Suite) testContextCancellation(t *testing.T) {
+	for _, method := range []Method{GetReplicaRole, Exec, Query} {
+		if !s.implemented(method) {
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		s.prepare(method)
+		_, err := s.call(t, ctx, method)
+		if err == nil {
+			t.Errorf("%s succeeds with a canceled context", method)
+		} else if errors.Is(err, models.ErrNotImplemented) {
+			t.Errorf("%s returns ErrNotImplemented with a canceled context", method)
+		}
+	}
+}
@@ -29,6 +29,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	models "github.com/apecloud/dbctl/engines/models"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -70,6 +71,35 @@ func (mr *MockDBManagerMockRecorder) Exec(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockDBManager)(nil).Exec), arg0, arg1)
 }
 
+// FenceMember mocks base method.
+func (m *MockDBManager) FenceMember(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FenceMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FenceMember indicates an expected call of FenceMember.
+func (mr *MockDBManagerMockRecorder) FenceMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FenceMember", reflect.TypeOf((*MockDBManager)(nil).FenceMember), arg0, arg1)
+}
+
+// GetMemberView mocks base method.
+func (m *MockDBManager) GetMemberView(arg0 context.Context, arg1 string) (*models.MemberView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberView", arg0, arg1)
+	ret0, _ := ret[0].(*models.MemberView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberView indicates an expected call of GetMemberView.
+func (mr *MockDBManagerMockRecorder) GetMemberView(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberView", reflect.TypeOf((*MockDBManager)(nil).GetMemberView), arg0, arg1)
+}
+
 // GetReplicaRole mocks base method.
 func (m *MockDBManager) GetReplicaRole(arg0 context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +115,36 @@ func (mr *MockDBManagerMockRecorder) GetReplicaRole(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicaRole", reflect.TypeOf((*MockDBManager)(nil).GetReplicaRole), arg0)
 }
 
+// GetReplicationStatus mocks base method.
+func (m *MockDBManager) GetReplicationStatus(arg0 context.Context) (*models.ReplicationStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplicationStatus", arg0)
+	ret0, _ := ret[0].(*models.ReplicationStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplicationStatus indicates an expected call of GetReplicationStatus.
+func (mr *MockDBManagerMockRecorder) GetReplicationStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicationStatus", reflect.TypeOf((*MockDBManager)(nil).GetReplicationStatus), arg0)
+}
+
+// GetTopology mocks base method.
+func (m *MockDBManager) GetTopology(arg0 context.Context) (*models.Topology, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopology", arg0)
+	ret0, _ := ret[0].(*models.Topology)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopology indicates an expected call of GetTopology.
+func (mr *MockDBManagerMockRecorder) GetTopology(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopology", reflect.TypeOf((*MockDBManager)(nil).GetTopology), arg0)
+}
+
 // IsDBStartupReady mocks base method.
 func (m *MockDBManager) IsDBStartupReady() bool {
 	m.ctrl.T.Helper()
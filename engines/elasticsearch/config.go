@@ -0,0 +1,80 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package elasticsearch
+
+import (
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/apecloud/dbctl/constant"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+const (
+	// configSection is the config file section of Elasticsearch, shared by
+	// OpenSearch, the address is of the REST API.
+	configSection = "elasticsearch"
+
+	defaultHost    = "127.0.0.1"
+	defaultPort    = 9200
+	defaultTimeout = 5 * time.Second
+
+	// EnvUsername and EnvPassword are the credentials of the elastic images, the
+	// REST API is called anonymously if no username is set.
+	EnvUsername = "ELASTIC_USERNAME"
+	EnvPassword = "ELASTIC_PASSWORD"
+)
+
+type Config struct {
+	// URL is the base URL of the REST API, e.g. http://127.0.0.1:9200.
+	URL       string
+	TLSConfig *tls.Config
+	Timeout   time.Duration
+
+	*utilconfig.EngineCredentials
+}
+
+func NewConfig() (*Config, error) {
+	engineConfig, err := utilconfig.GetEngineConfig(configSection)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{
+		EngineCredentials: utilconfig.NewEngineCredentials(configSection,
+			utilconfig.CredentialLookup{Name: constant.ConfigKeyUserName, Envs: []string{constant.KBEnvServiceUser, EnvUsername}},
+			utilconfig.CredentialLookup{Name: constant.ConfigKeyPassword, Envs: []string{constant.KBEnvServicePassword, EnvPassword}}),
+		Timeout: defaultTimeout,
+	}
+	if config.TLSConfig, err = engineConfig.TLS.ClientConfig(); err != nil {
+		return nil, err
+	}
+	scheme := "http"
+	if config.TLSConfig != nil {
+		scheme = "https"
+	}
+	config.URL = fmt.Sprintf("%s://%s", scheme, engineConfig.Addr(defaultHost, defaultPort))
+	if engineConfig.ReadTimeout != 0 {
+		config.Timeout = engineConfig.ReadTimeout
+	}
+	config.LoadCredentials(engineConfig)
+	return config, nil
+}
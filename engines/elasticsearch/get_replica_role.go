@@ -0,0 +1,218 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const (
+	clusterHealthPath = "/_cluster/health?local=true"
+	masterPath        = "/_cat/master?format=json"
+	localNodePath     = "/_nodes/_local?filter_path=nodes.*.name,nodes.*.roles"
+)
+
+// the native roles of a node, the elected master is models.MASTER.
+const (
+	MasterEligible = "master-eligible"
+	VotingOnly     = "voting-only"
+	Data           = "data"
+	Ingest         = "ingest"
+	Coordinating   = "coordinating"
+)
+
+// nodeRoles maps the native roles, a voting-only node votes but is never elected,
+// and the others don't vote.
+var nodeRoles = map[string]models.RoleInfo{
+	MasterEligible: {Role: models.SECONDARY, Voter: true, Health: models.HealthHealthy},
+	VotingOnly:     {Role: models.ARBITER, Voter: true, Health: models.HealthHealthy},
+	Data:           {Role: models.LEARNER, Health: models.HealthHealthy},
+	Ingest:         {Role: models.LEARNER, Health: models.HealthHealthy},
+	Coordinating:   {Role: models.LEARNER, Health: models.HealthHealthy},
+}
+
+// the status of the cluster health.
+const (
+	StatusGreen  = "green"
+	StatusYellow = "yellow"
+	StatusRed    = "red"
+)
+
+// clusterHealth is the response of the cluster health API.
+type clusterHealth struct {
+	ClusterName         string `json:"cluster_name"`
+	Status              string `json:"status"`
+	NumberOfNodes       int    `json:"number_of_nodes"`
+	ActivePrimaryShards int    `json:"active_primary_shards"`
+	ActiveShards        int    `json:"active_shards"`
+	RelocatingShards    int    `json:"relocating_shards"`
+	InitializingShards  int    `json:"initializing_shards"`
+	UnassignedShards    int    `json:"unassigned_shards"`
+}
+
+func (health *clusterHealth) details() map[string]any {
+	return map[string]any{
+		"clusterName":         health.ClusterName,
+		"status":              health.Status,
+		"numberOfNodes":       health.NumberOfNodes,
+		"activePrimaryShards": health.ActivePrimaryShards,
+		"activeShards":        health.ActiveShards,
+		"relocatingShards":    health.RelocatingShards,
+		"initializingShards":  health.InitializingShards,
+		"unassignedShards":    health.UnassignedShards,
+	}
+}
+
+// localNode is the local node of the nodes info API.
+type localNode struct {
+	ID    string
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+// GetReplicaRole returns master if the local node is the elected master, or the
+// role of the node otherwise: master-eligible, voting-only, data, ingest or
+// coordinating, the first one the node has in this order. The data tiers, e.g.
+// data_hot, are data.
+func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
+	node, err := mgr.getLocalNode(ctx)
+	if err != nil {
+		return "", err
+	}
+	masterID, err := mgr.getMasterID(ctx)
+	if err != nil {
+		return "", err
+	}
+	if node.ID == masterID {
+		return models.MASTER, nil
+	}
+	return node.role(), nil
+}
+
+// GetReplicaRoleInfo returns the role of the node with the health of the cluster:
+// healthy if it's green, recovering if it's yellow, i.e. some replica shards are
+// unassigned, or unhealthy if it's red, i.e. some primary shards are unassigned.
+// Every node routes the writes to the primary shards, so a node is writable
+// unless the cluster is red. The shard counts are in the details.
+func (mgr *Manager) GetReplicaRoleInfo(ctx context.Context) (*models.RoleInfo, error) {
+	role, err := mgr.GetReplicaRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	health, err := mgr.getClusterHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info, ok := nodeRoles[role]
+	if !ok {
+		info = *models.NewRoleInfo(role)
+	}
+	info.NativeRole = role
+	switch health.Status {
+	case StatusGreen:
+		info.Health = models.HealthHealthy
+	case StatusYellow:
+		info.Health = models.HealthRecovering
+	case StatusRed:
+		info.Health = models.HealthUnhealthy
+	default:
+		info.Health = models.HealthUnknown
+	}
+	info.Writable = health.Status != StatusRed
+	info.Details = health.details()
+	return &info, nil
+}
+
+func (mgr *Manager) getClusterHealth(ctx context.Context) (*clusterHealth, error) {
+	data, err := mgr.request(ctx, http.MethodGet, clusterHealthPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	health := &clusterHealth{}
+	if err = json.Unmarshal(data, health); err != nil {
+		return nil, errors.Wrapf(err, "decode the cluster health %s failed", data)
+	}
+	return health, nil
+}
+
+// getMasterID returns the node id of the elected master, the request fails if no
+// master is elected.
+func (mgr *Manager) getMasterID(ctx context.Context) (string, error) {
+	data, err := mgr.request(ctx, http.MethodGet, masterPath, nil)
+	if err != nil {
+		return "", err
+	}
+	var masters []struct {
+		ID string `json:"id"`
+	}
+	if err = json.Unmarshal(data, &masters); err != nil {
+		return "", errors.Wrapf(err, "decode the master %s failed", data)
+	}
+	if len(masters) == 0 || masters[0].ID == "" {
+		return "", errors.New("no master is elected")
+	}
+	return masters[0].ID, nil
+}
+
+func (mgr *Manager) getLocalNode(ctx context.Context) (*localNode, error) {
+	data, err := mgr.request(ctx, http.MethodGet, localNodePath, nil)
+	if err != nil {
+		return nil, err
+	}
+	info := &struct {
+		Nodes map[string]*localNode `json:"nodes"`
+	}{}
+	if err = json.Unmarshal(data, info); err != nil {
+		return nil, errors.Wrapf(err, "decode the local node %s failed", data)
+	}
+	if len(info.Nodes) != 1 {
+		return nil, errors.Errorf("expect the local node, but got %d nodes", len(info.Nodes))
+	}
+	for id, node := range info.Nodes {
+		node.ID = id
+		return node, nil
+	}
+	return nil, nil
+}
+
+func (node *localNode) role() string {
+	switch {
+	case slices.Contains(node.Roles, "voting_only"):
+		return VotingOnly
+	// cluster_manager is the master of OpenSearch 2
+	case slices.Contains(node.Roles, "master"), slices.Contains(node.Roles, "cluster_manager"):
+		return MasterEligible
+	case slices.ContainsFunc(node.Roles, func(role string) bool { return strings.HasPrefix(role, "data") }):
+		return Data
+	case slices.Contains(node.Roles, "ingest"):
+		return Ingest
+	default:
+		return Coordinating
+	}
+}
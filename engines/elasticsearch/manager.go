@@ -0,0 +1,182 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+// Manager calls the REST API of the local node, it serves OpenSearch as well,
+// which keeps the APIs of Elasticsearch 7.10.
+type Manager struct {
+	engines.DBManagerBase
+	config *Config
+	client *http.Client
+}
+
+var _ engines.DBManager = &Manager{}
+
+func NewManager() (engines.DBManager, error) {
+	logger := ctrl.Log.WithName("Elasticsearch")
+	config, err := NewConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	managerBase, err := engines.NewDBManagerBase(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := &Manager{
+		DBManagerBase: *managerBase,
+		config:        config,
+		client: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: &http.Transport{TLSClientConfig: config.TLSConfig},
+		},
+	}
+
+	utilconfig.OnCredentialsChange(mgr.reloadCredentials)
+	return mgr, nil
+}
+
+// reloadCredentials reloads the credentials, which are read for every request.
+func (mgr *Manager) reloadCredentials() {
+	if err := mgr.config.ReloadCredentials(); err != nil {
+		mgr.Logger.Info("reload credentials failed", "error", err.Error())
+		return
+	}
+	mgr.Logger.Info("credentials reloaded")
+}
+
+// IsDBStartupReady checks the cluster health of the local node, which responds
+// by its own cluster state once it has joined the cluster. The status isn't
+// checked, a red cluster still serves the indices whose primaries are assigned.
+func (mgr *Manager) IsDBStartupReady() bool {
+	if mgr.DBStartupReady {
+		return true
+	}
+
+	if _, err := mgr.getClusterHealth(context.Background()); err != nil {
+		mgr.Logger.Info("DB is not ready", "error", err.Error())
+		return false
+	}
+
+	mgr.DBStartupReady = true
+	mgr.Logger.Info("DB startup ready")
+	return true
+}
+
+// restError is the error response of the REST API, the error is a string for a
+// few APIs, e.g. when the authentication fails on OpenSearch.
+type restError struct {
+	Error json.RawMessage `json:"error"`
+}
+
+// reason returns the reason of the error, or an empty string if the response
+// isn't an error of the REST API.
+func (e *restError) reason() string {
+	var reason string
+	if json.Unmarshal(e.Error, &reason) == nil {
+		return reason
+	}
+	cause := &struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	}{}
+	if json.Unmarshal(e.Error, cause) != nil || cause.Reason == "" {
+		return ""
+	}
+	return cause.Type + ": " + cause.Reason
+}
+
+// request sends the REST request and returns the response body, the error of a
+// failed request is the reason Elasticsearch responds with. The body of the bulk
+// APIs is newline-delimited JSON, which must end with a newline.
+func (mgr *Manager) request(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	contentType := "application/json"
+	if isNDJSON(path) {
+		contentType = "application/x-ndjson"
+		if len(body) != 0 && !bytes.HasSuffix(body, []byte("\n")) {
+			body = append(body, '\n')
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, mgr.config.URL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) != 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	if username, password := mgr.config.GetCredentials(); username != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := mgr.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s failed", method, path)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read the response of %s %s failed", method, path)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		reason := strings.TrimSpace(string(data))
+		restErr := &restError{}
+		if json.Unmarshal(data, restErr) == nil {
+			if r := restErr.reason(); r != "" {
+				reason = r
+			}
+		}
+		return nil, errors.Errorf("%s %s failed, status %s: %s", method, path, resp.Status, reason)
+	}
+	return data, nil
+}
+
+// isNDJSON tells whether the body of the API is newline-delimited JSON.
+func isNDJSON(path string) bool {
+	path, _, _ = strings.Cut(path, "?")
+	for _, api := range []string{"_bulk", "_msearch"} {
+		if strings.HasSuffix(path, "/"+api) || strings.Contains(path, "/"+api+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (mgr *Manager) ShutDownWithWait() {
+	mgr.client.CloseIdleConnections()
+}
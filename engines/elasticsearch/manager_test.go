@@ -0,0 +1,235 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package elasticsearch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/conformance"
+	"github.com/apecloud/dbctl/engines/models"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+// mockNode serves the REST API of a node, the requests are recorded as
+// "METHOD uri Content-Type body".
+type mockNode struct {
+	status   string
+	masterID string
+	roles    string
+	requests []string
+}
+
+func (m *mockNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	m.requests = append(m.requests, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Content-Type")+" "+string(body))
+	if username, password, ok := r.BasicAuth(); !ok || username != "elastic" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"type":"security_exception","reason":"missing authentication credentials"},"status":401}`))
+		return
+	}
+
+	switch r.Method + " " + r.URL.RequestURI() {
+	case "GET " + clusterHealthPath:
+		if m.status == "" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":{"type":"cluster_block_exception","reason":"blocked by: [SERVICE_UNAVAILABLE/1/state not recovered / initialized];"},"status":503}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"cluster_name":"es","status":"` + m.status + `","number_of_nodes":3,"unassigned_shards":2}`))
+	case "GET " + masterPath:
+		_, _ = w.Write([]byte(`[{"id":"` + m.masterID + `","host":"10.0.0.1","ip":"10.0.0.1","node":"es-0"}]`))
+	case "GET " + localNodePath:
+		_, _ = w.Write([]byte(`{"nodes":{"n0":{"name":"es-0","roles":` + m.roles + `}}}`))
+	case "GET /_cat/health":
+		_, _ = w.Write([]byte("1700000000 00:00:00 es green 3 3 10 5 0 0 0 0 - 100.0%\n"))
+	case "GET /orders/_search":
+		_, _ = w.Write([]byte(`{"hits":{"total":{"value":1},"hits":[{"_id":"1"}]}}`))
+	case "PUT /orders/_doc/1":
+		_, _ = w.Write([]byte(`{"_index":"orders","_id":"1","result":"created"}`))
+	case "POST /orders/_update_by_query":
+		_, _ = w.Write([]byte(`{"total":3,"updated":3,"created":0,"deleted":0}`))
+	case "POST /_bulk":
+		_, _ = w.Write([]byte(`{"errors":true,"items":[{"index":{"result":"created"}},{"delete":{"result":"not_found"}},{"update":{"error":{}}}]}`))
+	case "PUT /orders":
+		_, _ = w.Write([]byte(`{"acknowledged":true,"index":"orders"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"type":"index_not_found_exception","reason":"no such index [missing]"},"status":404}`))
+	}
+}
+
+func mockManager(t *testing.T) (*Manager, *mockNode) {
+	node := &mockNode{
+		status:   StatusGreen,
+		masterID: "n0",
+		roles:    `["data","ingest","master"]`,
+	}
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+
+	mgr := &Manager{
+		DBManagerBase: engines.DBManagerBase{
+			CurrentMemberName: "es-0",
+			Logger:            ctrl.Log.WithName("Elasticsearch-TEST"),
+		},
+		config: &Config{
+			URL:               server.URL,
+			EngineCredentials: utilconfig.NewEngineCredentials(configSection, utilconfig.CredentialLookup{Default: "elastic"}, utilconfig.CredentialLookup{Default: "secret"}),
+		},
+		client: server.Client(),
+	}
+	return mgr, node
+}
+
+func TestIsDBStartupReady(t *testing.T) {
+	mgr, node := mockManager(t)
+
+	node.status = ""
+	assert.False(t, mgr.IsDBStartupReady())
+
+	// a red cluster is ready
+	node.status = StatusRed
+	assert.True(t, mgr.IsDBStartupReady())
+
+	// the credentials are required
+	mgr, _ = mockManager(t)
+	mgr.config.SetCredentials("elastic", "")
+	assert.False(t, mgr.IsDBStartupReady())
+}
+
+func TestGetReplicaRole(t *testing.T) {
+	ctx := context.TODO()
+	mgr, node := mockManager(t)
+
+	role, err := mgr.GetReplicaRole(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.MASTER, role)
+
+	node.masterID = "n1"
+	tests := []struct {
+		roles string
+		want  string
+	}{
+		{`["data","ingest","master"]`, MasterEligible},
+		{`["cluster_manager"]`, MasterEligible},
+		{`["master","voting_only"]`, VotingOnly},
+		{`["data_hot","ingest"]`, Data},
+		{`["ingest"]`, Ingest},
+		{`[]`, Coordinating},
+	}
+	for _, tt := range tests {
+		node.roles = tt.roles
+		role, err = mgr.GetReplicaRole(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, role, tt.roles)
+	}
+
+	node.masterID = ""
+	_, err = mgr.GetReplicaRole(ctx)
+	assert.ErrorContains(t, err, "no master is elected")
+}
+
+func TestGetReplicaRoleInfo(t *testing.T) {
+	ctx := context.TODO()
+	mgr, node := mockManager(t)
+
+	details := map[string]any{"clusterName": "es", "status": StatusGreen, "numberOfNodes": 3, "activePrimaryShards": 0,
+		"activeShards": 0, "relocatingShards": 0, "initializingShards": 0, "unassignedShards": 2}
+	info, err := mgr.GetReplicaRoleInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &models.RoleInfo{Role: models.PRIMARY, NativeRole: models.MASTER, Writable: true, Voter: true,
+		Health: models.HealthHealthy, Details: details}, info)
+
+	node.masterID = "n1"
+	node.roles = `["data"]`
+	node.status = StatusYellow
+	details["status"] = StatusYellow
+	info, err = mgr.GetReplicaRoleInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &models.RoleInfo{Role: models.LEARNER, NativeRole: Data, Writable: true, Health: models.HealthRecovering,
+		Details: details}, info)
+
+	node.status = StatusRed
+	info, err = mgr.GetReplicaRoleInfo(ctx)
+	require.NoError(t, err)
+	assert.False(t, info.Writable)
+	assert.Equal(t, models.HealthUnhealthy, info.Health)
+}
+
+func TestQueryAndExec(t *testing.T) {
+	ctx := context.TODO()
+	mgr, node := mockManager(t)
+
+	result, err := mgr.Query(ctx, "GET /orders/_search\n{\"query\": {\"match_all\": {}}}")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"hits":{"total":{"value":1},"hits":[{"_id":"1"}]}}`, string(result))
+	assert.Equal(t, "GET /orders/_search application/json {\"query\": {\"match_all\": {}}}", node.requests[len(node.requests)-1])
+
+	result, err = mgr.Query(ctx, "_cat/health")
+	require.NoError(t, err)
+	assert.Equal(t, `"1700000000 00:00:00 es green 3 3 10 5 0 0 0 0 - 100.0%\n"`, string(result))
+
+	_, err = mgr.Query(ctx, "GET /missing/_search")
+	assert.ErrorContains(t, err, "index_not_found_exception: no such index [missing]")
+	_, err = mgr.Query(ctx, "POST /orders/_search")
+	assert.ErrorContains(t, err, "use exec for POST")
+	_, err = mgr.Query(ctx, "GET /orders/_search extra")
+	assert.ErrorContains(t, err, "invalid request line")
+
+	count, err := mgr.Exec(ctx, "PUT /orders/_doc/1\n{\"status\": \"paid\"}")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, err = mgr.Exec(ctx, "orders/_update_by_query\n{\"script\": \"ctx._source.n++\"}")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	// the bulk body is newline-delimited JSON, and the failed items aren't counted
+	count, err = mgr.Exec(ctx, "POST /_bulk\n{\"index\":{\"_index\":\"orders\"}}\n{\"status\":\"new\"}")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, "POST /_bulk application/x-ndjson {\"index\":{\"_index\":\"orders\"}}\n{\"status\":\"new\"}\n", node.requests[len(node.requests)-1])
+
+	count, err = mgr.Exec(ctx, "PUT /orders")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	_, err = mgr.Exec(ctx, "GET /orders/_search")
+	assert.ErrorContains(t, err, "use query for GET")
+}
+
+func TestConformance(t *testing.T) {
+	mgr, _ := mockManager(t)
+
+	conformance.Run(t, conformance.Suite{
+		Manager:  mgr,
+		ExecSQL:  "PUT /orders/_doc/1\n{\"status\": \"paid\"}",
+		QuerySQL: "GET /orders/_search",
+	})
+}
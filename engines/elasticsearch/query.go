@@ -0,0 +1,155 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Query sends a GET request, e.g. "/_cat/indices?format=json", or a search with
+// the JSON body in the following lines:
+//
+//	GET /orders/_search
+//	{"query": {"match": {"status": "paid"}}}
+//
+// The response is returned as it is, a response other than JSON, e.g. of the cat
+// APIs without format=json, is returned as a JSON string.
+func (mgr *Manager) Query(ctx context.Context, request string) ([]byte, error) {
+	mgr.Logger.Info(fmt.Sprintf("query: %s", request))
+	method, path, body, err := parseRequest(request, http.MethodGet)
+	if err != nil {
+		return nil, err
+	}
+	if method != http.MethodGet {
+		return nil, errors.Errorf("query only sends GET requests, use exec for %s", method)
+	}
+
+	data, err := mgr.request(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(data) {
+		return json.Marshal(string(data))
+	}
+	return data, nil
+}
+
+// Exec sends a POST, PUT or DELETE request, POST by default, the body follows the
+// request line, e.g.
+//
+//	PUT /orders/_doc/1
+//	{"status": "paid"}
+//
+// The affected documents are counted by the response, see affectedDocs.
+func (mgr *Manager) Exec(ctx context.Context, request string) (int64, error) {
+	mgr.Logger.Info(fmt.Sprintf("exec: %s", request))
+	method, path, body, err := parseRequest(request, http.MethodPost)
+	if err != nil {
+		return 0, err
+	}
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodDelete:
+	default:
+		return 0, errors.Errorf("exec only sends POST, PUT or DELETE requests, use query for %s", method)
+	}
+
+	data, err := mgr.request(ctx, method, path, body)
+	if err != nil {
+		return 0, err
+	}
+	return affectedDocs(data), nil
+}
+
+// writeResponse is the part of the write responses which tells the affected
+// documents.
+type writeResponse struct {
+	// Result is of a single document, e.g. created, updated, deleted or noop.
+	Result string `json:"result"`
+	// Created, Updated and Deleted are of the update and delete by query APIs.
+	Created int64 `json:"created"`
+	Updated int64 `json:"updated"`
+	Deleted int64 `json:"deleted"`
+	// Items are of the bulk API, an item is keyed by the action.
+	Items []map[string]struct {
+		Result string `json:"result"`
+	} `json:"items"`
+}
+
+// affectedDocs counts the documents created, updated or deleted by the response,
+// it's 0 for the other APIs, e.g. to create an index.
+func affectedDocs(data []byte) int64 {
+	resp := &writeResponse{}
+	if json.Unmarshal(data, resp) != nil {
+		return 0
+	}
+	if resp.Items != nil {
+		var count int64
+		for _, item := range resp.Items {
+			for _, action := range item {
+				if isWriteResult(action.Result) {
+					count++
+				}
+			}
+		}
+		return count
+	}
+	if resp.Result != "" {
+		if isWriteResult(resp.Result) {
+			return 1
+		}
+		return 0
+	}
+	return resp.Created + resp.Updated + resp.Deleted
+}
+
+func isWriteResult(result string) bool {
+	return result == "created" || result == "updated" || result == "deleted"
+}
+
+// parseRequest parses the request line, "[METHOD] path", and the body in the
+// following lines.
+func parseRequest(request, defaultMethod string) (string, string, []byte, error) {
+	line, body, _ := strings.Cut(strings.TrimSpace(request), "\n")
+	fields := strings.Fields(line)
+	method, path := defaultMethod, ""
+	switch len(fields) {
+	case 1:
+		path = fields[0]
+	case 2:
+		method, path = strings.ToUpper(fields[0]), fields[1]
+	default:
+		return "", "", nil, errors.Errorf("invalid request line %q, it should be [METHOD] path", line)
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return method, path, nil, nil
+	}
+	return method, path, []byte(body), nil
+}
@@ -27,6 +27,8 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+	utilconfig "github.com/apecloud/dbctl/util/config"
 )
 
 const (
@@ -45,8 +47,21 @@ var _ engines.DBManager = &Manager{}
 
 func NewManager() (engines.DBManager, error) {
 	logger := ctrl.Log.WithName("ETCD")
+	engineConfig, err := utilconfig.GetEngineConfig(string(models.ETCD))
+	if err != nil {
+		return nil, err
+	}
 	properties := map[string]string{
-		defaultEndpoint: "127.0.0.1:2379",
+		defaultEndpoint: engineConfig.Addr("127.0.0.1", 2379),
+	}
+
+	tlsConfig, err := engineConfig.TLS.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	dialTimeout := defaultDialTimeout
+	if engineConfig.ConnectTimeout != 0 {
+		dialTimeout = engineConfig.ConnectTimeout
 	}
 
 	managerBase, err := engines.NewDBManagerBase(logger)
@@ -67,7 +82,10 @@ func NewManager() (engines.DBManager, error) {
 
 	cli, err := v3.New(v3.Config{
 		Endpoints:   endpoints,
-		DialTimeout: defaultDialTimeout,
+		DialTimeout: dialTimeout,
+		Username:    engineConfig.Username,
+		Password:    engineConfig.Password,
+		TLS:         tlsConfig,
 	})
 	if err != nil {
 		return nil, err
@@ -94,3 +112,10 @@ func (mgr *Manager) IsDBStartupReady() bool {
 	mgr.Logger.Info("DB startup ready")
 	return true
 }
+
+var _ engines.Pinger = &Manager{}
+
+func (mgr *Manager) Ping(ctx context.Context) error {
+	_, err := mgr.etcd.Status(ctx, mgr.endpoint)
+	return err
+}
@@ -23,12 +23,16 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/spf13/viper"
+	clientv3 "go.etcd.io/etcd/client/v3"
 
 	"github.com/apecloud/dbctl/constant"
+	"github.com/apecloud/dbctl/engines/conformance"
+	"github.com/apecloud/dbctl/engines/models"
 )
 
 // Test case for Init() function
@@ -83,4 +87,48 @@ var _ = Describe("ETCD DBManager", func() {
 			Expect(role).Should(Equal("leader"))
 		})
 	})
+
+	Context("get topology", func() {
+		It("get the single member", func() {
+			etcdServer, err := StartEtcdServer()
+			Expect(err).Should(BeNil())
+			defer etcdServer.Stop()
+			testEndpoint := fmt.Sprintf("http://%s", etcdServer.ETCD.Clients[0].Addr().(*net.TCPAddr).String())
+			manager := &Manager{
+				etcd:     etcdServer.client,
+				endpoint: testEndpoint,
+			}
+			topology, err := manager.GetTopology(context.Background())
+			Expect(err).Should(BeNil())
+			Expect(topology.Members).Should(HaveLen(1))
+			Expect(topology.Members[0].Role).Should(Equal(models.LEADER))
+			Expect(topology.Members[0].Self).Should(BeTrue())
+			Expect(topology.Members[0].Health).Should(Equal(models.HealthHealthy))
+			Expect(topology.Term).Should(BeNumerically(">", 0))
+		})
+	})
 })
+
+func TestConformance(t *testing.T) {
+	etcdServer, err := StartEtcdServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer etcdServer.Stop()
+	testEndpoint := fmt.Sprintf("http://%s", etcdServer.ETCD.Clients[0].Addr().(*net.TCPAddr).String())
+	// the in-process client of the embedded etcd doesn't honor the context, connect it by grpc instead
+	client, err := clientv3.New(clientv3.Config{Endpoints: []string{testEndpoint}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	manager := &Manager{
+		etcd:     client,
+		endpoint: testEndpoint,
+	}
+
+	conformance.Run(t, conformance.Suite{
+		Manager:        manager,
+		NotImplemented: []conformance.Method{conformance.Exec, conformance.Query},
+	})
+}
@@ -0,0 +1,65 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package etcd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// GetMemberView asks the member for its status by its client URL, the leader ID
+// in the status is resolved to the client URL by the member list.
+func (mgr *Manager) GetMemberView(ctx context.Context, address string) (*models.MemberView, error) {
+	endpoint, _, _ := strings.Cut(address, ",")
+	if endpoint == "" {
+		endpoint = mgr.endpoint
+	}
+	status, err := mgr.etcd.Status(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &models.MemberView{
+		Address: address,
+		Role:    models.FOLLOWER,
+		Term:    int64(status.RaftTerm),
+	}
+	switch {
+	case status.Leader == status.Header.MemberId:
+		view.Role = models.LEADER
+		view.Writable = true
+		return view, nil
+	case status.IsLearner:
+		view.Role = models.LEARNER
+	}
+
+	memberList, err := mgr.etcd.MemberList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range memberList.Members {
+		if m.ID == status.Leader {
+			view.Leader = strings.Join(m.ClientURLs, ",")
+		}
+	}
+	return view, nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package etcd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// GetTopology lists the members with the leader known by the local member, the
+// health of the other members is unknown from the local view.
+func (mgr *Manager) GetTopology(ctx context.Context) (*models.Topology, error) {
+	status, err := mgr.etcd.Status(ctx, mgr.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	memberList, err := mgr.etcd.MemberList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	topology := &models.Topology{
+		Members: make([]models.Member, 0, len(memberList.Members)),
+		Term:    int64(status.RaftTerm),
+	}
+	for _, m := range memberList.Members {
+		role := models.FOLLOWER
+		switch {
+		case m.ID == status.Leader:
+			role = models.LEADER
+		case m.IsLearner:
+			role = models.LEARNER
+		}
+
+		member := models.NewMember(strings.Join(m.ClientURLs, ","), role)
+		member.Name = m.Name
+		member.Self = m.ID == status.Header.MemberId
+		if !member.Self {
+			member.Health = models.HealthUnknown
+		}
+		topology.Members = append(topology.Members, member)
+	}
+	return topology, nil
+}
@@ -0,0 +1,246 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+)
+
+// EnvScenarioFile is the scenario applied when the fake engine starts.
+const EnvScenarioFile = "FAKE_SCENARIO_FILE"
+
+// Manager is an in-memory engine without any database, whose role, readiness,
+// latency and errors are scripted at runtime. It's meant for the integration
+// tests of the dbctl consumers.
+type Manager struct {
+	engines.DBManagerBase
+
+	lock         sync.RWMutex
+	role         string
+	ready        bool
+	connected    bool
+	latency      time.Duration
+	err          string
+	queryResult  json.RawMessage
+	affectedRows int64
+
+	// stopScenario stops the steps of the running scenario.
+	stopScenario context.CancelFunc
+}
+
+var _ engines.DBManager = &Manager{}
+
+func NewManager() (engines.DBManager, error) {
+	logger := ctrl.Log.WithName("Fake")
+	managerBase, err := engines.NewDBManagerBase(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := &Manager{
+		DBManagerBase: *managerBase,
+		role:          "primary",
+		ready:         true,
+		connected:     true,
+		queryResult:   json.RawMessage("[]"),
+	}
+
+	if viper.IsSet(EnvScenarioFile) {
+		scenario, err := LoadScenario(viper.GetString(EnvScenarioFile))
+		if err != nil {
+			return nil, err
+		}
+		mgr.RunScenario(scenario)
+	}
+	return mgr, nil
+}
+
+func (mgr *Manager) IsDBStartupReady() bool {
+	mgr.lock.RLock()
+	defer mgr.lock.RUnlock()
+	return mgr.ready && mgr.connected
+}
+
+func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
+	if err := mgr.simulate(ctx); err != nil {
+		return "", err
+	}
+
+	mgr.lock.RLock()
+	defer mgr.lock.RUnlock()
+	return mgr.role, nil
+}
+
+func (mgr *Manager) Exec(ctx context.Context, _ string) (int64, error) {
+	if err := mgr.simulate(ctx); err != nil {
+		return 0, err
+	}
+
+	mgr.lock.RLock()
+	defer mgr.lock.RUnlock()
+	return mgr.affectedRows, nil
+}
+
+func (mgr *Manager) Query(ctx context.Context, _ string) ([]byte, error) {
+	if err := mgr.simulate(ctx); err != nil {
+		return nil, err
+	}
+
+	mgr.lock.RLock()
+	defer mgr.lock.RUnlock()
+	return mgr.queryResult, nil
+}
+
+func (mgr *Manager) ShutDownWithWait() {
+	mgr.lock.Lock()
+	defer mgr.lock.Unlock()
+	if mgr.stopScenario != nil {
+		mgr.stopScenario()
+		mgr.stopScenario = nil
+	}
+	mgr.connected = false
+}
+
+// simulate waits for the latency, and returns the scripted error.
+func (mgr *Manager) simulate(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	mgr.lock.RLock()
+	latency, connected, err := mgr.latency, mgr.connected, mgr.err
+	mgr.lock.RUnlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if !connected {
+		return errors.New("connection refused")
+	}
+	if err != "" {
+		return errors.New(err)
+	}
+	return nil
+}
+
+// Apply applies the set fields of the state.
+func (mgr *Manager) Apply(state State) error {
+	latency, err := parseLatency(state.Latency)
+	if err != nil {
+		return err
+	}
+
+	mgr.lock.Lock()
+	defer mgr.lock.Unlock()
+	if state.Role != "" {
+		mgr.role = state.Role
+	}
+	if state.Ready != nil {
+		mgr.ready = *state.Ready
+	}
+	if state.Connected != nil {
+		mgr.connected = *state.Connected
+	}
+	if state.Latency != "" {
+		mgr.latency = latency
+	}
+	if state.Error != nil {
+		mgr.err = *state.Error
+	}
+	if state.QueryResult != nil {
+		mgr.queryResult = state.QueryResult
+	}
+	if state.AffectedRows != nil {
+		mgr.affectedRows = *state.AffectedRows
+	}
+	return nil
+}
+
+// GetState returns the current state with all the fields set.
+func (mgr *Manager) GetState() State {
+	mgr.lock.RLock()
+	defer mgr.lock.RUnlock()
+
+	ready, connected, err, affectedRows := mgr.ready, mgr.connected, mgr.err, mgr.affectedRows
+	return State{
+		Role:         mgr.role,
+		Ready:        &ready,
+		Connected:    &connected,
+		Latency:      mgr.latency.String(),
+		Error:        &err,
+		QueryResult:  mgr.queryResult,
+		AffectedRows: &affectedRows,
+	}
+}
+
+// RunScenario stops the running scenario, applies the initial state of the new
+// one at once, and its steps in background. The scenario must be validated.
+func (mgr *Manager) RunScenario(scenario *Scenario) {
+	ctx, cancel := context.WithCancel(context.Background())
+	mgr.lock.Lock()
+	if mgr.stopScenario != nil {
+		mgr.stopScenario()
+	}
+	mgr.stopScenario = cancel
+	mgr.lock.Unlock()
+
+	_ = mgr.Apply(scenario.State)
+	if len(scenario.Steps) == 0 {
+		return
+	}
+	go mgr.runSteps(ctx, scenario)
+}
+
+func (mgr *Manager) runSteps(ctx context.Context, scenario *Scenario) {
+	for {
+		for i, step := range scenario.Steps {
+			after, _ := time.ParseDuration(step.After)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(after):
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if err := mgr.Apply(step.State); err != nil {
+				mgr.Logger.Info("apply scenario step failed", "step", i, "error", err.Error())
+				continue
+			}
+			mgr.Logger.Info("scenario step applied", "step", i)
+		}
+		if !scenario.Loop {
+			return
+		}
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package fake
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/constant"
+	"github.com/apecloud/dbctl/engines/conformance"
+)
+
+const fakeScenario = `
+role: primary
+queryResult:
+  - id: 1
+steps:
+  - after: 50ms
+    role: secondary
+  - after: 50ms
+    connected: false
+`
+
+func mockManager(t *testing.T) *Manager {
+	viper.Set(constant.KBEnvPodName, "test-pod-0")
+	t.Cleanup(viper.Reset)
+
+	dbManager, err := NewManager()
+	assert.Nil(t, err)
+	return dbManager.(*Manager)
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.TODO()
+	manager := mockManager(t)
+
+	t.Run("default state", func(t *testing.T) {
+		assert.True(t, manager.IsDBStartupReady())
+		role, err := manager.GetReplicaRole(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, "primary", role)
+		result, err := manager.Query(ctx, "select 1")
+		assert.Nil(t, err)
+		assert.Equal(t, "[]", string(result))
+	})
+
+	t.Run("slow query", func(t *testing.T) {
+		assert.Nil(t, manager.Apply(State{Latency: "1s"}))
+		timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err := manager.Query(timeoutCtx, "select 1")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, manager.Apply(State{Latency: "0s"}))
+	})
+
+	t.Run("scripted error", func(t *testing.T) {
+		errMsg := "too many connections"
+		assert.Nil(t, manager.Apply(State{Error: &errMsg}))
+		_, err := manager.Exec(ctx, "create database test")
+		assert.ErrorContains(t, err, errMsg)
+
+		errMsg = ""
+		assert.Nil(t, manager.Apply(State{Error: &errMsg}))
+		_, err = manager.Exec(ctx, "create database test")
+		assert.Nil(t, err)
+	})
+
+	t.Run("invalid latency", func(t *testing.T) {
+		assert.NotNil(t, manager.Apply(State{Latency: "1x"}))
+	})
+}
+
+func TestScenario(t *testing.T) {
+	ctx := context.TODO()
+	scenarioFile := filepath.Join(t.TempDir(), "scenario.yaml")
+	assert.Nil(t, os.WriteFile(scenarioFile, []byte(fakeScenario), 0600))
+	viper.Set(EnvScenarioFile, scenarioFile)
+	manager := mockManager(t)
+	defer manager.ShutDownWithWait()
+
+	result, err := manager.Query(ctx, "select 1")
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"id": 1}]`, string(result))
+
+	assert.Eventually(t, func() bool {
+		role, _ := manager.GetReplicaRole(ctx)
+		return role == "secondary"
+	}, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return !manager.IsDBStartupReady()
+	}, time.Second, 10*time.Millisecond)
+	_, err = manager.GetReplicaRole(ctx)
+	assert.ErrorContains(t, err, "connection refused")
+
+	t.Run("invalid scenario", func(t *testing.T) {
+		_, err := ParseScenario([]byte("steps:\n  - after: soon\n"))
+		assert.NotNil(t, err)
+		_, err = ParseScenario([]byte("loop: true\n"))
+		assert.NotNil(t, err)
+		_, err = ParseScenario([]byte("steps:\n  - after: 0s\n  - after: 0s\nloop: true\n"))
+		assert.ErrorContains(t, err, "positive after")
+		_, err = ParseScenario([]byte("steps:\n  - after: -1s\n"))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, conformance.Suite{
+		Manager:  mockManager(t),
+		ExecSQL:  "create database test",
+		QuerySQL: "select 1",
+	})
+}
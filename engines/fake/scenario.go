@@ -0,0 +1,129 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package fake
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// State is the scriptable state of the fake engine, the unset fields are kept
+// unchanged when it's applied.
+type State struct {
+	Role string `json:"role,omitempty"`
+	// Ready is the readiness of the DB startup.
+	Ready *bool `json:"ready,omitempty"`
+	// Connected false simulates the connection loss, all the methods fail.
+	Connected *bool `json:"connected,omitempty"`
+	// Latency delays all the methods, e.g. 2s, to simulate slow queries.
+	Latency string `json:"latency,omitempty"`
+	// Error is returned by all the methods if it's not empty.
+	Error *string `json:"error,omitempty"`
+	// QueryResult is returned by query, in any JSON value.
+	QueryResult json.RawMessage `json:"queryResult,omitempty"`
+	// AffectedRows is returned by exec.
+	AffectedRows *int64 `json:"affectedRows,omitempty"`
+}
+
+// Step is applied after the duration since the previous step.
+type Step struct {
+	After string `json:"after"`
+	State
+}
+
+// Scenario applies the initial state at once, then the steps one by one, e.g.
+//
+//	role: primary
+//	steps:
+//	  - after: 10s
+//	    role: secondary
+//	  - after: 5s
+//	    connected: false
+//	loop: true
+type Scenario struct {
+	State
+	Steps []Step `json:"steps,omitempty"`
+	// Loop restarts the steps after the last one.
+	Loop bool `json:"loop,omitempty"`
+}
+
+// LoadScenario reads a scenario from a YAML or JSON file.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read scenario file %s failed", path)
+	}
+	return ParseScenario(data)
+}
+
+// ParseScenario parses a scenario in YAML or JSON.
+func ParseScenario(data []byte) (*Scenario, error) {
+	scenario := &Scenario{}
+	if err := yaml.Unmarshal(data, scenario); err != nil {
+		return nil, errors.Wrap(err, "parse scenario failed")
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return scenario, nil
+}
+
+func (s *Scenario) Validate() error {
+	if _, err := parseLatency(s.Latency); err != nil {
+		return err
+	}
+	var period time.Duration
+	for i, step := range s.Steps {
+		after, err := time.ParseDuration(step.After)
+		if err != nil {
+			return errors.Wrapf(err, "invalid after of step %d", i)
+		}
+		if after < 0 {
+			return errors.Errorf("invalid after of step %d, it's negative", i)
+		}
+		if _, err := parseLatency(step.Latency); err != nil {
+			return errors.Wrapf(err, "invalid step %d", i)
+		}
+		period += after
+	}
+	if s.Loop && len(s.Steps) == 0 {
+		return errors.New("loop requires steps")
+	}
+	// the steps would be applied in a busy loop otherwise
+	if s.Loop && period == 0 {
+		return errors.New("loop requires a step with a positive after")
+	}
+	return nil
+}
+
+func parseLatency(latency string) (time.Duration, error) {
+	if latency == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(latency)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid latency %s", latency)
+	}
+	return d, nil
+}
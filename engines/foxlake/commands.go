@@ -21,6 +21,7 @@ package foxlake
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -76,6 +77,22 @@ func (r *Commands) ConnectExample(info *engines.ConnectionInfo, client string) s
 	return engines.BuildExample(info, client, r.examples)
 }
 
-func (r *Commands) ExecuteCommand([]string) ([]string, []corev1.EnvVar, error) {
-	return nil, nil, fmt.Errorf("%s not implemented", r.info.Client)
+// ExecuteCommand runs the scripts by the mysql client, the password is passed by
+// MYSQL_PWD rather than in a URL, where it would have to be escaped.
+func (r *Commands) ExecuteCommand(scripts []string) ([]string, []corev1.EnvVar, error) {
+	var cmd []string
+	cmd = append(cmd, "/bin/sh", "-c", "-ex")
+	cmd = append(cmd, fmt.Sprintf("mysql -h$%s -P$%s -u$%s -e %s",
+		engines.EnvVarMap[engines.HOST],
+		engines.EnvVarMap[engines.PORT],
+		engines.EnvVarMap[engines.USER],
+		strconv.Quote(strings.Join(scripts, " "))))
+
+	envs := []corev1.EnvVar{
+		{
+			Name:  "MYSQL_PWD",
+			Value: fmt.Sprintf("$(%s)", engines.EnvVarMap[engines.PASSWORD]),
+		},
+	}
+	return cmd, envs, nil
 }
@@ -24,6 +24,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/apecloud/dbctl/engines"
 )
@@ -56,4 +57,14 @@ var _ = Describe("Foxlake Engine", func() {
 
 		Expect(foxlake.ConnectExample(info, "")).ShouldNot(BeZero())
 	})
+
+	It("execute command", func() {
+		foxlake := NewCommands()
+
+		cmd, envs, err := foxlake.ExecuteCommand([]string{"show databases;"})
+		Expect(err).Should(Succeed())
+		Expect(cmd).Should(Equal([]string{"/bin/sh", "-c", "-ex", `mysql -h$KB_HOST -P$KB_PORT -u$KB_USER -e "show databases;"`}))
+		// the password is not in the command line
+		Expect(envs).Should(Equal([]corev1.EnvVar{{Name: "MYSQL_PWD", Value: "$(KB_PASSWD)"}}))
+	})
 })
@@ -0,0 +1,65 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package foxlake
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/apecloud/dbctl/constant"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const (
+	// EnvRootUser and EnvRootPassword are the root credentials of FoxLake, the
+	// same as the cluster commands.
+	EnvRootUser     = "FOXLAKE_ROOT_USER"
+	EnvRootPassword = "FOXLAKE_ROOT_PASSWORD"
+	// EnvRole is the role of the local FoxLake, coordinator or worker, it's guessed
+	// from the component name if not set.
+	EnvRole = "FOXLAKE_ROLE"
+)
+
+// the roles of FoxLake, a coordinator plans the queries and keeps the catalog in
+// foxlake-metadb, while the MPP workers execute the fragments of the queries.
+const (
+	Coordinator = "coordinator"
+	Worker      = "worker"
+)
+
+// roles maps the roles of FoxLake, all the coordinators take the writes, and the
+// workers hold no data.
+var roles = map[string]models.RoleInfo{
+	Coordinator: {Role: models.PRIMARY, Writable: true, Voter: true, Health: models.HealthHealthy},
+	Worker:      {Role: models.LEARNER, Health: models.HealthHealthy},
+}
+
+// getRole returns the role of the local FoxLake, foxlake-server of KubeBlocks is
+// a coordinator.
+func getRole() string {
+	if viper.IsSet(EnvRole) {
+		return strings.ToLower(viper.GetString(EnvRole))
+	}
+	if strings.Contains(constant.GetClusterCompName(), Worker) {
+		return Worker
+	}
+	return Coordinator
+}
@@ -0,0 +1,167 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package foxlake
+
+import (
+	"context"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/mysql"
+)
+
+// catalogSQL lists the databases from the catalog, which is kept in foxlake-metadb.
+const catalogSQL = "show databases"
+
+// Manager connects to FoxLake by the MySQL protocol with the FoxLake root credentials.
+type Manager struct {
+	mysql.Manager
+	role string
+}
+
+var _ engines.DBManager = &Manager{}
+
+func NewManager() (engines.DBManager, error) {
+	logger := ctrl.Log.WithName("FoxLake")
+	config, err := mysql.NewConfigWithEnvs(EnvRootUser, EnvRootPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	mysqlMgr, err := mysql.NewManagerWithConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := &Manager{
+		Manager: *mysqlMgr.(*mysql.Manager),
+		role:    getRole(),
+	}
+
+	mgr.SetLogger(logger)
+	return mgr, nil
+}
+
+// IsDBStartupReady waits for a coordinator to read its catalog, the catalog is in
+// foxlake-metadb, so a coordinator isn't ready until it reaches foxlake-metadb. A
+// worker is ready once it accepts connections.
+func (mgr *Manager) IsDBStartupReady() bool {
+	if mgr.DBStartupReady {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	var err error
+	if mgr.role == Coordinator {
+		err = mgr.readCatalog(ctx)
+	} else {
+		err = mgr.DB.PingContext(ctx)
+	}
+	if err != nil {
+		mgr.Logger.Info("DB is not ready", "error", err.Error())
+		return false
+	}
+
+	mgr.DBStartupReady = true
+	mgr.Logger.Info("DB startup ready")
+	return true
+}
+
+func (mgr *Manager) readCatalog(ctx context.Context) error {
+	rows, err := mgr.DB.QueryContext(ctx, catalogSQL)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// GetReplicaRole returns coordinator or worker, FoxLake has no replication, all
+// the coordinators serve the queries.
+func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return mgr.role, nil
+}
+
+// GetReplicaRoleInfo normalizes coordinator to primary and worker to learner.
+func (mgr *Manager) GetReplicaRoleInfo(ctx context.Context) (*models.RoleInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	info, ok := roles[mgr.role]
+	if !ok {
+		return models.NewRoleInfo(mgr.role), nil
+	}
+	info.NativeRole = mgr.role
+	return &info, nil
+}
+
+// GetReplicationStatus, GetMemberView and FenceMember are not implemented, the
+// data of FoxLake is in the object storage instead of replicas.
+func (mgr *Manager) GetReplicationStatus(ctx context.Context) (*models.ReplicationStatus, error) {
+	return mgr.DBManagerBase.GetReplicationStatus(ctx)
+}
+
+func (mgr *Manager) GetMemberView(ctx context.Context, address string) (*models.MemberView, error) {
+	return mgr.DBManagerBase.GetMemberView(ctx, address)
+}
+
+func (mgr *Manager) FenceMember(ctx context.Context, address string) error {
+	return mgr.DBManagerBase.FenceMember(ctx, address)
+}
+
+// ClassifyParameter and SetParameter are not implemented, FoxLake doesn't support
+// SET PERSIST of MySQL.
+func (mgr *Manager) ClassifyParameter(context.Context, string) (models.ParameterKind, error) {
+	return "", models.ErrNotImplemented
+}
+
+func (mgr *Manager) SetParameter(context.Context, string, string) error {
+	return models.ErrNotImplemented
+}
+
+// CheckConfigDrift is not implemented, FoxLake is not configured by my.cnf.
+func (mgr *Manager) CheckConfigDrift(context.Context, string) (*models.ConfigDriftReport, error) {
+	return nil, models.ErrNotImplemented
+}
+
+// ListSessions and KillSession are not implemented, FoxLake has no processlist
+// of MySQL.
+func (mgr *Manager) ListSessions(context.Context) ([]models.Session, error) {
+	return nil, models.ErrNotImplemented
+}
+
+func (mgr *Manager) KillSession(context.Context, string) error {
+	return models.ErrNotImplemented
+}
+
+// Promote and Demote are not implemented, there are no replicas to switch over.
+func (mgr *Manager) Promote(context.Context) error {
+	return models.ErrNotImplemented
+}
+
+func (mgr *Manager) Demote(context.Context, string) error {
+	return models.ErrNotImplemented
+}
@@ -0,0 +1,128 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package foxlake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/constant"
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/conformance"
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/mysql"
+)
+
+func mockDatabase(t *testing.T, role string) (*Manager, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	manager := &Manager{
+		Manager: mysql.Manager{
+			DBManagerBase: engines.DBManagerBase{
+				CurrentMemberName: "foxlake-server-0",
+				Logger:            ctrl.Log.WithName("FoxLake-TEST"),
+			},
+			DB: db,
+		},
+		role: role,
+	}
+	return manager, mock
+}
+
+func TestIsDBStartupReady(t *testing.T) {
+	manager, mock := mockDatabase(t, Coordinator)
+
+	mock.ExpectQuery(catalogSQL).WillReturnError(sqlmock.ErrCancelled)
+	assert.False(t, manager.IsDBStartupReady())
+
+	mock.ExpectQuery(catalogSQL).WillReturnRows(sqlmock.NewRows([]string{"Database"}).AddRow("information_schema"))
+	assert.True(t, manager.IsDBStartupReady())
+	assert.True(t, manager.IsDBStartupReady())
+
+	manager, mock = mockDatabase(t, Worker)
+	mock.ExpectPing()
+	assert.True(t, manager.IsDBStartupReady())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetReplicaRole(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set(constant.KBEnvClusterCompName, "foxlake-cluster-foxlake-server")
+	assert.Equal(t, Coordinator, getRole())
+	viper.Set(constant.KBEnvClusterCompName, "foxlake-cluster-mpp-worker")
+	assert.Equal(t, Worker, getRole())
+	viper.Set(EnvRole, "Coordinator")
+	assert.Equal(t, Coordinator, getRole())
+
+	manager, _ := mockDatabase(t, Worker)
+	role, err := manager.GetReplicaRole(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, Worker, role)
+
+	info, err := manager.GetReplicaRoleInfo(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, &models.RoleInfo{Role: models.LEARNER, NativeRole: Worker, Health: models.HealthHealthy}, info)
+}
+
+func TestConformance(t *testing.T) {
+	manager, mock := mockDatabase(t, Coordinator)
+
+	conformance.Run(t, conformance.Suite{
+		Manager: manager,
+		Prepare: func(method conformance.Method) {
+			switch method {
+			case conformance.IsDBStartupReady:
+				mock.ExpectQuery(catalogSQL).WillReturnRows(sqlmock.NewRows([]string{"Database"}).AddRow("information_schema"))
+			case conformance.Exec:
+				mock.ExpectExec("create database test").WillReturnResult(sqlmock.NewResult(0, 1))
+			case conformance.Query:
+				mock.ExpectQuery("select 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+			}
+		},
+		ExecSQL:  "create database test",
+		QuerySQL: "select 1",
+	})
+}
+
+func TestMySQLCapabilitiesNotImplemented(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock := mockDatabase(t, Coordinator)
+
+	_, err := manager.ClassifyParameter(ctx, "max_connections")
+	assert.ErrorIs(t, err, models.ErrNotImplemented)
+	assert.ErrorIs(t, manager.SetParameter(ctx, "max_connections", "200"), models.ErrNotImplemented)
+	_, err = manager.CheckConfigDrift(ctx, "")
+	assert.ErrorIs(t, err, models.ErrNotImplemented)
+	_, err = manager.ListSessions(ctx)
+	assert.ErrorIs(t, err, models.ErrNotImplemented)
+	assert.ErrorIs(t, manager.KillSession(ctx, "12"), models.ErrNotImplemented)
+	assert.ErrorIs(t, manager.Promote(ctx), models.ErrNotImplemented)
+	assert.ErrorIs(t, manager.Demote(ctx, "foxlake-server-1"), models.ErrNotImplemented)
+	// nothing is sent to FoxLake
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -21,15 +21,90 @@ package engines
 
 import (
 	"context"
+
+	"github.com/apecloud/dbctl/engines/models"
 )
 
 type DBManager interface {
 	IsDBStartupReady() bool
 
 	GetReplicaRole(context.Context) (string, error)
+	GetReplicationStatus(context.Context) (*models.ReplicationStatus, error)
+	GetTopology(context.Context) (*models.Topology, error)
+	// GetMemberView and FenceMember connect to the member at the address in the
+	// topology, the local member is connected if the address is empty.
+	GetMemberView(ctx context.Context, address string) (*models.MemberView, error)
+	FenceMember(ctx context.Context, address string) error
 
 	Exec(context.Context, string) (int64, error)
 	Query(context.Context, string) ([]byte, error)
 
 	ShutDownWithWait()
 }
+
+// RoleInfoGetter is implemented by the managers which know more about the role
+// than its name, e.g. the voting membership from the replica set config.
+type RoleInfoGetter interface {
+	GetReplicaRoleInfo(context.Context) (*models.RoleInfo, error)
+}
+
+// GetReplicaRoleInfo returns the structured role of the replica, the role name
+// from GetReplicaRole is normalized if the manager is not a RoleInfoGetter.
+func GetReplicaRoleInfo(ctx context.Context, mgr DBManager) (*models.RoleInfo, error) {
+	if getter, ok := mgr.(RoleInfoGetter); ok {
+		return getter.GetReplicaRoleInfo(ctx)
+	}
+
+	role, err := mgr.GetReplicaRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewRoleInfo(role), nil
+}
+
+// Switcher is implemented by the managers which can switch the role of the local
+// member, it's used by the HA controller to fail over.
+type Switcher interface {
+	// Promote makes the local member a writable primary.
+	Promote(context.Context) error
+	// Demote makes the local member a read-only replica of the leader at the
+	// address, it's also called to follow a new leader.
+	Demote(ctx context.Context, leader string) error
+}
+
+// Reconfigurer is implemented by the managers which can change the parameters of
+// the database online.
+type Reconfigurer interface {
+	// ClassifyParameter tells whether the parameter is dynamic or static, it returns
+	// models.ErrUnknownParameter or models.ErrReadOnlyParameter if the parameter
+	// can't be changed.
+	ClassifyParameter(ctx context.Context, name string) (models.ParameterKind, error)
+	// SetParameter applies the dynamic parameter online, and persists it if the
+	// engine can, so that it's kept after the database restarts. A static parameter
+	// is only persisted to be taken at the next start, or models.ErrNotPersisted
+	// is returned if it can't be. models.ErrRestartRequired is returned for a parameter
+	// found static only when it's applied, after it's persisted.
+	SetParameter(ctx context.Context, name, value string) error
+}
+
+// ConfigDriftChecker is implemented by the managers which can compare the config
+// files with the parameters the database is running with.
+type ConfigDriftChecker interface {
+	// CheckConfigDrift parses the config file, the one the database is started
+	// with if file is empty, and compares its parameters with the live values.
+	CheckConfigDrift(ctx context.Context, file string) (*models.ConfigDriftReport, error)
+}
+
+// SessionManager is implemented by the managers which can list and kill the client
+// sessions, or the running operations of MongoDB.
+type SessionManager interface {
+	// ListSessions returns the sessions except the one of the manager itself.
+	ListSessions(ctx context.Context) ([]models.Session, error)
+	KillSession(ctx context.Context, id string) error
+}
+
+// Pinger is implemented by the managers which can check the connection to the
+// database on every call, unlike IsDBStartupReady which is kept once it's ready.
+type Pinger interface {
+	Ping(context.Context) error
+}
@@ -0,0 +1,119 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package kafka
+
+import (
+	"crypto/tls"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"github.com/apecloud/dbctl/constant"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+const (
+	// configSection is the config file section of Kafka, the address is of a
+	// broker listener, which bootstraps the client to the whole cluster.
+	configSection = "kafka"
+
+	defaultHost    = "127.0.0.1"
+	defaultPort    = 9092
+	defaultTimeout = 5 * time.Second
+
+	// EnvSASLMechanism is the SASL mechanism to authenticate with, one of PLAIN,
+	// SCRAM-SHA-256 and SCRAM-SHA-512. The client authenticates with PLAIN if it's
+	// not set but the username is.
+	EnvSASLMechanism = "KAFKA_SASL_MECHANISM"
+	// EnvNodeID and EnvBitnamiNodeID are the node.id of the local node, which is
+	// looked up by the host of the brokers if neither is set. The node id of a
+	// controller-only node has to be set, since it's not listed as a broker.
+	EnvNodeID        = "KAFKA_NODE_ID"
+	EnvBitnamiNodeID = "KAFKA_CFG_NODE_ID"
+	// EnvBitnamiBrokerID is the broker.id of a broker in the ZooKeeper mode.
+	EnvBitnamiBrokerID = "KAFKA_CFG_BROKER_ID"
+)
+
+// the SASL mechanisms.
+const (
+	SASLPlain       = "PLAIN"
+	SASLScramSHA256 = "SCRAM-SHA-256"
+	SASLScramSHA512 = "SCRAM-SHA-512"
+)
+
+// unknownNodeID is the node id if it's not set by env.
+const unknownNodeID int32 = -1
+
+type Config struct {
+	Addr          string
+	TLSConfig     *tls.Config
+	Timeout       time.Duration
+	SASLMechanism string
+	// NodeID is the node id of the local node, or unknownNodeID if it's not set.
+	NodeID int32
+
+	*utilconfig.EngineCredentials
+}
+
+func NewConfig() (*Config, error) {
+	engineConfig, err := utilconfig.GetEngineConfig(configSection)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{
+		EngineCredentials: utilconfig.NewEngineCredentials(configSection,
+			utilconfig.CredentialLookup{Name: constant.ConfigKeyUserName, Envs: []string{constant.KBEnvServiceUser}},
+			utilconfig.CredentialLookup{Name: constant.ConfigKeyPassword, Envs: []string{constant.KBEnvServicePassword}}),
+		Addr:    engineConfig.Addr(defaultHost, defaultPort),
+		Timeout: defaultTimeout,
+		NodeID:  unknownNodeID,
+	}
+	if config.TLSConfig, err = engineConfig.TLS.ClientConfig(); err != nil {
+		return nil, err
+	}
+	if engineConfig.ReadTimeout != 0 {
+		config.Timeout = engineConfig.ReadTimeout
+	}
+	for _, env := range []string{EnvNodeID, EnvBitnamiNodeID, EnvBitnamiBrokerID} {
+		if viper.IsSet(env) {
+			config.NodeID = viper.GetInt32(env)
+			break
+		}
+	}
+	config.LoadCredentials(engineConfig)
+
+	if viper.IsSet(EnvSASLMechanism) {
+		config.SASLMechanism = strings.ToUpper(viper.GetString(EnvSASLMechanism))
+	}
+	switch config.SASLMechanism {
+	case "":
+		if username, _ := config.GetCredentials(); username != "" {
+			config.SASLMechanism = SASLPlain
+		}
+	case SASLPlain, SASLScramSHA256, SASLScramSHA512:
+	default:
+		return nil, errors.Errorf("unsupported SASL mechanism %s, the supported are %s, %s and %s",
+			config.SASLMechanism, SASLPlain, SASLScramSHA256, SASLScramSHA512)
+	}
+	return config, nil
+}
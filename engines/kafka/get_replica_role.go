@@ -0,0 +1,253 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package kafka
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// the roles of the Kafka nodes. The active controller leads the KRaft metadata
+// quorum, or is the controller elected in ZooKeeper, a controller votes in the
+// quorum and a broker only observes it.
+const (
+	ActiveController = "active-controller"
+	Controller       = "controller"
+	Broker           = "broker"
+)
+
+// nodeRoles maps the roles of the Kafka nodes, a broker observes the quorum.
+var nodeRoles = map[string]models.RoleInfo{
+	ActiveController: {Role: models.LEADER, Writable: true, Voter: true, Health: models.HealthHealthy},
+	Controller:       {Role: models.FOLLOWER, Voter: true, Health: models.HealthHealthy},
+	Broker:           {Role: models.LEARNER, Health: models.HealthHealthy},
+}
+
+// metadataTopic is the topic of the KRaft metadata log, which has one partition.
+const metadataTopic = "__cluster_metadata"
+
+// clusterState is the cluster as the local node sees it.
+type clusterState struct {
+	nodeID  int32
+	cluster *kmsg.DescribeClusterResponse
+	// quorum is the partition of the metadata log, nil in the ZooKeeper mode.
+	quorum *kmsg.DescribeQuorumResponseTopicPartition
+}
+
+// GetReplicaRole returns active-controller, controller or broker.
+func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
+	state, err := mgr.getClusterState(ctx)
+	if err != nil {
+		return "", err
+	}
+	return state.role(state.nodeID), nil
+}
+
+// GetReplicaRoleInfo normalizes the role, a node is writable if it's a broker,
+// which takes the writes of the partitions it leads. The health is of the
+// partitions replicated by the local broker: unhealthy if any of them is offline,
+// or recovering if the local replica of any is out of the ISR.
+func (mgr *Manager) GetReplicaRoleInfo(ctx context.Context) (*models.RoleInfo, error) {
+	state, err := mgr.getClusterState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info := newRoleInfo(state.role(state.nodeID))
+	info.Writable = state.isBroker(state.nodeID)
+	if !info.Writable {
+		return info, nil
+	}
+	topics, err := mgr.admin.ListTopicsWithInternal(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "list topics failed")
+	}
+	info.Health = replicaHealth(state.nodeID, topics)
+	return info, nil
+}
+
+func newRoleInfo(role string) *models.RoleInfo {
+	info, ok := nodeRoles[role]
+	if !ok {
+		return models.NewRoleInfo(role)
+	}
+	info.NativeRole = role
+	return &info
+}
+
+func (mgr *Manager) getClusterState(ctx context.Context) (*clusterState, error) {
+	cluster, err := mgr.describeCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+	state := &clusterState{
+		nodeID:  mgr.config.NodeID,
+		cluster: cluster,
+	}
+	if state.nodeID == unknownNodeID {
+		for _, broker := range cluster.Brokers {
+			if mgr.isLocalHost(broker.Host) {
+				state.nodeID = broker.NodeID
+			}
+		}
+	}
+	if state.nodeID == unknownNodeID {
+		return nil, errors.Errorf("%s is not found in the brokers, set %s if it's a controller-only node",
+			mgr.CurrentMemberName, EnvNodeID)
+	}
+	if state.quorum, err = mgr.describeQuorum(ctx); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// isLocalHost tells whether the host is the local pod, the brokers advertise
+// their FQDNs in KubeBlocks.
+func (mgr *Manager) isLocalHost(host string) bool {
+	return host == mgr.CurrentMemberName || strings.HasPrefix(host, mgr.CurrentMemberName+".")
+}
+
+func (mgr *Manager) describeCluster(ctx context.Context) (*kmsg.DescribeClusterResponse, error) {
+	resp, err := kmsg.NewPtrDescribeClusterRequest().RequestWith(ctx, mgr.client)
+	if err != nil {
+		return nil, errors.Wrap(err, "describe cluster failed")
+	}
+	if err = kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		return nil, errors.Wrapf(err, "describe cluster failed: %s", stringValue(resp.ErrorMessage))
+	}
+	return resp, nil
+}
+
+// describeQuorum describes the metadata quorum, it returns nil if the brokers
+// don't serve DescribeQuorum, which is the ZooKeeper mode.
+func (mgr *Manager) describeQuorum(ctx context.Context) (*kmsg.DescribeQuorumResponseTopicPartition, error) {
+	req := kmsg.NewPtrDescribeQuorumRequest()
+	supported, err := mgr.supportsKey(ctx, req.Key())
+	if err != nil || !supported {
+		return nil, err
+	}
+
+	topic := kmsg.NewDescribeQuorumRequestTopic()
+	topic.Topic = metadataTopic
+	topic.Partitions = append(topic.Partitions, kmsg.NewDescribeQuorumRequestTopicPartition())
+	req.Topics = append(req.Topics, topic)
+	resp, err := req.RequestWith(ctx, mgr.client)
+	if err != nil {
+		return nil, errors.Wrap(err, "describe quorum failed")
+	}
+	if err = kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		return nil, errors.Wrap(err, "describe quorum failed")
+	}
+	for _, topic := range resp.Topics {
+		if topic.Topic != metadataTopic || len(topic.Partitions) == 0 {
+			continue
+		}
+		partition := &topic.Partitions[0]
+		if err = kerr.ErrorForCode(partition.ErrorCode); err != nil {
+			return nil, errors.Wrapf(err, "describe quorum of %s failed", metadataTopic)
+		}
+		return partition, nil
+	}
+	return nil, errors.Errorf("%s is not found by describe quorum", metadataTopic)
+}
+
+// supportsKey tells whether the brokers serve the request.
+func (mgr *Manager) supportsKey(ctx context.Context, key int16) (bool, error) {
+	resp, err := kmsg.NewPtrApiVersionsRequest().RequestWith(ctx, mgr.client)
+	if err != nil {
+		return false, errors.Wrap(err, "get api versions failed")
+	}
+	if err = kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		return false, errors.Wrap(err, "get api versions failed")
+	}
+	return slices.ContainsFunc(resp.ApiKeys, func(apiKey kmsg.ApiVersionsResponseApiKey) bool {
+		return apiKey.ApiKey == key
+	}), nil
+}
+
+func (state *clusterState) role(nodeID int32) string {
+	if state.quorum == nil {
+		if state.cluster.ControllerID == nodeID {
+			return ActiveController
+		}
+		return Broker
+	}
+
+	if state.quorum.LeaderID == nodeID {
+		return ActiveController
+	}
+	for _, voter := range state.quorum.CurrentVoters {
+		if voter.ReplicaID == nodeID {
+			return Controller
+		}
+	}
+	return Broker
+}
+
+// isBroker tells whether the node is an unfenced broker.
+func (state *clusterState) isBroker(nodeID int32) bool {
+	return slices.ContainsFunc(state.cluster.Brokers, func(broker kmsg.DescribeClusterResponseBroker) bool {
+		return broker.NodeID == nodeID
+	})
+}
+
+// replicaHealth returns the health of the partitions replicated by the broker.
+func replicaHealth(nodeID int32, topics kadm.TopicDetails) string {
+	health := models.HealthHealthy
+	for _, topic := range topics {
+		for _, partition := range topic.Partitions {
+			if !slices.Contains(partition.Replicas, nodeID) {
+				continue
+			}
+			if isOffline(partition) {
+				return models.HealthUnhealthy
+			}
+			if !slices.Contains(partition.ISR, nodeID) {
+				health = models.HealthRecovering
+			}
+		}
+	}
+	return health
+}
+
+// isOffline tells whether the partition has no leader.
+func isOffline(partition kadm.PartitionDetail) bool {
+	return partition.Leader < 0
+}
+
+// isUnderReplicated tells whether any replica of the partition is out of the ISR.
+func isUnderReplicated(partition kadm.PartitionDetail) bool {
+	return len(partition.ISR) < len(partition.Replicas)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
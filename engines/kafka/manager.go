@@ -0,0 +1,143 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package kafka
+
+import (
+	"context"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/kversion"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+// Manager talks to the cluster by the Kafka protocol, the local broker bootstraps
+// the client and the admin requests are routed to the brokers which serve them.
+type Manager struct {
+	engines.DBManagerBase
+	config *Config
+	client *kgo.Client
+	admin  *kadm.Client
+}
+
+var _ engines.DBManager = &Manager{}
+
+func NewManager() (engines.DBManager, error) {
+	logger := ctrl.Log.WithName("Kafka")
+	config, err := NewConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	managerBase, err := engines.NewDBManagerBase(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := &Manager{
+		DBManagerBase: *managerBase,
+		config:        config,
+		client:        client,
+		admin:         kadm.NewClient(client),
+	}
+
+	utilconfig.OnCredentialsChange(mgr.reloadCredentials)
+	return mgr, nil
+}
+
+// newClient creates the client, the SASL credentials are read for every new
+// connection, so the rotated credentials take effect without a new client.
+func newClient(config *Config) (*kgo.Client, error) {
+	// the stable versions are of the ZooKeeper brokers, which don't serve
+	// DescribeQuorum, so it's allowed for the KRaft brokers
+	versions := kversion.Stable()
+	describeQuorum := kmsg.NewPtrDescribeQuorumRequest()
+	versions.SetMaxKeyVersion(describeQuorum.Key(), describeQuorum.MaxVersion())
+
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(config.Addr),
+		kgo.MaxVersions(versions),
+		kgo.DialTimeout(config.Timeout),
+		kgo.RetryTimeout(config.Timeout),
+	}
+	if config.TLSConfig != nil {
+		opts = append(opts, kgo.DialTLSConfig(config.TLSConfig))
+	}
+
+	scramAuth := func(context.Context) (scram.Auth, error) {
+		username, password := config.GetCredentials()
+		return scram.Auth{User: username, Pass: password}, nil
+	}
+	switch config.SASLMechanism {
+	case SASLPlain:
+		opts = append(opts, kgo.SASL(plain.Plain(func(context.Context) (plain.Auth, error) {
+			username, password := config.GetCredentials()
+			return plain.Auth{User: username, Pass: password}, nil
+		})))
+	case SASLScramSHA256:
+		opts = append(opts, kgo.SASL(scram.Sha256(scramAuth)))
+	case SASLScramSHA512:
+		opts = append(opts, kgo.SASL(scram.Sha512(scramAuth)))
+	}
+	return kgo.NewClient(opts...)
+}
+
+// reloadCredentials reloads the credentials, which are read for every new connection.
+func (mgr *Manager) reloadCredentials() {
+	if err := mgr.config.ReloadCredentials(); err != nil {
+		mgr.Logger.Info("reload credentials failed", "error", err.Error())
+		return
+	}
+	mgr.Logger.Info("credentials reloaded")
+}
+
+// IsDBStartupReady checks the cluster can be described, which requires the local
+// broker to be reachable and the brokers to be registered to the controller.
+func (mgr *Manager) IsDBStartupReady() bool {
+	if mgr.DBStartupReady {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mgr.config.Timeout)
+	defer cancel()
+	if _, err := mgr.describeCluster(ctx); err != nil {
+		mgr.Logger.Info("DB is not ready", "error", err.Error())
+		return false
+	}
+
+	mgr.DBStartupReady = true
+	mgr.Logger.Info("DB startup ready")
+	return true
+}
+
+func (mgr *Manager) ShutDownWithWait() {
+	mgr.client.Close()
+}
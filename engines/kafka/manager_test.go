@@ -0,0 +1,340 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/conformance"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// mockCluster is a fake cluster of 3 brokers, kfake doesn't serve DescribeCluster
+// and DescribeQuorum, they are answered by the control functions.
+type mockCluster struct {
+	*kfake.Cluster
+	// kraft is false for the ZooKeeper mode, DescribeQuorum is not served then.
+	kraft bool
+}
+
+func newMockCluster(t *testing.T, kraft bool) *mockCluster {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(3), kfake.SeedTopics(2, "orders"))
+	require.NoError(t, err)
+	t.Cleanup(cluster.Close)
+	mock := &mockCluster{Cluster: cluster, kraft: kraft}
+
+	client, err := kgo.NewClient(kgo.SeedBrokers(cluster.ListenAddrs()...))
+	require.NoError(t, err)
+	versions, err := kmsg.NewPtrApiVersionsRequest().RequestWith(context.Background(), client)
+	client.Close()
+	require.NoError(t, err)
+
+	cluster.ControlKey(kmsg.ApiVersions.Int16(), func(req kmsg.Request) (kmsg.Response, error, bool) {
+		cluster.KeepControl()
+		resp := req.ResponseKind().(*kmsg.ApiVersionsResponse)
+		resp.ApiKeys = append(resp.ApiKeys, versions.ApiKeys...)
+		added := []kmsg.Request{kmsg.NewPtrDescribeClusterRequest()}
+		if mock.kraft {
+			added = append(added, kmsg.NewPtrDescribeQuorumRequest())
+		}
+		for _, r := range added {
+			resp.ApiKeys = append(resp.ApiKeys, kmsg.ApiVersionsResponseApiKey{ApiKey: r.Key(), MaxVersion: r.MaxVersion()})
+		}
+		return resp, nil, true
+	})
+	cluster.ControlKey(kmsg.DescribeCluster.Int16(), func(req kmsg.Request) (kmsg.Response, error, bool) {
+		cluster.KeepControl()
+		resp := req.ResponseKind().(*kmsg.DescribeClusterResponse)
+		resp.ClusterID = "kafka-test"
+		resp.ControllerID = 1
+		for i, addr := range cluster.ListenAddrs() {
+			host, port, _ := net.SplitHostPort(addr)
+			broker := kmsg.NewDescribeClusterResponseBroker()
+			broker.NodeID = int32(i)
+			broker.Host = host
+			broker.Port = int32(mustAtoi(port))
+			resp.Brokers = append(resp.Brokers, broker)
+		}
+		return resp, nil, true
+	})
+	cluster.ControlKey(kmsg.DescribeQuorum.Int16(), func(req kmsg.Request) (kmsg.Response, error, bool) {
+		cluster.KeepControl()
+		resp := req.ResponseKind().(*kmsg.DescribeQuorumResponse)
+		topic := kmsg.NewDescribeQuorumResponseTopic()
+		topic.Topic = metadataTopic
+		partition := kmsg.NewDescribeQuorumResponseTopicPartition()
+		partition.LeaderID = 0
+		partition.LeaderEpoch = 7
+		partition.HighWatermark = 100
+		partition.CurrentVoters = []kmsg.DescribeQuorumResponseTopicPartitionReplicaState{
+			{ReplicaID: 0, LogEndOffset: 100},
+			{ReplicaID: 1, LogEndOffset: 98},
+		}
+		// node 5 is a fenced broker
+		partition.Observers = []kmsg.DescribeQuorumResponseTopicPartitionReplicaState{
+			{ReplicaID: 2, LogEndOffset: 90},
+			{ReplicaID: 5, LogEndOffset: 50},
+		}
+		topic.Partitions = append(topic.Partitions, partition)
+		resp.Topics = append(resp.Topics, topic)
+		return resp, nil, true
+	})
+	return mock
+}
+
+func mustAtoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func mockManager(t *testing.T, cluster *mockCluster, nodeID int32) *Manager {
+	config := &Config{
+		Addr:   cluster.ListenAddrs()[0],
+		NodeID: nodeID,
+	}
+	client, err := newClient(config)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	return &Manager{
+		DBManagerBase: engines.DBManagerBase{
+			CurrentMemberName: "kafka-0",
+			Logger:            ctrl.Log.WithName("Kafka-TEST"),
+		},
+		config: config,
+		client: client,
+		admin:  kadm.NewClient(client),
+	}
+}
+
+func TestIsDBStartupReady(t *testing.T) {
+	mgr := mockManager(t, newMockCluster(t, true), 0)
+	mgr.config.Timeout = defaultTimeout
+	assert.True(t, mgr.IsDBStartupReady())
+}
+
+func TestGetReplicaRole(t *testing.T) {
+	ctx := context.TODO()
+	cluster := newMockCluster(t, true)
+
+	for nodeID, want := range map[int32]string{0: ActiveController, 1: Controller, 2: Broker} {
+		role, err := mockManager(t, cluster, nodeID).GetReplicaRole(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, want, role)
+	}
+
+	// the controller is elected in ZooKeeper
+	cluster.kraft = false
+	for nodeID, want := range map[int32]string{0: Broker, 1: ActiveController} {
+		role, err := mockManager(t, cluster, nodeID).GetReplicaRole(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, want, role)
+	}
+
+	// the local node is looked up by the host of the brokers
+	_, err := mockManager(t, cluster, unknownNodeID).GetReplicaRole(ctx)
+	assert.ErrorContains(t, err, EnvNodeID)
+}
+
+func TestGetReplicaRoleInfo(t *testing.T) {
+	ctx := context.TODO()
+	cluster := newMockCluster(t, true)
+
+	info, err := mockManager(t, cluster, 0).GetReplicaRoleInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &models.RoleInfo{
+		Role:       models.LEADER,
+		NativeRole: ActiveController,
+		Writable:   true,
+		Voter:      true,
+		Health:     models.HealthHealthy,
+	}, info)
+
+	// a fenced broker takes no writes
+	info, err = mockManager(t, cluster, 5).GetReplicaRoleInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Broker, info.NativeRole)
+	assert.False(t, info.Writable)
+}
+
+func TestReplicaHealth(t *testing.T) {
+	topics := kadm.TopicDetails{
+		"orders": {
+			Topic: "orders",
+			Partitions: kadm.PartitionDetails{
+				0: {Partition: 0, Leader: 0, Replicas: []int32{0, 1, 2}, ISR: []int32{0, 1}},
+				1: {Partition: 1, Leader: -1, Replicas: []int32{1}, ISR: []int32{}},
+			},
+		},
+	}
+
+	assert.Equal(t, models.HealthHealthy, replicaHealth(0, topics))
+	assert.Equal(t, models.HealthRecovering, replicaHealth(2, topics))
+	assert.Equal(t, models.HealthUnhealthy, replicaHealth(1, topics))
+	assert.Equal(t, models.HealthHealthy, replicaHealth(3, topics))
+}
+
+func TestGetTopology(t *testing.T) {
+	cluster := newMockCluster(t, true)
+	mgr := mockManager(t, cluster, 2)
+
+	topology, err := mgr.GetTopology(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), topology.Term)
+	require.Len(t, topology.Members, 4)
+
+	lag := func(n int64) *int64 { return &n }
+	members := topology.Members
+	assert.Equal(t, "0", members[0].Name)
+	assert.Equal(t, cluster.ListenAddrs()[0], members[0].Address)
+	assert.Equal(t, models.LEADER, members[0].Role)
+	assert.Equal(t, lag(0), members[0].LagEntries)
+	assert.Equal(t, models.FOLLOWER, members[1].Role)
+	assert.Equal(t, lag(2), members[1].LagEntries)
+	assert.Equal(t, models.LEARNER, members[2].Role)
+	assert.Equal(t, lag(10), members[2].LagEntries)
+	assert.True(t, members[2].Self)
+	assert.Equal(t, models.HealthHealthy, members[2].Health)
+	assert.Equal(t, "5", members[3].Name)
+	assert.Empty(t, members[3].Address)
+	assert.Equal(t, models.HealthUnhealthy, members[3].Health)
+
+	cluster.kraft = false
+	topology, err = mgr.GetTopology(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, topology.Members, 3)
+	assert.Equal(t, models.LEADER, topology.Members[1].Role)
+}
+
+func TestQuery(t *testing.T) {
+	ctx := context.TODO()
+	mgr := mockManager(t, newMockCluster(t, true), 0)
+
+	data, err := mgr.Query(ctx, "describe-cluster")
+	require.NoError(t, err)
+	summary := &clusterSummary{}
+	require.NoError(t, json.Unmarshal(data, summary))
+	assert.Equal(t, "kafka-test", summary.ClusterID)
+	assert.Equal(t, int32(0), summary.ControllerID)
+	assert.Len(t, summary.Brokers, 3)
+	assert.Equal(t, []int32{0, 1}, summary.Quorum.Voters)
+	assert.Equal(t, 0, summary.OfflinePartitions)
+
+	data, err = mgr.Query(ctx, "list-topics")
+	require.NoError(t, err)
+	var topics []topicSummary
+	require.NoError(t, json.Unmarshal(data, &topics))
+	require.Len(t, topics, 1)
+	assert.Equal(t, "orders", topics[0].Topic)
+	assert.Equal(t, 2, topics[0].Partitions)
+
+	data, err = mgr.Query(ctx, "describe-topic orders")
+	require.NoError(t, err)
+	var partitions []partitionInfo
+	require.NoError(t, json.Unmarshal(data, &partitions))
+	assert.Len(t, partitions, 2)
+
+	_, err = mgr.Query(ctx, "describe-topic missing")
+	assert.Error(t, err)
+
+	_, err = mgr.admin.CommitOffsets(ctx, "consumers", kadm.OffsetsFromRecords(kgo.Record{Topic: "orders", Partition: 0, Offset: 0}))
+	require.NoError(t, err)
+	data, err = mgr.Query(ctx, "describe-lag consumers")
+	require.NoError(t, err)
+	var lags []partitionLag
+	require.NoError(t, json.Unmarshal(data, &lags))
+	require.NotEmpty(t, lags)
+	assert.Equal(t, "orders", lags[0].Topic)
+
+	data, err = mgr.Query(ctx, "list-groups")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"group":"consumers"`)
+
+	_, err = mgr.Query(ctx, "describe-topic")
+	assert.ErrorContains(t, err, "the usage is")
+	_, err = mgr.Query(ctx, "drop-topic orders")
+	assert.ErrorContains(t, err, "unknown command")
+}
+
+func TestExec(t *testing.T) {
+	ctx := context.TODO()
+	mgr := mockManager(t, newMockCluster(t, true), 0)
+
+	_, err := mgr.Exec(ctx, "create-topic events partitions=3 replication-factor=2 retention.ms=60000")
+	require.NoError(t, err)
+	data, err := mgr.Query(ctx, "describe-topic events")
+	require.NoError(t, err)
+	var partitions []partitionInfo
+	require.NoError(t, json.Unmarshal(data, &partitions))
+	require.Len(t, partitions, 3)
+	assert.Len(t, partitions[0].Replicas, 2)
+
+	_, err = mgr.Exec(ctx, "create-topic events")
+	assert.Error(t, err)
+	_, err = mgr.Exec(ctx, "create-topic bad partitions=x")
+	assert.ErrorContains(t, err, "invalid partitions")
+
+	_, err = mgr.Exec(ctx, "alter-configs events retention.ms=120000 cleanup.policy=compact")
+	require.NoError(t, err)
+	data, err = mgr.Query(ctx, "describe-configs events")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"name":"retention.ms","value":"120000"`)
+	_, err = mgr.Exec(ctx, "alter-configs events retention.ms=")
+	require.NoError(t, err)
+	data, err = mgr.Query(ctx, "describe-configs events")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"value":"120000"`)
+
+	_, err = mgr.Exec(ctx, "create-partitions events 5")
+	require.NoError(t, err)
+	data, err = mgr.Query(ctx, "describe-topic events")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &partitions))
+	assert.Len(t, partitions, 5)
+
+	_, err = mgr.Exec(ctx, "delete-topic events")
+	require.NoError(t, err)
+	_, err = mgr.Query(ctx, "describe-topic events")
+	assert.Error(t, err)
+}
+
+func TestConformance(t *testing.T) {
+	mgr := mockManager(t, newMockCluster(t, true), 0)
+	mgr.config.Timeout = defaultTimeout
+
+	conformance.Run(t, conformance.Suite{
+		Manager:  mgr,
+		ExecSQL:  "alter-configs orders retention.ms=60000",
+		QuerySQL: "list-topics",
+	})
+}
@@ -0,0 +1,520 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+// the admin reads of Query.
+const (
+	cmdDescribeCluster = "describe-cluster"
+	cmdListTopics      = "list-topics"
+	cmdDescribeTopic   = "describe-topic"
+	cmdDescribeConfigs = "describe-configs"
+	cmdListGroups      = "list-groups"
+	cmdDescribeLag     = "describe-lag"
+)
+
+// the topic changes of Exec.
+const (
+	cmdCreateTopic      = "create-topic"
+	cmdDeleteTopic      = "delete-topic"
+	cmdAlterConfigs     = "alter-configs"
+	cmdCreatePartitions = "create-partitions"
+)
+
+var (
+	queryUsages = []string{
+		cmdDescribeCluster,
+		cmdListTopics,
+		cmdDescribeTopic + " <topic>",
+		cmdDescribeConfigs + " <topic>",
+		cmdListGroups,
+		cmdDescribeLag + " <group>",
+	}
+	execUsages = []string{
+		cmdCreateTopic + " <topic> [partitions=<n>] [replication-factor=<n>] [<config>=<value>]...",
+		cmdDeleteTopic + " <topic>",
+		cmdAlterConfigs + " <topic> <config>=[<value>]...",
+		cmdCreatePartitions + " <topic> <total>",
+	}
+)
+
+type clusterSummary struct {
+	ClusterID    string       `json:"clusterId"`
+	ControllerID int32        `json:"controllerId"`
+	Brokers      []brokerInfo `json:"brokers"`
+	// Quorum is the KRaft metadata quorum, nil in the ZooKeeper mode.
+	Quorum                    *quorumInfo `json:"quorum,omitempty"`
+	UnderReplicatedPartitions int         `json:"underReplicatedPartitions"`
+	OfflinePartitions         int         `json:"offlinePartitions"`
+}
+
+type brokerInfo struct {
+	NodeID int32   `json:"nodeId"`
+	Host   string  `json:"host"`
+	Port   int32   `json:"port"`
+	Rack   *string `json:"rack,omitempty"`
+}
+
+type quorumInfo struct {
+	LeaderID      int32   `json:"leaderId"`
+	LeaderEpoch   int32   `json:"leaderEpoch"`
+	HighWatermark int64   `json:"highWatermark"`
+	Voters        []int32 `json:"voters"`
+	Observers     []int32 `json:"observers"`
+}
+
+type topicSummary struct {
+	Topic                     string `json:"topic"`
+	Internal                  bool   `json:"internal,omitempty"`
+	Partitions                int    `json:"partitions"`
+	ReplicationFactor         int    `json:"replicationFactor"`
+	UnderReplicatedPartitions int    `json:"underReplicatedPartitions"`
+	OfflinePartitions         int    `json:"offlinePartitions"`
+	Error                     string `json:"error,omitempty"`
+}
+
+type partitionInfo struct {
+	Partition       int32   `json:"partition"`
+	Leader          int32   `json:"leader"`
+	LeaderEpoch     int32   `json:"leaderEpoch"`
+	Replicas        []int32 `json:"replicas"`
+	ISR             []int32 `json:"isr"`
+	OfflineReplicas []int32 `json:"offlineReplicas,omitempty"`
+	Error           string  `json:"error,omitempty"`
+}
+
+type configEntry struct {
+	Name      string  `json:"name"`
+	Value     *string `json:"value"`
+	Source    string  `json:"source"`
+	Sensitive bool    `json:"sensitive,omitempty"`
+}
+
+type groupSummary struct {
+	Group        string `json:"group"`
+	State        string `json:"state"`
+	ProtocolType string `json:"protocolType"`
+	Coordinator  int32  `json:"coordinator"`
+}
+
+type partitionLag struct {
+	Topic           string `json:"topic"`
+	Partition       int32  `json:"partition"`
+	MemberID        string `json:"memberId,omitempty"`
+	CommittedOffset int64  `json:"committedOffset"`
+	EndOffset       int64  `json:"endOffset"`
+	// Lag is -1 if the committed or the end offset can't be fetched.
+	Lag   int64  `json:"lag"`
+	Error string `json:"error,omitempty"`
+}
+
+// Query runs an admin read, e.g. "describe-topic orders", and returns the result
+// in JSON.
+func (mgr *Manager) Query(ctx context.Context, cmd string) ([]byte, error) {
+	args := strings.Fields(cmd)
+	if len(args) == 0 {
+		return nil, errors.Errorf("the command is empty, the commands are: %s", strings.Join(queryUsages, "; "))
+	}
+
+	var result any
+	var err error
+	switch args[0] {
+	case cmdDescribeCluster:
+		result, err = mgr.describeClusterSummary(ctx)
+	case cmdListTopics:
+		result, err = mgr.listTopics(ctx)
+	case cmdDescribeTopic:
+		if err = checkArgs(args, 2, queryUsages[2]); err == nil {
+			result, err = mgr.describeTopic(ctx, args[1])
+		}
+	case cmdDescribeConfigs:
+		if err = checkArgs(args, 2, queryUsages[3]); err == nil {
+			result, err = mgr.describeConfigs(ctx, args[1])
+		}
+	case cmdListGroups:
+		result, err = mgr.listGroups(ctx)
+	case cmdDescribeLag:
+		if err = checkArgs(args, 2, queryUsages[5]); err == nil {
+			result, err = mgr.describeLag(ctx, args[1])
+		}
+	default:
+		return nil, errors.Errorf("unknown command %s, the commands are: %s", args[0], strings.Join(queryUsages, "; "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(result)
+}
+
+// Exec changes the topics, e.g. "alter-configs orders retention.ms=86400000",
+// Kafka doesn't report the affected rows, so it always returns 0.
+func (mgr *Manager) Exec(ctx context.Context, cmd string) (int64, error) {
+	args := strings.Fields(cmd)
+	if len(args) == 0 {
+		return 0, errors.Errorf("the command is empty, the commands are: %s", strings.Join(execUsages, "; "))
+	}
+
+	var err error
+	switch args[0] {
+	case cmdCreateTopic:
+		if err = checkMinArgs(args, 2, execUsages[0]); err == nil {
+			err = mgr.createTopic(ctx, args[1], args[2:])
+		}
+	case cmdDeleteTopic:
+		if err = checkArgs(args, 2, execUsages[1]); err == nil {
+			err = mgr.deleteTopic(ctx, args[1])
+		}
+	case cmdAlterConfigs:
+		if err = checkMinArgs(args, 3, execUsages[2]); err == nil {
+			err = mgr.alterConfigs(ctx, args[1], args[2:])
+		}
+	case cmdCreatePartitions:
+		if err = checkArgs(args, 3, execUsages[3]); err == nil {
+			err = mgr.createPartitions(ctx, args[1], args[2])
+		}
+	default:
+		return 0, errors.Errorf("unknown command %s, the commands are: %s", args[0], strings.Join(execUsages, "; "))
+	}
+	return 0, err
+}
+
+func checkArgs(args []string, n int, usage string) error {
+	if len(args) != n {
+		return errors.Errorf("invalid command %s, the usage is: %s", strings.Join(args, " "), usage)
+	}
+	return nil
+}
+
+func checkMinArgs(args []string, n int, usage string) error {
+	if len(args) < n {
+		return errors.Errorf("invalid command %s, the usage is: %s", strings.Join(args, " "), usage)
+	}
+	return nil
+}
+
+func (mgr *Manager) describeClusterSummary(ctx context.Context) (*clusterSummary, error) {
+	cluster, err := mgr.describeCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+	quorum, err := mgr.describeQuorum(ctx)
+	if err != nil {
+		return nil, err
+	}
+	topics, err := mgr.admin.ListTopicsWithInternal(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "list topics failed")
+	}
+
+	summary := &clusterSummary{
+		ClusterID:    cluster.ClusterID,
+		ControllerID: cluster.ControllerID,
+		Brokers:      []brokerInfo{},
+	}
+	for _, broker := range cluster.Brokers {
+		summary.Brokers = append(summary.Brokers, brokerInfo{
+			NodeID: broker.NodeID,
+			Host:   broker.Host,
+			Port:   broker.Port,
+			Rack:   broker.Rack,
+		})
+	}
+	if quorum != nil {
+		summary.Quorum = &quorumInfo{
+			LeaderID:      quorum.LeaderID,
+			LeaderEpoch:   quorum.LeaderEpoch,
+			HighWatermark: quorum.HighWatermark,
+			Voters:        []int32{},
+			Observers:     []int32{},
+		}
+		for _, voter := range quorum.CurrentVoters {
+			summary.Quorum.Voters = append(summary.Quorum.Voters, voter.ReplicaID)
+		}
+		for _, observer := range quorum.Observers {
+			summary.Quorum.Observers = append(summary.Quorum.Observers, observer.ReplicaID)
+		}
+		// the active controller is not a broker in the KRaft mode, the controller
+		// of DescribeCluster is a random broker then
+		summary.ControllerID = quorum.LeaderID
+	}
+	for _, topic := range topics {
+		for _, partition := range topic.Partitions {
+			if isOffline(partition) {
+				summary.OfflinePartitions++
+			} else if isUnderReplicated(partition) {
+				summary.UnderReplicatedPartitions++
+			}
+		}
+	}
+	return summary, nil
+}
+
+func (mgr *Manager) listTopics(ctx context.Context) ([]topicSummary, error) {
+	topics, err := mgr.admin.ListTopicsWithInternal(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "list topics failed")
+	}
+
+	summaries := []topicSummary{}
+	for _, topic := range topics.Sorted() {
+		summary := topicSummary{
+			Topic:      topic.Topic,
+			Internal:   topic.IsInternal,
+			Partitions: len(topic.Partitions),
+			Error:      errorString(topic.Err),
+		}
+		for _, partition := range topic.Partitions {
+			summary.ReplicationFactor = max(summary.ReplicationFactor, len(partition.Replicas))
+			if isOffline(partition) {
+				summary.OfflinePartitions++
+			} else if isUnderReplicated(partition) {
+				summary.UnderReplicatedPartitions++
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+func (mgr *Manager) describeTopic(ctx context.Context, topic string) ([]partitionInfo, error) {
+	topics, err := mgr.admin.ListTopicsWithInternal(ctx, topic)
+	if err != nil {
+		return nil, errors.Wrapf(err, "describe topic %s failed", topic)
+	}
+	detail, ok := topics[topic]
+	if !ok {
+		return nil, errors.Errorf("topic %s is not found", topic)
+	}
+	if detail.Err != nil {
+		return nil, errors.Wrapf(detail.Err, "describe topic %s failed", topic)
+	}
+
+	partitions := []partitionInfo{}
+	for _, partition := range detail.Partitions.Sorted() {
+		partitions = append(partitions, partitionInfo{
+			Partition:       partition.Partition,
+			Leader:          partition.Leader,
+			LeaderEpoch:     partition.LeaderEpoch,
+			Replicas:        partition.Replicas,
+			ISR:             partition.ISR,
+			OfflineReplicas: partition.OfflineReplicas,
+			Error:           errorString(partition.Err),
+		})
+	}
+	return partitions, nil
+}
+
+func (mgr *Manager) describeConfigs(ctx context.Context, topic string) ([]configEntry, error) {
+	configs, err := mgr.admin.DescribeTopicConfigs(ctx, topic)
+	if err != nil {
+		return nil, errors.Wrapf(err, "describe configs of topic %s failed", topic)
+	}
+	resource, err := configs.On(topic, nil)
+	if err == nil {
+		err = resource.Err
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "describe configs of topic %s failed", topic)
+	}
+
+	entries := []configEntry{}
+	for _, config := range resource.Configs {
+		entries = append(entries, configEntry{
+			Name:      config.Key,
+			Value:     config.Value,
+			Source:    config.Source.String(),
+			Sensitive: config.Sensitive,
+		})
+	}
+	return entries, nil
+}
+
+func (mgr *Manager) listGroups(ctx context.Context) ([]groupSummary, error) {
+	groups, err := mgr.admin.ListGroups(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "list groups failed")
+	}
+
+	summaries := []groupSummary{}
+	for _, group := range groups.Sorted() {
+		summaries = append(summaries, groupSummary{
+			Group:        group.Group,
+			State:        group.State,
+			ProtocolType: group.ProtocolType,
+			Coordinator:  group.Coordinator,
+		})
+	}
+	return summaries, nil
+}
+
+func (mgr *Manager) describeLag(ctx context.Context, group string) ([]partitionLag, error) {
+	lags, err := mgr.admin.Lag(ctx, group)
+	if err != nil {
+		return nil, errors.Wrapf(err, "describe lag of group %s failed", group)
+	}
+	lag, ok := lags[group]
+	if !ok {
+		return nil, errors.Errorf("group %s is not found", group)
+	}
+	if err = lag.Error(); err != nil {
+		return nil, errors.Wrapf(err, "describe lag of group %s failed", group)
+	}
+
+	partitions := []partitionLag{}
+	for _, memberLag := range lag.Lag.Sorted() {
+		partition := partitionLag{
+			Topic:           memberLag.Topic,
+			Partition:       memberLag.Partition,
+			CommittedOffset: memberLag.Commit.At,
+			EndOffset:       memberLag.End.Offset,
+			Lag:             memberLag.Lag,
+			Error:           errorString(memberLag.Err),
+		}
+		if memberLag.Member != nil {
+			partition.MemberID = memberLag.Member.MemberID
+		}
+		partitions = append(partitions, partition)
+	}
+	return partitions, nil
+}
+
+// createTopic creates the topic, the partitions and the replication factor are
+// the defaults of the brokers if they're not set, and the other args are the
+// configs of the topic.
+func (mgr *Manager) createTopic(ctx context.Context, topic string, args []string) error {
+	partitions, replicationFactor := int32(-1), int16(-1)
+	configs := map[string]*string{}
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return errors.Errorf("invalid arg %s, the usage is: %s", arg, execUsages[0])
+		}
+		switch key {
+		case "partitions":
+			n, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return errors.Wrapf(err, "invalid partitions %s", value)
+			}
+			partitions = int32(n)
+		case "replication-factor":
+			n, err := strconv.ParseInt(value, 10, 16)
+			if err != nil {
+				return errors.Wrapf(err, "invalid replication factor %s", value)
+			}
+			replicationFactor = int16(n)
+		default:
+			configs[key] = &value
+		}
+	}
+
+	resp, err := mgr.admin.CreateTopic(ctx, partitions, replicationFactor, configs, topic)
+	if err == nil {
+		err = resp.Err
+	}
+	if err != nil {
+		return errors.Wrapf(withMessage(err, resp.ErrMessage), "create topic %s failed", topic)
+	}
+	return nil
+}
+
+func (mgr *Manager) deleteTopic(ctx context.Context, topic string) error {
+	resp, err := mgr.admin.DeleteTopic(ctx, topic)
+	if err == nil {
+		err = resp.Err
+	}
+	if err != nil {
+		return errors.Wrapf(withMessage(err, resp.ErrMessage), "delete topic %s failed", topic)
+	}
+	return nil
+}
+
+// alterConfigs sets the configs of the topic incrementally, a config without
+// value is deleted, which falls back to the default of the brokers.
+func (mgr *Manager) alterConfigs(ctx context.Context, topic string, args []string) error {
+	var configs []kadm.AlterConfig
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return errors.Errorf("invalid arg %s, the usage is: %s", arg, execUsages[2])
+		}
+		config := kadm.AlterConfig{Op: kadm.SetConfig, Name: key, Value: &value}
+		if value == "" {
+			config = kadm.AlterConfig{Op: kadm.DeleteConfig, Name: key}
+		}
+		configs = append(configs, config)
+	}
+
+	resps, err := mgr.admin.AlterTopicConfigs(ctx, configs, topic)
+	if err != nil {
+		return errors.Wrapf(err, "alter configs of topic %s failed", topic)
+	}
+	resp, err := resps.On(topic, nil)
+	if err == nil {
+		err = resp.Err
+	}
+	if err != nil {
+		return errors.Wrapf(withMessage(err, resp.ErrMessage), "alter configs of topic %s failed", topic)
+	}
+	return nil
+}
+
+// createPartitions increases the partitions of the topic to the total.
+func (mgr *Manager) createPartitions(ctx context.Context, topic string, total string) error {
+	n, err := strconv.Atoi(total)
+	if err != nil {
+		return errors.Wrapf(err, "invalid partitions %s", total)
+	}
+	resps, err := mgr.admin.UpdatePartitions(ctx, n, topic)
+	if err != nil {
+		return errors.Wrapf(err, "create partitions of topic %s failed", topic)
+	}
+	resp, ok := resps[topic]
+	if !ok {
+		return errors.Errorf("topic %s is not in the response of create partitions", topic)
+	}
+	if resp.Err != nil {
+		return errors.Wrapf(withMessage(resp.Err, resp.ErrMessage), "create partitions of topic %s failed", topic)
+	}
+	return nil
+}
+
+// withMessage adds the error message of the response to the error.
+func withMessage(err error, message string) error {
+	if message == "" {
+		return err
+	}
+	return errors.Wrap(err, message)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
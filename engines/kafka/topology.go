@@ -0,0 +1,91 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package kafka
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// GetTopology lists the voters and the observers of the metadata quorum, whose
+// lag is the entries behind the log end of the active controller. The brokers
+// are listed in the ZooKeeper mode, which has no quorum.
+func (mgr *Manager) GetTopology(ctx context.Context) (*models.Topology, error) {
+	state, err := mgr.getClusterState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := map[int32]string{}
+	for _, broker := range state.cluster.Brokers {
+		addresses[broker.NodeID] = net.JoinHostPort(broker.Host, strconv.Itoa(int(broker.Port)))
+	}
+	newMember := func(nodeID int32) models.Member {
+		info := newRoleInfo(state.role(nodeID))
+		return models.Member{
+			Name:       strconv.Itoa(int(nodeID)),
+			Address:    addresses[nodeID],
+			Role:       info.Role,
+			NativeRole: info.NativeRole,
+			Health:     info.Health,
+			Self:       nodeID == state.nodeID,
+		}
+	}
+
+	topology := &models.Topology{}
+	if state.quorum == nil {
+		for _, broker := range state.cluster.Brokers {
+			topology.Members = append(topology.Members, newMember(broker.NodeID))
+		}
+		return topology, nil
+	}
+
+	topology.Term = int64(state.quorum.LeaderEpoch)
+	leaderEnd := int64(-1)
+	for _, voter := range state.quorum.CurrentVoters {
+		if voter.ReplicaID == state.quorum.LeaderID {
+			leaderEnd = voter.LogEndOffset
+		}
+	}
+	addReplica := func(replica kmsg.DescribeQuorumResponseTopicPartitionReplicaState) {
+		member := newMember(replica.ReplicaID)
+		if leaderEnd >= 0 && replica.LogEndOffset >= 0 {
+			lag := max(leaderEnd-replica.LogEndOffset, 0)
+			member.LagEntries = &lag
+		}
+		// a fenced broker still observes the quorum, but it's not described
+		if member.NativeRole == Broker && !state.isBroker(replica.ReplicaID) {
+			member.Health = models.HealthUnhealthy
+		}
+		topology.Members = append(topology.Members, member)
+	}
+	for _, voter := range state.quorum.CurrentVoters {
+		addReplica(voter)
+	}
+	for _, observer := range state.quorum.Observers {
+		addReplica(observer)
+	}
+	return topology, nil
+}
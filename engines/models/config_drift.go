@@ -0,0 +1,38 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package models
+
+// ConfigDriftReport compares the parameters in the config files with the values the
+// database is running with, e.g. a static parameter changed without a restart.
+type ConfigDriftReport struct {
+	// Files are the parsed config files, the main file and the included ones.
+	Files  []string         `json:"files"`
+	Drifts []ParameterDrift `json:"drifts"`
+	// Unknown are the parameters in the files the database doesn't report.
+	Unknown []string `json:"unknown,omitempty"`
+}
+
+type ParameterDrift struct {
+	Name      string `json:"name"`
+	FileValue string `json:"fileValue"`
+	LiveValue string `json:"liveValue"`
+	// File is where the parameter is set last.
+	File string `json:"file"`
+}
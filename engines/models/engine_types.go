@@ -38,6 +38,12 @@ const (
 	Oceanbase          EngineType = "oceanbase"
 	Oracle             EngineType = "oracle"
 	OpenGauss          EngineType = "opengauss"
+	Kafka              EngineType = "kafka"
+	ZooKeeper          EngineType = "zookeeper"
+	ClickHouse         EngineType = "clickhouse"
+	Elasticsearch      EngineType = "elasticsearch"
+	OpenSearch         EngineType = "opensearch"
+	Fake               EngineType = "fake"
 )
 
 func GetEngineTypeList() []EngineType {
@@ -58,6 +64,12 @@ func GetEngineTypeList() []EngineType {
 		Oceanbase,
 		Oracle,
 		OpenGauss,
+		Kafka,
+		ZooKeeper,
+		ClickHouse,
+		Elasticsearch,
+		OpenSearch,
+		Fake,
 	}
 }
 
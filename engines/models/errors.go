@@ -24,9 +24,23 @@ import (
 )
 
 const (
-	errMsgNotImplemented = "not implemented"
+	errMsgNotImplemented    = "not implemented"
+	errMsgUnknownParameter  = "unknown parameter"
+	errMsgReadOnlyParameter = "read-only parameter"
+	errMsgRestartRequired   = "restart required"
+	errMsgNotPersisted      = "static parameter can't be persisted online, set it in the config file"
 )
 
 var (
 	ErrNotImplemented = errors.New(errMsgNotImplemented)
+	// ErrUnknownParameter and ErrReadOnlyParameter reject the parameter to reconfigure.
+	ErrUnknownParameter  = errors.New(errMsgUnknownParameter)
+	ErrReadOnlyParameter = errors.New(errMsgReadOnlyParameter)
+	// ErrRestartRequired is returned by a manager which finds the parameter static
+	// only when it's applied.
+	ErrRestartRequired = errors.New(errMsgRestartRequired)
+	// ErrNotPersisted is returned for a static parameter which the manager can't
+	// persist for the next start, it requires a restart after it's set in the
+	// config file.
+	ErrNotPersisted = errors.New(errMsgNotPersisted)
 )
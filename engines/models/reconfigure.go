@@ -0,0 +1,45 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package models
+
+// ParameterKind tells how a parameter takes effect.
+type ParameterKind string
+
+const (
+	// ParameterDynamic is applied online.
+	ParameterDynamic ParameterKind = "dynamic"
+	// ParameterStatic takes effect after the database restarts.
+	ParameterStatic ParameterKind = "static"
+)
+
+// ReconfigureResult lists the parameters by how they are handled. The static
+// parameters the engine can't persist are listed in both RestartRequired and
+// NotPersisted, they have to be set in the config file before the restart.
+type ReconfigureResult struct {
+	Applied         []string            `json:"applied"`
+	RestartRequired []string            `json:"restartRequired"`
+	NotPersisted    []string            `json:"notPersisted"`
+	Rejected        []RejectedParameter `json:"rejected"`
+}
+
+type RejectedParameter struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
@@ -28,4 +28,5 @@ const (
 	FOLLOWER  = "follower"
 	LEARNER   = "learner"
 	CANDIDATE = "candidate"
+	ARBITER   = "arbiter"
 )
@@ -0,0 +1,46 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package models
+
+// ReplicationStatus is the replication state of a replica from its upstream. On a
+// primary, IsReplica is false and only AppliedPosition is set.
+type ReplicationStatus struct {
+	IsReplica    bool   `json:"isReplica"`
+	UpstreamHost string `json:"upstreamHost,omitempty"`
+	UpstreamPort int    `json:"upstreamPort,omitempty"`
+
+	// LagSeconds and LagBytes are nil if the engine can't tell the lag, e.g. the
+	// replication is stopped or the engine has no byte positions.
+	LagSeconds *int64 `json:"lagSeconds,omitempty"`
+	LagBytes   *int64 `json:"lagBytes,omitempty"`
+
+	// IOThreadState is the state of receiving the changes from the upstream, and
+	// SQLThreadState is the state of applying them, e.g. MySQL's Replica_IO_Running
+	// and Replica_SQL_Running, or PostgreSQL's WAL receiver status.
+	IOThreadState  string `json:"ioThreadState,omitempty"`
+	SQLThreadState string `json:"sqlThreadState,omitempty"`
+	LastError      string `json:"lastError,omitempty"`
+
+	// ReceivedPosition and AppliedPosition are in the engine's own form: GTID sets
+	// or binlog positions for MySQL, LSNs for PostgreSQL, offsets for Redis and
+	// optimes for MongoDB.
+	ReceivedPosition string `json:"receivedPosition,omitempty"`
+	AppliedPosition  string `json:"appliedPosition,omitempty"`
+}
@@ -0,0 +1,101 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package models
+
+import (
+	"strings"
+)
+
+// the health hints of a replica.
+const (
+	HealthHealthy    = "healthy"
+	HealthRecovering = "recovering"
+	HealthUnhealthy  = "unhealthy"
+	HealthUnknown    = "unknown"
+)
+
+// RoleInfo is the structured role of a replica. Role is normalized to the role
+// vocabulary of this package, while NativeRole is the role reported by the engine,
+// e.g. mongodb's "STARTUP2" or wesql's "Leader".
+type RoleInfo struct {
+	Role       string `json:"role"`
+	NativeRole string `json:"nativeRole"`
+	// Writable is true if the replica accepts writes.
+	Writable bool `json:"writable"`
+	// Voter is true if the replica votes in the election of the primary.
+	Voter bool `json:"voter"`
+	// Health is a hint about the replica state, one of the Health* constants.
+	Health string `json:"health"`
+	// Details are the engine-specific facts behind the health, e.g. the shard
+	// counts of Elasticsearch, nil if the engine has none.
+	Details map[string]any `json:"details,omitempty"`
+}
+
+type nativeRole struct {
+	role     string
+	writable bool
+	voter    bool
+	health   string
+}
+
+// nativeRoles maps the lowercased native roles to the normalized roles.
+var nativeRoles = map[string]nativeRole{
+	PRIMARY:   {role: PRIMARY, writable: true, voter: true, health: HealthHealthy},
+	MASTER:    {role: PRIMARY, writable: true, voter: true, health: HealthHealthy},
+	SECONDARY: {role: SECONDARY, voter: true, health: HealthHealthy},
+	SLAVE:     {role: SECONDARY, voter: true, health: HealthHealthy},
+	LEADER:    {role: LEADER, writable: true, voter: true, health: HealthHealthy},
+	FOLLOWER:  {role: FOLLOWER, voter: true, health: HealthHealthy},
+	LEARNER:   {role: LEARNER, health: HealthHealthy},
+	CANDIDATE: {role: CANDIDATE, voter: true, health: HealthRecovering},
+	ARBITER:   {role: ARBITER, voter: true, health: HealthHealthy},
+
+	// patroni
+	"standby_leader": {role: PRIMARY, voter: true, health: HealthHealthy},
+	"replica":        {role: SECONDARY, voter: true, health: HealthHealthy},
+
+	// mongodb member states
+	"startup":    {role: SECONDARY, health: HealthRecovering},
+	"startup2":   {role: SECONDARY, voter: true, health: HealthRecovering},
+	"recovering": {role: SECONDARY, voter: true, health: HealthRecovering},
+	"rollback":   {role: SECONDARY, voter: true, health: HealthRecovering},
+	"down":       {role: SECONDARY, health: HealthUnhealthy},
+	"removed":    {role: SECONDARY, health: HealthUnhealthy},
+
+	// wesql and apecloud-postgresql consensus roles, a logger keeps the logs
+	// without data, it votes like a mongodb arbiter.
+	"logger": {role: ARBITER, voter: true, health: HealthHealthy},
+}
+
+// NewRoleInfo normalizes the native role reported by the engine, the role of an
+// unknown native role is empty.
+func NewRoleInfo(native string) *RoleInfo {
+	info := &RoleInfo{
+		NativeRole: native,
+		Health:     HealthUnknown,
+	}
+	if role, ok := nativeRoles[strings.ToLower(strings.TrimSpace(native))]; ok {
+		info.Role = role.role
+		info.Writable = role.writable
+		info.Voter = role.voter
+		info.Health = role.health
+	}
+	return info
+}
@@ -0,0 +1,52 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRoleInfo(t *testing.T) {
+	tests := []struct {
+		native string
+		want   RoleInfo
+	}{
+		{"primary", RoleInfo{Role: PRIMARY, Writable: true, Voter: true, Health: HealthHealthy}},
+		{"master", RoleInfo{Role: PRIMARY, Writable: true, Voter: true, Health: HealthHealthy}},
+		{"slave", RoleInfo{Role: SECONDARY, Voter: true, Health: HealthHealthy}},
+		{"Leader", RoleInfo{Role: LEADER, Writable: true, Voter: true, Health: HealthHealthy}},
+		{"Learner", RoleInfo{Role: LEARNER, Health: HealthHealthy}},
+		{"Logger", RoleInfo{Role: ARBITER, Voter: true, Health: HealthHealthy}},
+		{"broker", RoleInfo{Health: HealthUnknown}},
+		{"STARTUP2", RoleInfo{Role: SECONDARY, Voter: true, Health: HealthRecovering}},
+		{"recovering", RoleInfo{Role: SECONDARY, Voter: true, Health: HealthRecovering}},
+		{"DOWN", RoleInfo{Role: SECONDARY, Health: HealthUnhealthy}},
+		{"unknown", RoleInfo{Health: HealthUnknown}},
+		{"", RoleInfo{Health: HealthUnknown}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.native, func(t *testing.T) {
+			tt.want.NativeRole = tt.native
+			assert.Equal(t, &tt.want, NewRoleInfo(tt.native))
+		})
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Session is a client session or a running operation of the database.
+type Session struct {
+	ID       string `json:"id"`
+	User     string `json:"user,omitempty"`
+	Database string `json:"database,omitempty"`
+	// Client is the address of the client.
+	Client string `json:"client,omitempty"`
+	// State is the native state, e.g. active or idle of PostgreSQL, or the
+	// command of MySQL, e.g. Query or Sleep.
+	State string `json:"state,omitempty"`
+	// DurationSeconds is how long the query or operation has been running, or how
+	// long the connection has been idle or blocked for Redis.
+	DurationSeconds float64 `json:"durationSeconds"`
+	Query           string  `json:"query,omitempty"`
+}
+
+// SessionFilter matches the sessions by all of its non-empty fields.
+type SessionFilter struct {
+	User     string
+	Database string
+	// State is matched case-insensitively.
+	State       string
+	MinDuration time.Duration
+	// Query is a case-insensitive substring of the query.
+	Query string
+}
+
+func (f *SessionFilter) IsEmpty() bool {
+	return *f == SessionFilter{}
+}
+
+func (f *SessionFilter) Match(s *Session) bool {
+	switch {
+	case f.User != "" && f.User != s.User:
+		return false
+	case f.Database != "" && f.Database != s.Database:
+		return false
+	case f.State != "" && !strings.EqualFold(f.State, s.State):
+		return false
+	case f.MinDuration > 0 && s.DurationSeconds < f.MinDuration.Seconds():
+		return false
+	case f.Query != "" && !strings.Contains(strings.ToLower(s.Query), strings.ToLower(f.Query)):
+		return false
+	default:
+		return true
+	}
+}
+
+// KillSessionResult lists the killed sessions, or the ones to kill in a dry run.
+type KillSessionResult struct {
+	DryRun bool                `json:"dryRun"`
+	Killed []Session           `json:"killed"`
+	Failed []FailedSessionKill `json:"failed,omitempty"`
+}
+
+type FailedSessionKill struct {
+	Session
+	Reason string `json:"reason"`
+}
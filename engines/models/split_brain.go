@@ -0,0 +1,51 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package models
+
+// MemberView is the role of a member as the member itself reports it, which is
+// compared across the members to detect a split brain.
+type MemberView struct {
+	Address  string `json:"address"`
+	Role     string `json:"role,omitempty"`
+	Writable bool   `json:"writable"`
+	// Term is the election term of the member, or the timeline of PostgreSQL,
+	// zero if the engine has neither.
+	Term int64 `json:"term,omitempty"`
+	// Leader is the address of the primary or leader the member follows as the
+	// member reports it, empty on the primary or leader itself.
+	Leader string `json:"leader,omitempty"`
+	// Error is set if the member can't be reached, the other fields are empty then.
+	Error string `json:"error,omitempty"`
+}
+
+// SplitBrainReport is the result of comparing the views of the members.
+type SplitBrainReport struct {
+	SplitBrain bool     `json:"splitBrain"`
+	Reasons    []string `json:"reasons,omitempty"`
+	// Primaries are the addresses of the writable members.
+	Primaries []string `json:"primaries,omitempty"`
+	// Stale are the primaries to fence, which are in an older term, or followed by
+	// fewer members than the other primary. It's empty if no primary can be told
+	// stale for sure.
+	Stale []string `json:"stale,omitempty"`
+	// Fenced are the stale primaries locked read-only, if the fencing is requested.
+	Fenced  []string     `json:"fenced,omitempty"`
+	Members []MemberView `json:"members"`
+}
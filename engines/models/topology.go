@@ -0,0 +1,90 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package models
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Topology is the membership of the cluster as the local member knows it, the
+// members are reported by the local member only, so they may be stale during a
+// network partition.
+type Topology struct {
+	Members []Member `json:"members"`
+	// Term is the election term of the consensus engines, e.g. the raft term of
+	// etcd, zero if the engine has no terms.
+	Term int64 `json:"term,omitempty"`
+}
+
+// Member is a cluster member, Role and Health are in the vocabulary of RoleInfo.
+type Member struct {
+	Name       string `json:"name,omitempty"`
+	Address    string `json:"address"`
+	Role       string `json:"role"`
+	NativeRole string `json:"nativeRole,omitempty"`
+	Health     string `json:"health"`
+	// Self is true for the local member.
+	Self bool `json:"self,omitempty"`
+
+	// LagSeconds, LagBytes and LagEntries are the lag behind the primary, nil if
+	// the local member can't tell, e.g. the lag of the other followers.
+	LagSeconds *int64 `json:"lagSeconds,omitempty"`
+	LagBytes   *int64 `json:"lagBytes,omitempty"`
+	// LagEntries is the lag in the log entries of the consensus engines.
+	LagEntries *int64 `json:"lagEntries,omitempty"`
+
+	// Leaders is the number of the partition leaders on the member by the sharded
+	// collections, e.g. by the graph spaces of nebula storaged.
+	Leaders map[string]int64 `json:"leaders,omitempty"`
+}
+
+// NewMember returns a member whose role is normalized from the native role.
+func NewMember(address, nativeRole string) Member {
+	info := NewRoleInfo(nativeRole)
+	return Member{
+		Address:    address,
+		Role:       info.Role,
+		NativeRole: nativeRole,
+		Health:     info.Health,
+	}
+}
+
+// IsSameMember compares the hosts of the addresses, which are reported in
+// different forms by the members, e.g. a pod name, its FQDN, or a URL with port.
+func IsSameMember(a, b string) bool {
+	a, b = memberHost(a), memberHost(b)
+	if a == "" || b == "" {
+		return false
+	}
+	return a == b || strings.HasPrefix(a, b+".") || strings.HasPrefix(b, a+".")
+}
+
+func memberHost(address string) string {
+	address, _, _ = strings.Cut(address, ",")
+	if u, err := url.Parse(address); err == nil && u.Host != "" {
+		address = u.Host
+	}
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+	return strings.ToLower(address)
+}
@@ -0,0 +1,34 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSameMember(t *testing.T) {
+	assert.True(t, IsSameMember("pod-0:3306", "pod-0"))
+	assert.True(t, IsSameMember("pod-0.headless.default.svc", "pod-0:3306"))
+	assert.True(t, IsSameMember("http://Pod-0.headless:8080", "pod-0"))
+	assert.False(t, IsSameMember("pod-0", "pod-01"))
+	assert.False(t, IsSameMember("", "pod-0"))
+}
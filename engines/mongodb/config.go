@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package mongodb
 
 import (
+	"crypto/tls"
 	"net"
 	"strconv"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/apecloud/dbctl/constant"
+	"github.com/apecloud/dbctl/engines/models"
 	utilconfig "github.com/apecloud/dbctl/util/config"
 )
 
@@ -52,8 +54,12 @@ type Config struct {
 	Params                  string
 	Direct                  bool
 	OperationTimeout        time.Duration
+	ConnectTimeout          time.Duration
 	ConfigSvr               bool
 	GrantAnyActionPrivilege bool
+	TLSConfig               *tls.Config
+	// instance is the instance whose envs and credentials are looked up.
+	instance string
 }
 
 var config *Config
@@ -65,22 +71,23 @@ func NewConfig() (*Config, error) {
 		Hosts:            []string{"127.0.0.1:27017"},
 		Params:           "?directConnection=true",
 		OperationTimeout: defaultTimeout,
+		instance:         utilconfig.CurrentInstance(),
 	}
 
-	if viper.IsSet(constant.KBEnvServicePort) {
-		config.Hosts = []string{"localhost:" + viper.GetString(constant.KBEnvServicePort)}
+	engineConfig, err := utilconfig.GetInstanceEngineConfig(config.instance, string(models.MongoDB))
+	if err != nil {
+		return nil, err
 	}
-
-	_ = viper.BindEnv(constant.ConfigKeyUserName, constant.KBEnvServiceUser, RootUserEnv, UserEnv)
-	if viper.IsSet(constant.ConfigKeyUserName) {
-		config.Username = viper.GetString(constant.ConfigKeyUserName)
+	if err = config.mergeEngineConfig(engineConfig); err != nil {
+		return nil, err
 	}
 
-	_ = viper.BindEnv(constant.ConfigKeyPassword, constant.KBEnvServicePassword, RootPasswordEnv, PasswordEnv)
-	if viper.IsSet(constant.ConfigKeyPassword) {
-		config.Password = viper.GetString(constant.ConfigKeyPassword)
+	if viper.IsSet(constant.KBEnvServicePort) {
+		config.Hosts = []string{"localhost:" + viper.GetString(constant.KBEnvServicePort)}
 	}
 
+	config.setCredentials()
+
 	if viper.IsSet(ClusterRoleEnv) {
 		config.ConfigSvr = viper.GetString(ClusterRoleEnv) == "configsvr"
 	}
@@ -93,6 +100,54 @@ func NewConfig() (*Config, error) {
 	return config, nil
 }
 
+func (config *Config) mergeEngineConfig(engineConfig *utilconfig.EngineConfig) error {
+	if engineConfig.Host != "" || engineConfig.Port != 0 {
+		config.Hosts = []string{engineConfig.Addr("127.0.0.1", defaultDBPort)}
+	}
+	if engineConfig.Username != "" {
+		config.Username = engineConfig.Username
+	}
+	if engineConfig.Password != "" {
+		config.Password = engineConfig.Password
+	}
+	if engineConfig.ConnectTimeout != 0 {
+		config.ConnectTimeout = engineConfig.ConnectTimeout
+	}
+	tlsConfig, err := engineConfig.TLS.ClientConfig()
+	if err != nil {
+		return err
+	}
+	config.TLSConfig = tlsConfig
+	return nil
+}
+
+// ReloadCredentials reads the credentials again, it's called when the mounted secrets are rotated.
+func (config *Config) ReloadCredentials() error {
+	engineConfig, err := utilconfig.GetInstanceEngineConfig(config.instance, string(models.MongoDB))
+	if err != nil {
+		return err
+	}
+	if engineConfig.Username != "" {
+		config.Username = engineConfig.Username
+	}
+	if engineConfig.Password != "" {
+		config.Password = engineConfig.Password
+	}
+	config.setCredentials()
+	return nil
+}
+
+func (config *Config) setCredentials() {
+	if username, ok := utilconfig.LookupInstanceCredential(config.instance, constant.ConfigKeyUserName,
+		constant.KBEnvServiceUser, RootUserEnv, UserEnv); ok {
+		config.Username = username
+	}
+	if password, ok := utilconfig.LookupInstanceCredential(config.instance, constant.ConfigKeyPassword,
+		constant.KBEnvServicePassword, RootPasswordEnv, PasswordEnv); ok {
+		config.Password = password
+	}
+}
+
 func (config *Config) GetDBPort() int {
 	_, portStr, err := net.SplitHostPort(config.Hosts[0])
 	if err != nil {
@@ -0,0 +1,88 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/configfile"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const setParameterPrefix = "setParameter."
+
+var _ engines.ConfigDriftChecker = &Manager{}
+
+// CheckConfigDrift compares the setParameter section of mongod.conf, which is the
+// one in getCmdLineOpts by default, with getParameter. The other options can't
+// be changed online, so they are not compared.
+func (mgr *Manager) CheckConfigDrift(ctx context.Context, file string) (*models.ConfigDriftReport, error) {
+	admin := mgr.GetClient().Database("admin")
+	if file == "" {
+		var opts struct {
+			Parsed bson.M `bson:"parsed"`
+		}
+		if err := admin.RunCommand(ctx, bson.D{{Key: "getCmdLineOpts", Value: 1}}).Decode(&opts); err != nil {
+			return nil, errors.Wrap(err, "get command line options failed")
+		}
+		if file = cast.ToString(opts.Parsed["config"]); file == "" {
+			return nil, errors.New("mongod is running without a config file")
+		}
+	}
+	config, err := configfile.ParseMongodConf(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var parameters bson.M
+	if err = admin.RunCommand(ctx, bson.D{{Key: "getParameter", Value: "*"}}).Decode(&parameters); err != nil {
+		return nil, errors.Wrap(err, "get parameters failed")
+	}
+	live := map[string]string{}
+	for name, value := range parameters {
+		live[name] = fmt.Sprint(value)
+	}
+
+	return configfile.Diff(serverParameters(config), live, func(_, fileValue, liveValue string) bool {
+		return configfile.EqualValues(fileValue, liveValue, nil)
+	}), nil
+}
+
+// serverParameters keeps the parameters in the setParameter section, and strips
+// the section name.
+func serverParameters(config *configfile.Config) *configfile.Config {
+	params := &configfile.Config{
+		Files:      config.Files,
+		Parameters: map[string]configfile.Parameter{},
+	}
+	for name, param := range config.Parameters {
+		if strings.HasPrefix(name, setParameterPrefix) {
+			params.Parameters[strings.TrimPrefix(name, setParameterPrefix)] = param
+		}
+	}
+	return params
+}
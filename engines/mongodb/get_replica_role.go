@@ -21,8 +21,42 @@ package mongodb
 
 import (
 	"context"
+
+	"github.com/apecloud/dbctl/engines/models"
 )
 
 func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
 	return mgr.GetMemberState(ctx)
 }
+
+// GetReplicaRoleInfo normalizes the member state, the voting membership is read
+// from the replica set config since a secondary may be configured with no votes.
+func (mgr *Manager) GetReplicaRoleInfo(ctx context.Context) (*models.RoleInfo, error) {
+	status, err := mgr.GetReplSetStatus(ctx)
+	if err != nil {
+		mgr.Logger.Info("rs.status() error", "error", err.Error())
+		return nil, err
+	}
+
+	self := status.GetSelf()
+	if self == nil {
+		return models.NewRoleInfo(""), nil
+	}
+
+	info := models.NewRoleInfo(self.StateStr)
+	if self.Health == 0 {
+		info.Health = models.HealthUnhealthy
+	}
+
+	rsConfig, err := GetReplSetConfig(ctx, mgr.GetClient())
+	if err != nil {
+		mgr.Logger.Info("rs.conf() error", "error", err.Error())
+		return nil, err
+	}
+	for _, member := range rsConfig.Members {
+		if member.ID == self.ID && member.Votes != nil {
+			info.Voter = *member.Votes > 0
+		}
+	}
+	return info, nil
+}
@@ -22,6 +22,7 @@ package mongodb
 import (
 	"context"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -32,12 +33,17 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/apecloud/dbctl/engines"
+	utilconfig "github.com/apecloud/dbctl/util/config"
 )
 
 type Manager struct {
 	engines.DBManagerBase
 	Client   *mongo.Client
 	Database *mongo.Database
+
+	// clientLock guards the client which is replaced when the credentials are rotated.
+	clientLock sync.RWMutex
+	config     *Config
 }
 
 var Mgr *Manager
@@ -51,20 +57,9 @@ func NewManager() (engines.DBManager, error) {
 		return nil, err
 	}
 
-	opts := options.Client().
-		SetHosts(config.Hosts).
-		SetReplicaSet(config.ReplSetName).
-		SetAuth(options.Credential{
-			Password: config.Password,
-			Username: config.Username,
-		}).
-		SetWriteConcern(writeconcern.Majority()).
-		SetReadPreference(readpref.Primary()).
-		SetDirect(config.Direct)
-
-	client, err := mongo.Connect(ctx, opts)
+	client, err := newClient(ctx, config)
 	if err != nil {
-		return nil, errors.Wrap(err, "connect to mongodb")
+		return nil, err
 	}
 
 	defer func() {
@@ -85,11 +80,75 @@ func NewManager() (engines.DBManager, error) {
 		DBManagerBase: *managerBase,
 		Client:        client,
 		Database:      client.Database(config.DatabaseName),
+		config:        config,
 	}
 
+	utilconfig.OnCredentialsChange(Mgr.reloadCredentials)
 	return Mgr, nil
 }
 
+func newClient(ctx context.Context, config *Config) (*mongo.Client, error) {
+	opts := options.Client().
+		SetHosts(config.Hosts).
+		SetReplicaSet(config.ReplSetName).
+		SetAuth(options.Credential{
+			Password: config.Password,
+			Username: config.Username,
+		}).
+		SetWriteConcern(writeconcern.Majority()).
+		SetReadPreference(readpref.Primary()).
+		SetDirect(config.Direct)
+	if config.ConnectTimeout != 0 {
+		opts.SetConnectTimeout(config.ConnectTimeout)
+	}
+	if config.TLSConfig != nil {
+		opts.SetTLSConfig(config.TLSConfig)
+	}
+
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "connect to mongodb")
+	}
+	return client, nil
+}
+
+// reloadCredentials replaces the client after the credentials are reloaded, as the
+// mongo driver can't update the credentials of an existing client. The old client is
+// disconnected in background, which waits for the in-use connections to be returned.
+func (mgr *Manager) reloadCredentials() {
+	if err := mgr.config.ReloadCredentials(); err != nil {
+		mgr.Logger.Info("reload credentials failed", "error", err.Error())
+		return
+	}
+	client, err := newClient(context.Background(), mgr.config)
+	if err != nil {
+		mgr.Logger.Info("reload credentials failed", "error", err.Error())
+		return
+	}
+
+	mgr.clientLock.Lock()
+	oldClient := mgr.Client
+	mgr.Client = client
+	mgr.Database = client.Database(mgr.config.DatabaseName)
+	mgr.clientLock.Unlock()
+	mgr.Logger.Info("credentials reloaded")
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := oldClient.Disconnect(ctx); err != nil {
+			mgr.Logger.Info("failed to disconnect", "error", err.Error())
+		}
+	}()
+}
+
+// GetClient returns the current client, which may be replaced when the credentials are rotated.
+func (mgr *Manager) GetClient() *mongo.Client {
+	mgr.clientLock.RLock()
+	defer mgr.clientLock.RUnlock()
+	return mgr.Client
+}
+
 func (mgr *Manager) IsDBStartupReady() bool {
 	if mgr.DBStartupReady {
 		return true
@@ -97,7 +156,7 @@ func (mgr *Manager) IsDBStartupReady() bool {
 	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
 	defer cancel()
 
-	err := mgr.Client.Ping(ctx, readpref.Primary())
+	err := mgr.GetClient().Ping(ctx, readpref.Primary())
 	if err != nil {
 		mgr.Logger.Info("DB is not ready", "error", err)
 		return false
@@ -107,6 +166,12 @@ func (mgr *Manager) IsDBStartupReady() bool {
 	return true
 }
 
+var _ engines.Pinger = &Manager{}
+
+func (mgr *Manager) Ping(ctx context.Context) error {
+	return mgr.GetClient().Ping(ctx, readpref.Primary())
+}
+
 func (mgr *Manager) GetMemberState(ctx context.Context) (string, error) {
 	status, err := mgr.GetReplSetStatus(ctx)
 	if err != nil {
@@ -122,7 +187,7 @@ func (mgr *Manager) GetMemberState(ctx context.Context) (string, error) {
 }
 
 func (mgr *Manager) GetReplSetStatus(ctx context.Context) (*ReplSetStatus, error) {
-	return GetReplSetStatus(ctx, mgr.Client)
+	return GetReplSetStatus(ctx, mgr.GetClient())
 }
 
 func (mgr *Manager) GetMemberAddrsFromRSConfig(rsConfig *RSConfig) []string {
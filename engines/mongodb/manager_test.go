@@ -0,0 +1,71 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mongodb
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	// the mock deployment of the driver answers the commands by the queued responses
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	mt.Run("mock deployment", func(mt *mtest.T) {
+		manager := &Manager{
+			DBManagerBase: engines.DBManagerBase{
+				CurrentMemberName: "mongo-0",
+				Logger:            ctrl.Log.WithName("MongoDB-TEST"),
+			},
+			Client: mt.Client,
+		}
+
+		conformance.Run(mt.T, conformance.Suite{
+			Manager: manager,
+			Prepare: func(method conformance.Method) {
+				switch method {
+				case conformance.IsDBStartupReady:
+					mt.AddMockResponses(mtest.CreateSuccessResponse())
+				case conformance.GetReplicaRole, conformance.GetReplicaRoleInfo:
+					mt.AddMockResponses(mtest.CreateSuccessResponse(
+						bson.E{Key: "set", Value: "rs0"},
+						bson.E{Key: "members", Value: bson.A{
+							bson.D{{Key: "_id", Value: 0}, {Key: "name", Value: "mongo-0:27017"}, {Key: "health", Value: 1},
+								{Key: "state", Value: 1}, {Key: "stateStr", Value: "PRIMARY"}, {Key: "self", Value: true}},
+						}},
+					))
+					if method == conformance.GetReplicaRoleInfo {
+						mt.AddMockResponses(mtest.CreateSuccessResponse(
+							bson.E{Key: "config", Value: bson.D{{Key: "_id", Value: "rs0"}, {Key: "members", Value: bson.A{
+								bson.D{{Key: "_id", Value: 0}, {Key: "host", Value: "mongo-0:27017"}, {Key: "votes", Value: 1}},
+							}}}},
+						))
+					}
+				}
+			},
+			NotImplemented: []conformance.Method{conformance.Exec, conformance.Query},
+		})
+	})
+}
@@ -0,0 +1,177 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const (
+	// errCodeInvalidOptions is returned by getParameter for an unknown parameter.
+	errCodeInvalidOptions = 72
+	// showDetailsMajorVersion is the first version whose getParameter takes showDetails.
+	showDetailsMajorVersion = 5
+)
+
+var _ engines.Reconfigurer = &Manager{}
+
+func (mgr *Manager) ClassifyParameter(ctx context.Context, name string) (models.ParameterKind, error) {
+	details, err := mgr.getParameterDetails(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return parameterKind(details)
+}
+
+// SetParameter sets the server parameter, the value is converted to the type of
+// the current value. It's not persisted, mongod.conf is kept as it is, so a
+// parameter settable only at startup is reported to be set in mongod.conf and
+// take effect after a restart.
+func (mgr *Manager) SetParameter(ctx context.Context, name, value string) error {
+	details, err := mgr.getParameterDetails(ctx, name)
+	if err != nil {
+		return err
+	}
+	kind, err := parameterKind(details)
+	if err != nil {
+		return err
+	}
+	if kind == models.ParameterStatic {
+		return models.ErrNotPersisted
+	}
+	typedValue, err := parameterValue(details["value"], value)
+	if err != nil {
+		return err
+	}
+	cmd := bson.D{{Key: "setParameter", Value: 1}, {Key: name, Value: typedValue}}
+	if err = mgr.GetClient().Database("admin").RunCommand(ctx, cmd).Err(); err != nil {
+		return errors.Wrapf(err, "set parameter %s failed", name)
+	}
+	return nil
+}
+
+func (mgr *Manager) getParameterDetails(ctx context.Context, name string) (bson.M, error) {
+	if !engines.IsValidParameterName(name) {
+		return nil, models.ErrUnknownParameter
+	}
+	majorVersion, err := mgr.getMajorVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	showDetails := majorVersion >= showDetailsMajorVersion
+	var resp bson.M
+	err = mgr.GetClient().Database("admin").RunCommand(ctx, getParameterCmd(name, showDetails)).Decode(&resp)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == errCodeInvalidOptions {
+		return nil, models.ErrUnknownParameter
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "get parameter %s failed", name)
+	}
+	return parameterDetails(resp, name, showDetails)
+}
+
+// getMajorVersion returns the major version of the server by buildInfo.
+func (mgr *Manager) getMajorVersion(ctx context.Context) (int, error) {
+	var resp bson.M
+	err := mgr.GetClient().Database("admin").RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&resp)
+	if err != nil {
+		return 0, errors.Wrap(err, "get build info failed")
+	}
+	version := cast.ToString(resp["version"])
+	major, _, _ := strings.Cut(version, ".")
+	majorVersion, err := strconv.Atoi(major)
+	if err != nil {
+		return 0, errors.Errorf("invalid version %s", version)
+	}
+	return majorVersion, nil
+}
+
+// getParameterCmd shows the details of the parameter if the server takes showDetails.
+func getParameterCmd(name string, showDetails bool) bson.D {
+	if !showDetails {
+		return bson.D{{Key: "getParameter", Value: 1}, {Key: name, Value: 1}}
+	}
+	return bson.D{{Key: "getParameter", Value: bson.D{{Key: "showDetails", Value: true}}}, {Key: name, Value: 1}}
+}
+
+// parameterDetails returns the details of the parameter in the response of
+// getParameter. Without the details before 5.0, the parameter is taken as
+// dynamic, and setParameter rejects the one settable only at startup.
+func parameterDetails(resp bson.M, name string, showDetails bool) (bson.M, error) {
+	if !showDetails {
+		value, ok := resp[name]
+		if !ok {
+			return nil, models.ErrUnknownParameter
+		}
+		return bson.M{"value": value, "settableAtRuntime": true}, nil
+	}
+	details, ok := resp[name].(bson.M)
+	if !ok {
+		return nil, models.ErrUnknownParameter
+	}
+	return details, nil
+}
+
+// parameterKind tells by the details of getParameter, a parameter settable only at
+// startup is static.
+func parameterKind(details bson.M) (models.ParameterKind, error) {
+	switch {
+	case cast.ToBool(details["settableAtRuntime"]):
+		return models.ParameterDynamic, nil
+	case cast.ToBool(details["settableAtStartup"]):
+		return models.ParameterStatic, nil
+	default:
+		return "", models.ErrReadOnlyParameter
+	}
+}
+
+// parameterValue converts the value to the type of the current value, as
+// setParameter rejects a value of another type.
+func parameterValue(current any, value string) (any, error) {
+	var typedValue any
+	var err error
+	switch current.(type) {
+	case bool:
+		typedValue, err = cast.ToBoolE(value)
+	case int32:
+		typedValue, err = cast.ToInt32E(value)
+	case int64:
+		typedValue, err = cast.ToInt64E(value)
+	case float64:
+		typedValue, err = cast.ToFloat64E(value)
+	default:
+		typedValue = value
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid value %s", value)
+	}
+	return typedValue, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestParameterKind(t *testing.T) {
+	kind, err := parameterKind(bson.M{"value": int32(100), "settableAtRuntime": true, "settableAtStartup": true})
+	assert.Nil(t, err)
+	assert.Equal(t, models.ParameterDynamic, kind)
+
+	kind, err = parameterKind(bson.M{"value": "snappy", "settableAtRuntime": false, "settableAtStartup": true})
+	assert.Nil(t, err)
+	assert.Equal(t, models.ParameterStatic, kind)
+
+	_, err = parameterKind(bson.M{"value": "7.0.1"})
+	assert.ErrorIs(t, err, models.ErrReadOnlyParameter)
+}
+
+func TestParameterDetails(t *testing.T) {
+	resp := bson.M{"ok": 1.0, "maxTransactionLockRequestTimeoutMillis": bson.M{
+		"value": int32(5), "settableAtRuntime": true, "settableAtStartup": true,
+	}}
+	details, err := parameterDetails(resp, "maxTransactionLockRequestTimeoutMillis", true)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(5), details["value"])
+	assert.Equal(t, true, details["settableAtStartup"])
+
+	// without the details before 5.0
+	assert.Equal(t, bson.D{{Key: "getParameter", Value: 1}, {Key: "logLevel", Value: 1}}, getParameterCmd("logLevel", false))
+	details, err = parameterDetails(bson.M{"ok": 1.0, "logLevel": int32(0)}, "logLevel", false)
+	assert.Nil(t, err)
+	kind, err := parameterKind(details)
+	assert.Nil(t, err)
+	assert.Equal(t, models.ParameterDynamic, kind)
+
+	_, err = parameterDetails(bson.M{"ok": 1.0}, "foo", false)
+	assert.ErrorIs(t, err, models.ErrUnknownParameter)
+	_, err = parameterDetails(bson.M{"ok": 1.0}, "foo", true)
+	assert.ErrorIs(t, err, models.ErrUnknownParameter)
+}
+
+func TestParameterValue(t *testing.T) {
+	value, err := parameterValue(int32(100), "200")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(200), value)
+
+	value, err = parameterValue(false, "true")
+	assert.Nil(t, err)
+	assert.Equal(t, true, value)
+
+	value, err = parameterValue("info", "debug")
+	assert.Nil(t, err)
+	assert.Equal(t, "debug", value)
+
+	_, err = parameterValue(int64(1), "one")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"net"
+
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func (mgr *Manager) GetReplicationStatus(ctx context.Context) (*models.ReplicationStatus, error) {
+	status, err := mgr.GetReplSetStatus(ctx)
+	if err != nil {
+		mgr.Logger.Info("rs.status() error", "error", err.Error())
+		return nil, err
+	}
+	return replicationStatus(status), nil
+}
+
+// replicationStatus builds the status from the optimes of replSetGetStatus, the
+// lag is the optime distance to the primary, so it's unknown without a primary.
+func replicationStatus(rsStatus *ReplSetStatus) *models.ReplicationStatus {
+	status := &models.ReplicationStatus{}
+	if rsStatus.Optimes != nil {
+		status.AppliedPosition = rsStatus.Optimes.AppliedOpTime.String()
+	}
+
+	self := rsStatus.GetSelf()
+	if self == nil || self.State == MemberStatePrimary {
+		return status
+	}
+
+	status.IsReplica = true
+	status.IOThreadState = self.StateStr
+	status.LastError = self.InfoMessage
+	if rsStatus.Optimes != nil {
+		status.ReceivedPosition = rsStatus.Optimes.DurableOptime.String()
+	}
+
+	syncSource := self.SyncSourceHost
+	if syncSource == "" {
+		syncSource = self.SyncingTo
+	}
+	if host, port, err := net.SplitHostPort(syncSource); err == nil {
+		status.UpstreamHost = host
+		status.UpstreamPort = cast.ToInt(port)
+	}
+
+	if primary := rsStatus.GetPrimary(); primary != nil && !primary.OptimeDate.IsZero() && !self.OptimeDate.IsZero() {
+		lagSeconds := int64(primary.OptimeDate.Sub(self.OptimeDate).Seconds())
+		if lagSeconds < 0 {
+			lagSeconds = 0
+		}
+		status.LagSeconds = &lagSeconds
+	}
+	return status
+}
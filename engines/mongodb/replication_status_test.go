@@ -0,0 +1,77 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReplicationStatus(t *testing.T) {
+	now := time.Now()
+	optimes := &StatusOptimes{
+		AppliedOpTime: &Optime{Timestamp: primitive.Timestamp{T: 1700000000, I: 2}, Term: 3},
+		DurableOptime: &Optime{Timestamp: primitive.Timestamp{T: 1700000000, I: 1}, Term: 3},
+	}
+
+	t.Run("primary", func(t *testing.T) {
+		status := replicationStatus(&ReplSetStatus{
+			Optimes: optimes,
+			Members: []*Member{
+				{Name: "mongo-0:27017", State: MemberStatePrimary, StateStr: "PRIMARY", Self: true},
+			},
+		})
+		assert.False(t, status.IsReplica)
+		assert.Equal(t, "1700000000.2/3", status.AppliedPosition)
+	})
+
+	t.Run("secondary", func(t *testing.T) {
+		status := replicationStatus(&ReplSetStatus{
+			Optimes: optimes,
+			Members: []*Member{
+				{Name: "mongo-0:27017", State: MemberStatePrimary, StateStr: "PRIMARY", OptimeDate: now},
+				{Name: "mongo-1:27017", State: 2, StateStr: "SECONDARY", OptimeDate: now.Add(-5 * time.Second),
+					SyncSourceHost: "mongo-0:27017", Self: true},
+			},
+		})
+		assert.True(t, status.IsReplica)
+		assert.Equal(t, "mongo-0", status.UpstreamHost)
+		assert.Equal(t, 27017, status.UpstreamPort)
+		assert.Equal(t, "SECONDARY", status.IOThreadState)
+		assert.Equal(t, int64(5), *status.LagSeconds)
+		assert.Equal(t, "1700000000.1/3", status.ReceivedPosition)
+		assert.Equal(t, "1700000000.2/3", status.AppliedPosition)
+	})
+
+	t.Run("secondary without primary", func(t *testing.T) {
+		status := replicationStatus(&ReplSetStatus{
+			Members: []*Member{
+				{Name: "mongo-1:27017", State: 2, StateStr: "SECONDARY", OptimeDate: now,
+					InfoMessage: "Could not find member to sync from", Self: true},
+			},
+		})
+		assert.True(t, status.IsReplica)
+		assert.Nil(t, status.LagSeconds)
+		assert.Equal(t, "Could not find member to sync from", status.LastError)
+	})
+}
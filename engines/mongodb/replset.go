@@ -48,3 +48,24 @@ func GetReplSetStatus(ctx context.Context, client *mongo.Client) (*ReplSetStatus
 
 	return status, nil
 }
+
+func GetReplSetConfig(ctx context.Context, client *mongo.Client) (*RSConfig, error) {
+	resp := client.Database("admin").RunCommand(ctx, bson.D{{Key: "replSetGetConfig", Value: 1}})
+	if resp.Err() != nil {
+		err := errors.Wrap(resp.Err(), "replSetGetConfig")
+		return nil, err
+	}
+
+	cfg := &ReplSetGetConfig{}
+	if err := resp.Decode(cfg); err != nil {
+		err := errors.Wrap(err, "failed to decode rs config")
+		return nil, err
+	}
+
+	if cfg.OK != 1 {
+		err := errors.Errorf("mongo says: %s", cfg.Errmsg)
+		return nil, err
+	}
+
+	return cfg.Config, nil
+}
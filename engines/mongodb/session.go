@@ -0,0 +1,120 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mongodb
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// oplogNamespace is tailed by the secondaries, their getMore is never to be killed.
+const oplogNamespace = "local.oplog.rs"
+
+var _ engines.SessionManager = &Manager{}
+
+// ListSessions lists the client operations of currentOp, the idle connections are
+// not included.
+func (mgr *Manager) ListSessions(ctx context.Context) ([]models.Session, error) {
+	var resp struct {
+		Inprog []bson.Raw `bson:"inprog"`
+	}
+	cmd := bson.D{{Key: "currentOp", Value: 1}}
+	if err := mgr.GetClient().Database("admin").RunCommand(ctx, cmd).Decode(&resp); err != nil {
+		return nil, errors.Wrap(err, "current op failed")
+	}
+	return parseCurrentOp(resp.Inprog), nil
+}
+
+func (mgr *Manager) KillSession(ctx context.Context, id string) error {
+	cmd := bson.D{{Key: "killOp", Value: 1}, {Key: "op", Value: opID(id)}}
+	if err := mgr.GetClient().Database("admin").RunCommand(ctx, cmd).Err(); err != nil {
+		return errors.Wrapf(err, "kill op %s failed", id)
+	}
+	return nil
+}
+
+// opID returns the op of killOp, it's a number on mongod and a string of
+// shard:opid on mongos.
+func opID(id string) any {
+	opid, err := strconv.ParseInt(id, 10, 64)
+	switch {
+	case err != nil:
+		return id
+	case opid >= math.MinInt32 && opid <= math.MaxInt32:
+		return int32(opid)
+	default:
+		return opid
+	}
+}
+
+// parseCurrentOp keeps the command as a raw document, for its fields to be in the
+// order as they are sent.
+func parseCurrentOp(inprog []bson.Raw) []models.Session {
+	sessions := []models.Session{}
+	for _, raw := range inprog {
+		var op bson.M
+		if err := bson.Unmarshal(raw, &op); err != nil {
+			continue
+		}
+		command, _ := raw.Lookup("command").DocumentOK()
+		ns := cast.ToString(op["ns"])
+		_, err := command.LookupErr("currentOp")
+		// skip the internal operations, the oplog readers, and currentOp itself
+		if op["client"] == nil || ns == oplogNamespace || err == nil {
+			continue
+		}
+
+		session := models.Session{
+			ID:       cast.ToString(op["opid"]),
+			Database: strings.SplitN(ns, ".", 2)[0],
+			Client:   cast.ToString(op["client"]),
+			State:    "idle",
+		}
+		if cast.ToBool(op["active"]) {
+			session.State = "active"
+		}
+		if users, ok := op["effectiveUsers"].(bson.A); ok && len(users) > 0 {
+			if user, ok := users[0].(bson.M); ok {
+				session.User = cast.ToString(user["user"])
+			}
+		}
+		if microseconds, ok := op["microsecs_running"]; ok {
+			session.DurationSeconds = cast.ToFloat64(microseconds) / 1e6
+		} else {
+			session.DurationSeconds = cast.ToFloat64(op["secs_running"])
+		}
+		if len(command) > 0 {
+			if query, err := bson.MarshalExtJSON(command, false, false); err == nil {
+				session.Query = string(query)
+			}
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
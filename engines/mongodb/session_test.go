@@ -0,0 +1,68 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestParseCurrentOp(t *testing.T) {
+	var inprog []bson.Raw
+	for _, op := range []bson.D{
+		{
+			{Key: "opid", Value: int32(1234)}, {Key: "active", Value: true}, {Key: "client", Value: "10.0.0.3:51234"},
+			{Key: "ns", Value: "orders.items"}, {Key: "microsecs_running", Value: int64(95500000)},
+			{Key: "effectiveUsers", Value: bson.A{bson.D{{Key: "user", Value: "app"}, {Key: "db", Value: "admin"}}}},
+			{Key: "command", Value: bson.D{{Key: "find", Value: "items"}, {Key: "filter", Value: bson.D{{Key: "status", Value: "new"}}}}},
+		},
+		// a secondary tailing the oplog
+		{
+			{Key: "opid", Value: int32(1235)}, {Key: "active", Value: true}, {Key: "client", Value: "10.0.0.5:40000"},
+			{Key: "ns", Value: "local.oplog.rs"}, {Key: "secs_running", Value: int64(900)},
+			{Key: "command", Value: bson.D{{Key: "getMore", Value: int64(1)}, {Key: "collection", Value: "oplog.rs"}}},
+		},
+		{
+			{Key: "opid", Value: int32(1236)}, {Key: "active", Value: true}, {Key: "client", Value: "127.0.0.1:50000"},
+			{Key: "ns", Value: "admin.$cmd.aggregate"}, {Key: "command", Value: bson.D{{Key: "currentOp", Value: int32(1)}}},
+		},
+		// an internal operation
+		{{Key: "opid", Value: int32(1)}, {Key: "active", Value: true}, {Key: "desc", Value: "ReplBatcher"}},
+	} {
+		raw, err := bson.Marshal(op)
+		assert.Nil(t, err)
+		inprog = append(inprog, raw)
+	}
+
+	assert.Equal(t, []models.Session{{
+		ID: "1234", User: "app", Database: "orders", Client: "10.0.0.3:51234", State: "active", DurationSeconds: 95.5,
+		Query: `{"find":"items","filter":{"status":"new"}}`,
+	}}, parseCurrentOp(inprog))
+}
+
+func TestOpID(t *testing.T) {
+	assert.Equal(t, int32(1234), opID("1234"))
+	assert.Equal(t, int64(1<<40), opID("1099511627776"))
+	assert.Equal(t, "shard0:1234", opID("shard0:1234"))
+}
@@ -0,0 +1,97 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mongodb
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// fenceStepDownSecs is how long the fenced primary is not electable.
+const fenceStepDownSecs = 60
+
+func (mgr *Manager) GetMemberView(ctx context.Context, address string) (*models.MemberView, error) {
+	client, release, err := mgr.memberClient(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	status, err := GetReplSetStatus(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	self := status.GetSelf()
+	if self == nil {
+		return nil, errors.Errorf("member %s is not in the replica set", address)
+	}
+
+	view := &models.MemberView{
+		Address:  address,
+		Role:     models.NewRoleInfo(self.StateStr).Role,
+		Writable: self.State == MemberStatePrimary,
+		Term:     status.Term,
+	}
+	if primary := status.GetPrimary(); primary != nil && primary != self {
+		view.Leader = primary.Name
+	}
+	return view, nil
+}
+
+// FenceMember steps the primary down by force, as a stale primary has no caught
+// up secondary to hand over to.
+func (mgr *Manager) FenceMember(ctx context.Context, address string) error {
+	client, release, err := mgr.memberClient(ctx, address)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	cmd := bson.D{{Key: "replSetStepDown", Value: fenceStepDownSecs}, {Key: "force", Value: true}}
+	if err = client.Database("admin").RunCommand(ctx, cmd).Err(); err != nil {
+		return errors.Wrapf(err, "step down member %s failed", address)
+	}
+	return nil
+}
+
+// memberClient connects to the member directly, the local client is returned if
+// the address is empty.
+func (mgr *Manager) memberClient(ctx context.Context, address string) (*mongo.Client, func(), error) {
+	if address == "" {
+		return mgr.GetClient(), func() {}, nil
+	}
+
+	config := *mgr.config
+	config.Hosts = []string{address}
+	config.Direct = true
+
+	client, err := newClient(ctx, &config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, func() {
+		_ = client.Disconnect(context.Background())
+	}, nil
+}
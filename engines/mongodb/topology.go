@@ -0,0 +1,59 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mongodb
+
+import (
+	"context"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func (mgr *Manager) GetTopology(ctx context.Context) (*models.Topology, error) {
+	status, err := mgr.GetReplSetStatus(ctx)
+	if err != nil {
+		mgr.Logger.Info("rs.status() error", "error", err.Error())
+		return nil, err
+	}
+	return topology(status), nil
+}
+
+// topology builds the members from replSetGetStatus, the lag of a member is its
+// optime distance to the primary.
+func topology(rsStatus *ReplSetStatus) *models.Topology {
+	topology := &models.Topology{
+		Members: make([]models.Member, 0, len(rsStatus.Members)),
+		Term:    rsStatus.Term,
+	}
+
+	primary := rsStatus.GetPrimary()
+	for _, m := range rsStatus.Members {
+		member := models.NewMember(m.Name, m.StateStr)
+		member.Self = m.Self
+		if m.Health == 0 {
+			member.Health = models.HealthUnhealthy
+		}
+		if primary != nil && m != primary && !primary.OptimeDate.IsZero() && !m.OptimeDate.IsZero() {
+			lagSeconds := max(int64(primary.OptimeDate.Sub(m.OptimeDate).Seconds()), 0)
+			member.LagSeconds = &lagSeconds
+		}
+		topology.Members = append(topology.Members, member)
+	}
+	return topology
+}
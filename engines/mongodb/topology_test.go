@@ -0,0 +1,53 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestTopology(t *testing.T) {
+	now := time.Now()
+	topology := topology(&ReplSetStatus{
+		Term: 2,
+		Members: []*Member{
+			{Name: "mongo-0:27017", Health: 1, State: MemberStatePrimary, StateStr: "PRIMARY", OptimeDate: now},
+			{Name: "mongo-1:27017", Health: 1, State: 2, StateStr: "SECONDARY", OptimeDate: now.Add(-2 * time.Second), Self: true},
+			{Name: "mongo-2:27017", Health: 0, State: 8, StateStr: "(not reachable/healthy)"},
+			{Name: "mongo-3:27017", Health: 1, State: 7, StateStr: "ARBITER"},
+		},
+	})
+
+	assert.Equal(t, int64(2), topology.Term)
+	assert.Len(t, topology.Members, 4)
+	assert.Equal(t, models.PRIMARY, topology.Members[0].Role)
+	assert.Nil(t, topology.Members[0].LagSeconds)
+	assert.Equal(t, models.SECONDARY, topology.Members[1].Role)
+	assert.True(t, topology.Members[1].Self)
+	assert.Equal(t, int64(2), *topology.Members[1].LagSeconds)
+	assert.Equal(t, models.HealthUnhealthy, topology.Members[2].Health)
+	assert.Equal(t, models.ARBITER, topology.Members[3].Role)
+	assert.Nil(t, topology.Members[3].LagSeconds)
+}
@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package mongodb
 
 import (
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -177,6 +178,16 @@ type Member struct {
 	PingMs            int64               `bson:"pingMs,omitempty" json:"pingMs,omitempty"`
 	Self              bool                `bson:"self,omitempty" json:"self,omitempty"`
 	SyncingTo         string              `bson:"syncingTo,omitempty" json:"syncingTo,omitempty"`
+	SyncSourceHost    string              `bson:"syncSourceHost,omitempty" json:"syncSourceHost,omitempty"`
+}
+
+func (s *ReplSetStatus) GetPrimary() *Member {
+	for _, member := range s.Members {
+		if member.State == MemberStatePrimary {
+			return member
+		}
+	}
+	return nil
 }
 
 func (s *ReplSetStatus) GetSelf() *Member {
@@ -193,6 +204,13 @@ type Optime struct {
 	Term      int64               `bson:"t" json:"t"`
 }
 
+func (o *Optime) String() string {
+	if o == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d.%d/%d", o.Timestamp.T, o.Timestamp.I, o.Term)
+}
+
 type StatusOptimes struct {
 	LastCommittedOpTime *Optime `bson:"lastCommittedOpTime" json:"lastCommittedOpTime"`
 	AppliedOpTime       *Optime `bson:"appliedOpTime" json:"appliedOpTime"`
@@ -201,3 +219,6 @@ type StatusOptimes struct {
 
 type MemberHealth int
 type MemberState int
+
+// MemberStatePrimary is the state of the primary in replSetGetStatus.
+const MemberStatePrimary MemberState = 1
@@ -20,10 +20,14 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package mysql
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"database/sql"
 	"fmt"
+	"net"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
@@ -32,32 +36,57 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/apecloud/dbctl/constant"
+	utilconfig "github.com/apecloud/dbctl/util/config"
 )
 
 const (
 	// configurations to connect to MySQL, either a data source name represent by URL.
 	connectionURLKey = "url"
+
+	// customTLSConfig is the TLS config registered to the driver for the CA file or
+	// the client certificate of the config file.
+	customTLSConfig = "custom"
 )
 
 const (
-	adminDatabase = "mysql"
-	defaultDBPort = 3306
-	EnvRootUser   = "MYSQL_ROOT_USER"
-	EnvRootPass   = "MYSQL_ROOT_PASSWORD"
+	// configSection is the config file section shared by all MySQL-protocol engines.
+	configSection = "mysql"
+
+	defaultDBHost  = "127.0.0.1"
+	defaultDBPort  = 3306
+	defaultTimeout = 5 * time.Second
+	EnvRootUser    = "MYSQL_ROOT_USER"
+	EnvRootPass    = "MYSQL_ROOT_PASSWORD"
 )
 
 type Config struct {
 	URL                 string
+	Host                string
 	Port                string
 	Username            string
 	Password            string
 	pemPath             string
+	certPath            string
+	keyPath             string
+	tlsEnabled          bool
+	tlsSkipVerify       bool
 	MaxIdleConns        int
 	MaxOpenConns        int
+	ConnectTimeout      time.Duration
+	ReadTimeout         time.Duration
+	WriteTimeout        time.Duration
 	AdminUsername       string
 	AdminPassword       string
 	ReplicationUsername string
 	ReplicationPassword string
+	// userEnv and passwordEnv are the envs of the root credentials.
+	userEnv     string
+	passwordEnv string
+	// instance is the instance whose envs and credentials are looked up.
+	instance string
+
+	// credentialLock guards the credentials which are reloaded on rotation.
+	credentialLock sync.RWMutex
 }
 
 var fs = afero.NewOsFs()
@@ -65,23 +94,69 @@ var fs = afero.NewOsFs()
 var config *Config
 
 func NewConfig() (*Config, error) {
+	return NewConfigWithEnvs(EnvRootUser, EnvRootPass)
+}
+
+// NewConfigWithEnvs reads the root credentials from the envs of the engine before
+// the MySQL ones, e.g. FOXLAKE_ROOT_USER of FoxLake.
+func NewConfigWithEnvs(userEnv, passwordEnv string) (*Config, error) {
 	config = &Config{
-		URL:          "root:@tcp(127.0.0.1:3306)/mysql?multiStatements=true",
-		MaxIdleConns: 1,
-		MaxOpenConns: 5,
+		userEnv:        userEnv,
+		passwordEnv:    passwordEnv,
+		instance:       utilconfig.CurrentInstance(),
+		URL:            "root:@tcp(127.0.0.1:3306)/mysql?multiStatements=true",
+		MaxIdleConns:   1,
+		MaxOpenConns:   5,
+		ConnectTimeout: defaultTimeout,
+		ReadTimeout:    defaultTimeout,
+		WriteTimeout:   defaultTimeout,
 	}
 
-	config.Username = getRootUserName()
-	config.Password = getRootPassword()
-	config.AdminUsername = getAdminUserName()
-	config.AdminPassword = getAdminPassword()
-	config.ReplicationUsername = getReplicationUserName()
-	config.ReplicationPassword = getReplicationPassword()
+	engineConfig, err := utilconfig.GetInstanceEngineConfig(config.instance, configSection)
+	if err != nil {
+		return nil, err
+	}
+	config.mergeEngineConfig(engineConfig)
+	config.setCredentials(engineConfig)
 
 	if viper.IsSet(constant.KBEnvServicePort) {
 		config.Port = viper.GetString(constant.KBEnvServicePort)
 	}
 
+	if err = config.registerTLSConfig(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// registerTLSConfig registers the TLS config with the CA file and the client
+// certificate to the driver if any of them is set.
+func (config *Config) registerTLSConfig() error {
+	if !config.customTLS() {
+		return nil
+	}
+	tlsConfig, err := config.newTLSConfig()
+	if err != nil {
+		return err
+	}
+	if err = mysql.RegisterTLSConfig(customTLSConfig, tlsConfig); err != nil {
+		return errors.Wrap(err, "Error register TLS config")
+	}
+	return nil
+}
+
+// newTLSConfig builds the TLS config with the CA file and the client certificate,
+// the server certificate is not verified if insecureSkipVerify is set.
+func (config *Config) newTLSConfig() (*tls.Config, error) {
+	if (config.certPath == "") != (config.keyPath == "") {
+		return nil, errors.New("both tls.certFile and tls.keyFile are required for the client certificate")
+	}
+
+	/* #nosec */
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.tlsSkipVerify,
+	}
 	if config.pemPath != "" {
 		rootCertPool := x509.NewCertPool()
 		pem, err := afero.ReadFile(fs, config.pemPath)
@@ -93,82 +168,190 @@ func NewConfig() (*Config, error) {
 		if !ok {
 			return nil, fmt.Errorf("failed to append PEM")
 		}
-
-		err = mysql.RegisterTLSConfig("custom", &tls.Config{RootCAs: rootCertPool, MinVersion: tls.VersionTLS12})
+		tlsConfig.RootCAs = rootCertPool
+	}
+	if config.certPath != "" {
+		certPEM, err := afero.ReadFile(fs, config.certPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error reading client certificate from %s", config.certPath)
+		}
+		keyPEM, err := afero.ReadFile(fs, config.keyPath)
 		if err != nil {
-			return nil, errors.Wrap(err, "Error register TLS config")
+			return nil, errors.Wrapf(err, "Error reading client key from %s", config.keyPath)
 		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, errors.Wrap(err, "load client certificate failed")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
-	return config, nil
+	return tlsConfig, nil
+}
+
+// customTLS tells whether the TLS config of the CA file or the client certificate is used.
+func (config *Config) customTLS() bool {
+	return config.pemPath != "" || config.certPath != "" || config.keyPath != ""
+}
+
+func (config *Config) mergeEngineConfig(engineConfig *utilconfig.EngineConfig) {
+	if engineConfig.Host != "" {
+		config.Host = engineConfig.Host
+	}
+	if engineConfig.Port != 0 {
+		config.Port = strconv.Itoa(engineConfig.Port)
+	}
+	if engineConfig.MaxOpenConns != 0 {
+		config.MaxOpenConns = engineConfig.MaxOpenConns
+	}
+	if engineConfig.MaxIdleConns != 0 {
+		config.MaxIdleConns = engineConfig.MaxIdleConns
+	}
+	if engineConfig.ConnectTimeout != 0 {
+		config.ConnectTimeout = engineConfig.ConnectTimeout
+	}
+	if engineConfig.ReadTimeout != 0 {
+		config.ReadTimeout = engineConfig.ReadTimeout
+	}
+	if engineConfig.WriteTimeout != 0 {
+		config.WriteTimeout = engineConfig.WriteTimeout
+	}
+	config.tlsEnabled = engineConfig.TLS.Enabled
+	config.tlsSkipVerify = engineConfig.TLS.InsecureSkipVerify
+	if engineConfig.TLS.Enabled {
+		config.pemPath = engineConfig.TLS.CAFile
+		config.certPath = engineConfig.TLS.CertFile
+		config.keyPath = engineConfig.TLS.KeyFile
+	}
+}
+
+// ReloadCredentials reads the credentials again, it's called when the mounted secrets are rotated.
+func (config *Config) ReloadCredentials() error {
+	engineConfig, err := utilconfig.GetInstanceEngineConfig(config.instance, configSection)
+	if err != nil {
+		return err
+	}
+	config.setCredentials(engineConfig)
+	return nil
+}
+
+// GetCredentials returns the current username and password to connect to MySQL.
+func (config *Config) GetCredentials() (string, string) {
+	config.credentialLock.RLock()
+	defer config.credentialLock.RUnlock()
+	return config.Username, config.Password
 }
 
-func getRootUserName() string {
-	if viper.IsSet(constant.KBEnvServiceUser) {
-		return viper.GetString(constant.KBEnvServiceUser)
-	} else if viper.IsSet(EnvRootUser) {
-		return viper.GetString(EnvRootUser)
+func (config *Config) setCredentials(engineConfig *utilconfig.EngineConfig) {
+	config.credentialLock.Lock()
+	defer config.credentialLock.Unlock()
+
+	// credentials from env take precedence over the config file
+	config.Username = config.getRootUserName(engineConfig.Username)
+	config.Password = config.getRootPassword(engineConfig.Password)
+	config.AdminUsername = config.getAdminUserName(engineConfig.Username)
+	config.AdminPassword = config.getAdminPassword(engineConfig.Password)
+	config.ReplicationUsername = config.getReplicationUserName(engineConfig.Username)
+	config.ReplicationPassword = config.getReplicationPassword(engineConfig.Password)
+}
+
+func (config *Config) getRootUserName(def string) string {
+	if user, ok := utilconfig.LookupInstanceCredential(config.instance, constant.ConfigKeyUserName, constant.KBEnvServiceUser, config.userEnv, EnvRootUser); ok {
+		return user
 	}
-	return ""
+	return def
 }
 
-func getRootPassword() string {
-	if viper.IsSet(constant.KBEnvServicePassword) {
-		return viper.GetString(constant.KBEnvServicePassword)
-	} else if viper.IsSet(EnvRootPass) {
-		return viper.GetString(EnvRootPass)
+func (config *Config) getRootPassword(def string) string {
+	if password, ok := utilconfig.LookupInstanceCredential(config.instance, constant.ConfigKeyPassword, constant.KBEnvServicePassword, config.passwordEnv, EnvRootPass); ok {
+		return password
 	}
-	return ""
+	return def
 }
 
-func getAdminUserName() string {
+func (config *Config) getAdminUserName(def string) string {
 	// if the user is not set, use the root user
-	if viper.IsSet("MYSQL_ADMIN_USER") {
-		return viper.GetString("MYSQL_ADMIN_USER")
+	if user, ok := utilconfig.LookupInstanceEnv(config.instance, "MYSQL_ADMIN_USER"); ok {
+		return user
 	}
-	return getRootUserName()
+	return config.getRootUserName(def)
 }
 
-func getAdminPassword() string {
+func (config *Config) getAdminPassword(def string) string {
 	// if the password is not set, use the root password
-	if viper.IsSet("MYSQL_ADMIN_PASSWORD") {
-		return viper.GetString("MYSQL_ADMIN_PASSWORD")
+	if password, ok := utilconfig.LookupInstanceEnv(config.instance, "MYSQL_ADMIN_PASSWORD"); ok {
+		return password
 	}
-	return getRootPassword()
+	return config.getRootPassword(def)
 }
 
-func getReplicationUserName() string {
+func (config *Config) getReplicationUserName(def string) string {
 	// if the user is not set, use the admin user
-	if viper.IsSet("MYSQL_REPLICATION_USER") {
-		return viper.GetString("MYSQL_REPLICATION_USER")
+	if user, ok := utilconfig.LookupInstanceEnv(config.instance, "MYSQL_REPLICATION_USER"); ok {
+		return user
 	}
-	return getAdminUserName()
+	return config.getAdminUserName(def)
 }
 
-func getReplicationPassword() string {
+func (config *Config) getReplicationPassword(def string) string {
 	// if the password is not set, use the admin password
-	if viper.IsSet("MYSQL_REPLICATION_PASSWORD") {
-		return viper.GetString("MYSQL_REPLICATION_PASSWORD")
+	if password, ok := utilconfig.LookupInstanceEnv(config.instance, "MYSQL_REPLICATION_PASSWORD"); ok {
+		return password
 	}
-	return getAdminPassword()
+	return config.getAdminPassword(def)
 }
 
 func (config *Config) GetLocalDBConn() (*sql.DB, error) {
+	return config.GetDBConnWithAddr("")
+}
+
+// GetDBConnWithAddr connects to the member at addr with the local settings, e.g.
+// the credentials and TLS, the local address is used if addr is empty.
+func (config *Config) GetDBConnWithAddr(addr string) (*sql.DB, error) {
 	mysqlConfig, err := mysql.ParseDSN(config.URL)
 	if err != nil {
 		return nil, errors.Wrapf(err, "illegal Data Source Name (DNS) specified by %s", connectionURLKey)
 	}
-	mysqlConfig.User = config.Username
-	mysqlConfig.Passwd = config.Password
-	mysqlConfig.Timeout = time.Second * 5
-	mysqlConfig.ReadTimeout = time.Second * 5
-	mysqlConfig.WriteTimeout = time.Second * 5
-	if config.Port != "" {
-		mysqlConfig.Addr = "127.0.0.1:" + config.Port
+	mysqlConfig.User, mysqlConfig.Passwd = config.GetCredentials()
+	mysqlConfig.Timeout = config.ConnectTimeout
+	mysqlConfig.ReadTimeout = config.ReadTimeout
+	mysqlConfig.WriteTimeout = config.WriteTimeout
+	if config.Host != "" || config.Port != "" {
+		host := config.Host
+		if host == "" {
+			host = defaultDBHost
+		}
+		port := config.Port
+		if port == "" {
+			port = strconv.Itoa(defaultDBPort)
+		}
+		mysqlConfig.Addr = net.JoinHostPort(host, port)
+	}
+	if addr != "" {
+		mysqlConfig.Addr = addr
+	}
+	switch {
+	case config.customTLS():
+		mysqlConfig.TLSConfig = customTLSConfig
+	case config.tlsEnabled && config.tlsSkipVerify:
+		mysqlConfig.TLSConfig = "skip-verify"
+	case config.tlsEnabled:
+		mysqlConfig.TLSConfig = "true"
+	}
+	// new connections always pick up the latest credentials, so that the
+	// connection pool keeps working after the secrets are rotated.
+	err = mysqlConfig.Apply(mysql.BeforeConnect(func(_ context.Context, cfg *mysql.Config) error {
+		cfg.User, cfg.Passwd = config.GetCredentials()
+		return nil
+	}))
+	if err != nil {
+		return nil, errors.Wrap(err, "apply DB config failed")
 	}
-	db, err := GetDBConnection(mysqlConfig.FormatDSN())
+	db, err := GetDBConnection(mysqlConfig)
 	if err != nil {
 		return nil, errors.Wrap(err, "get DB connection failed")
 	}
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
 
 	return db, nil
 }
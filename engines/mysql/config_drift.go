@@ -0,0 +1,77 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mysql
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/configfile"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+var _ engines.ConfigDriftChecker = &Manager{}
+
+// myCnfPaths are where mysqld reads my.cnf by default.
+var myCnfPaths = []string{"/etc/my.cnf", "/etc/mysql/my.cnf"}
+
+// sizeUnits are the suffixes of the sizes in my.cnf.
+var sizeUnits = map[string]float64{
+	"k": 1 << 10,
+	"m": 1 << 20,
+	"g": 1 << 30,
+	"t": 1 << 40,
+	"p": 1 << 50,
+}
+
+func (mgr *Manager) CheckConfigDrift(ctx context.Context, file string) (*models.ConfigDriftReport, error) {
+	if file == "" {
+		for _, path := range myCnfPaths {
+			if _, err := os.Stat(path); err == nil {
+				file = path
+				break
+			}
+		}
+		if file == "" {
+			return nil, errors.New("no my.cnf found")
+		}
+	}
+	config, err := configfile.ParseMyCnf(file)
+	if err != nil {
+		return nil, err
+	}
+
+	live := map[string]string{}
+	err = QueryRowsMap(mgr.DB, "show global variables", func(row RowMap) error {
+		live[strings.ToLower(row.GetString("Variable_name"))] = row.GetString("Value")
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "show global variables failed")
+	}
+
+	return configfile.Diff(config, live, func(_, fileValue, liveValue string) bool {
+		return configfile.EqualValues(fileValue, liveValue, sizeUnits)
+	}), nil
+}
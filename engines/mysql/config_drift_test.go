@@ -0,0 +1,52 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mysql
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestCheckConfigDrift(t *testing.T) {
+	manager, mock, _ := mockDatabase(t)
+	file := filepath.Join(t.TempDir(), "my.cnf")
+	assert.Nil(t, os.WriteFile(file, []byte("[mysqld]\nmax_connections=500\ninnodb_buffer_pool_size=1G\nfoo=bar\n"), 0644))
+
+	mock.ExpectQuery("show global variables").WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).
+		AddRow("max_connections", "151").
+		AddRow("innodb_buffer_pool_size", "1073741824"))
+
+	report, err := manager.CheckConfigDrift(context.TODO(), file)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{file}, report.Files)
+	assert.Equal(t, []models.ParameterDrift{{Name: "max_connections", FileValue: "500", LiveValue: "151", File: file}}, report.Drifts)
+	assert.Equal(t, []string{"foo"}, report.Unknown)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -20,8 +20,17 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package mysql
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/spf13/afero"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -42,6 +51,111 @@ func TestNewConfig(t *testing.T) {
 		assert.Equal(t, 5, fakeConfig.MaxOpenConns)
 		assert.Equal(t, 1, fakeConfig.MaxIdleConns)
 	})
+
+	t.Run("with config file", func(t *testing.T) {
+		viper.Set("mysql.host", "10.0.0.1")
+		viper.Set("mysql.port", 3307)
+		viper.Set("mysql.username", "file-user")
+		viper.Set("mysql.password", "file-pwd")
+		viper.Set("mysql.maxOpenConns", 10)
+		viper.Set(EnvRootPass, "env-pwd")
+		defer viper.Reset()
+
+		fakeConfig, err := NewConfig()
+		assert.Nil(t, err)
+		assert.Equal(t, "10.0.0.1", fakeConfig.Host)
+		assert.Equal(t, "3307", fakeConfig.Port)
+		assert.Equal(t, 10, fakeConfig.MaxOpenConns)
+		assert.Equal(t, "file-user", fakeConfig.Username)
+		assert.Equal(t, "file-user", fakeConfig.ReplicationUsername)
+		// env takes precedence over the config file
+		assert.Equal(t, "env-pwd", fakeConfig.Password)
+		assert.Equal(t, "env-pwd", fakeConfig.AdminPassword)
+	})
+
+	t.Run("with engine envs", func(t *testing.T) {
+		viper.Set("FOXLAKE_ROOT_USER", "fox")
+		viper.Set("FOXLAKE_ROOT_PASSWORD", "fox-pwd")
+		viper.Set(EnvRootPass, "env-pwd")
+		defer viper.Reset()
+
+		fakeConfig, err := NewConfigWithEnvs("FOXLAKE_ROOT_USER", "FOXLAKE_ROOT_PASSWORD")
+		assert.Nil(t, err)
+		assert.Equal(t, "fox", fakeConfig.Username)
+		// the engine envs take precedence over the MySQL ones
+		assert.Equal(t, "fox-pwd", fakeConfig.Password)
+		assert.Equal(t, "fox-pwd", fakeConfig.ReplicationPassword)
+	})
+}
+
+// newKeyPair returns a self-signed certificate and its key in PEM.
+func newKeyPair(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "dbctl"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestNewConfigWithTLS(t *testing.T) {
+	fs = afero.NewMemMapFs()
+	defer func() {
+		fs = afero.NewOsFs()
+		viper.Reset()
+	}()
+	certPEM, keyPEM := newKeyPair(t)
+	assert.Nil(t, afero.WriteFile(fs, "/tls/ca.crt", certPEM, 0600))
+	assert.Nil(t, afero.WriteFile(fs, "/tls/tls.crt", certPEM, 0600))
+	assert.Nil(t, afero.WriteFile(fs, "/tls/tls.key", keyPEM, 0600))
+	viper.Set("mysql.tls.enabled", true)
+	viper.Set("mysql.tls.caFile", "/tls/ca.crt")
+	viper.Set("mysql.tls.certFile", "/tls/tls.crt")
+	viper.Set("mysql.tls.keyFile", "/tls/tls.key")
+	viper.Set("mysql.tls.insecureSkipVerify", true)
+
+	t.Run("client certificate", func(t *testing.T) {
+		fakeConfig, err := NewConfig()
+		assert.Nil(t, err)
+		tlsConfig, err := fakeConfig.newTLSConfig()
+		assert.Nil(t, err)
+		assert.NotNil(t, tlsConfig.RootCAs)
+		assert.Len(t, tlsConfig.Certificates, 1)
+		assert.True(t, tlsConfig.InsecureSkipVerify)
+
+		closeConnectionPools()
+		db, err := fakeConfig.GetLocalDBConn()
+		assert.Nil(t, err)
+		assert.NotNil(t, db)
+		assert.Len(t, connectionPoolCache, 1)
+		for _, pool := range connectionPoolCache {
+			mysqlConfig, err := mysql.ParseDSN(pool.dsn)
+			assert.Nil(t, err)
+			assert.Equal(t, customTLSConfig, mysqlConfig.TLSConfig)
+		}
+	})
+
+	t.Run("client certificate without key", func(t *testing.T) {
+		viper.Set("mysql.tls.keyFile", "")
+		defer viper.Set("mysql.tls.keyFile", "/tls/tls.key")
+		_, err := NewConfig()
+		assert.ErrorContains(t, err, "both tls.certFile and tls.keyFile are required")
+	})
+
+	t.Run("invalid client key", func(t *testing.T) {
+		viper.Set("mysql.tls.keyFile", "/tls/ca.crt")
+		defer viper.Set("mysql.tls.keyFile", "/tls/tls.key")
+		_, err := NewConfig()
+		assert.ErrorContains(t, err, "load client certificate failed")
+	})
 }
 
 func TestConfig_GetLocalDBConn(t *testing.T) {
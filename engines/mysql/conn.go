@@ -17,21 +17,58 @@ package mysql
 
 import (
 	"database/sql"
+	"sync"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// connectionPool is a cached pool, dsn is the DSN without the password.
+type connectionPool struct {
+	dsn string
+	db  *sql.DB
+}
+
+var (
+	connectionPoolLock  sync.Mutex
+	connectionPoolCache = make(map[string]*connectionPool)
 )
 
-var connectionPoolCache = make(map[string]*sql.DB)
+// GetDBConnection returns a DB Connection based on the driver config. The pools are
+// cached by the user and the address without the password, the new connections
+// pick up the rotated credentials by BeforeConnect. A pool of the same user and
+// address with the other settings changed is replaced and closed.
+func GetDBConnection(mysqlConfig *mysql.Config) (*sql.DB, error) {
+	key := mysqlConfig.User + "@" + mysqlConfig.Addr + "/" + mysqlConfig.DBName
+	withoutPasswd := mysqlConfig.Clone()
+	withoutPasswd.Passwd = ""
+	dsn := withoutPasswd.FormatDSN()
 
-// GetDBConnection returns a DB Connection based on dsn.
-func GetDBConnection(dsn string) (*sql.DB, error) {
-	if db, ok := connectionPoolCache[dsn]; ok {
-		return db, nil
+	connectionPoolLock.Lock()
+	defer connectionPoolLock.Unlock()
+	if pool, ok := connectionPoolCache[key]; ok {
+		if pool.dsn == dsn {
+			return pool.db, nil
+		}
+		_ = pool.db.Close()
+		delete(connectionPoolCache, key)
 	}
 
-	db, err := sql.Open(adminDatabase, dsn)
+	connector, err := mysql.NewConnector(mysqlConfig)
 	if err != nil {
 		return nil, err
 	}
+	db := sql.OpenDB(connector)
 
-	connectionPoolCache[dsn] = db
+	connectionPoolCache[key] = &connectionPool{dsn: dsn, db: db}
 	return db, nil
 }
+
+// closeConnectionPools closes all the cached pools.
+func closeConnectionPools() {
+	connectionPoolLock.Lock()
+	defer connectionPoolLock.Unlock()
+	for _, pool := range connectionPoolCache {
+		_ = pool.db.Close()
+	}
+	connectionPoolCache = make(map[string]*connectionPool)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mysql
+
+import (
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDBConnection(t *testing.T) {
+	closeConnectionPools()
+	defer closeConnectionPools()
+	newConfig := func(passwd string, timeout string) *mysql.Config {
+		config, err := mysql.ParseDSN("root:" + passwd + "@tcp(127.0.0.1:3306)/mysql?timeout=" + timeout)
+		assert.Nil(t, err)
+		return config
+	}
+
+	db, err := GetDBConnection(newConfig("old", "1s"))
+	assert.Nil(t, err)
+	assert.Len(t, connectionPoolCache, 1)
+	for key, pool := range connectionPoolCache {
+		assert.NotContains(t, key, "old")
+		assert.NotContains(t, pool.dsn, "old")
+	}
+
+	t.Run("same pool with the rotated password", func(t *testing.T) {
+		rotated, err := GetDBConnection(newConfig("new", "1s"))
+		assert.Nil(t, err)
+		assert.Same(t, db, rotated)
+		assert.Len(t, connectionPoolCache, 1)
+	})
+
+	t.Run("replaced pool is closed", func(t *testing.T) {
+		replaced, err := GetDBConnection(newConfig("new", "2s"))
+		assert.Nil(t, err)
+		assert.NotSame(t, db, replaced)
+		assert.Len(t, connectionPoolCache, 1)
+		assert.ErrorContains(t, db.Ping(), "database is closed")
+	})
+}
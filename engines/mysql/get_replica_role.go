@@ -32,7 +32,11 @@ func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
 }
 
 func (mgr *Manager) GetReplicaRoleFromDB(ctx context.Context) (string, error) {
-	if mgr.isSlaveRunning(ctx) {
+	slaveStatus, err := mgr.getSlaveStatus(ctx)
+	if err != nil {
+		return "", err
+	}
+	if mgr.isSlaveRunning(ctx, slaveStatus) {
 		return constant.Secondary, nil
 	}
 
@@ -57,9 +61,7 @@ func (mgr *Manager) GetReplicaRoleFromDB(ctx context.Context) (string, error) {
 	return constant.Primary, nil
 }
 
-func (mgr *Manager) isSlaveRunning(ctx context.Context) bool {
-	var rowMap = mgr.slaveStatus
-
+func (mgr *Manager) isSlaveRunning(ctx context.Context, rowMap RowMap) bool {
 	if len(rowMap) == 0 {
 		return false
 	}
@@ -28,6 +28,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/apecloud/dbctl/engines"
+	utilconfig "github.com/apecloud/dbctl/util/config"
 )
 
 type Manager struct {
@@ -38,18 +39,22 @@ type Manager struct {
 	binlogFormat                 string
 	logbinEnabled                bool
 	logReplicationUpdatesEnabled bool
-	slaveStatus                  RowMap
+	config                       *Config
 }
 
 var _ engines.DBManager = &Manager{}
 
 func NewManager() (engines.DBManager, error) {
-	logger := ctrl.Log.WithName("MySQL")
 	config, err := NewConfig()
 	if err != nil {
 		return nil, err
 	}
+	return NewManagerWithConfig(config)
+}
 
+// NewManagerWithConfig connects by the config, e.g. of the envs of a MySQL-protocol engine.
+func NewManagerWithConfig(config *Config) (engines.DBManager, error) {
+	logger := ctrl.Log.WithName("MySQL")
 	managerBase, err := engines.NewDBManagerBase(logger)
 	if err != nil {
 		return nil, err
@@ -63,11 +68,26 @@ func NewManager() (engines.DBManager, error) {
 	mgr := &Manager{
 		DBManagerBase: *managerBase,
 		DB:            db,
+		config:        config,
 	}
 
+	utilconfig.OnCredentialsChange(mgr.reloadCredentials)
 	return mgr, nil
 }
 
+// reloadCredentials drops the idle connections after the credentials are reloaded,
+// new connections are established with the rotated credentials, while the in-use
+// ones are kept until the queries on them are finished.
+func (mgr *Manager) reloadCredentials() {
+	if err := mgr.config.ReloadCredentials(); err != nil {
+		mgr.Logger.Info("reload credentials failed", "error", err.Error())
+		return
+	}
+	mgr.DB.SetMaxIdleConns(0)
+	mgr.DB.SetMaxIdleConns(mgr.config.MaxIdleConns)
+	mgr.Logger.Info("credentials reloaded")
+}
+
 func (mgr *Manager) IsDBStartupReady() bool {
 	if mgr.DBStartupReady {
 		return true
@@ -87,6 +107,12 @@ func (mgr *Manager) IsDBStartupReady() bool {
 	return true
 }
 
+var _ engines.Pinger = &Manager{}
+
+func (mgr *Manager) Ping(ctx context.Context) error {
+	return mgr.DB.PingContext(ctx)
+}
+
 func (mgr *Manager) GetVersion(ctx context.Context) (string, error) {
 	if mgr.version != "" {
 		return mgr.version, nil
@@ -99,10 +125,7 @@ func (mgr *Manager) GetVersion(ctx context.Context) (string, error) {
 }
 
 func (mgr *Manager) ShutDownWithWait() {
-	for _, db := range connectionPoolCache {
-		_ = db.Close()
-	}
-	connectionPoolCache = make(map[string]*sql.DB)
+	closeConnectionPools()
 }
 
 func (mgr *Manager) IsReadonly(ctx context.Context) (bool, error) {
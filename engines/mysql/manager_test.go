@@ -23,10 +23,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/apecloud/dbctl/constant"
+	"github.com/apecloud/dbctl/engines/conformance"
 )
 
 const (
@@ -83,3 +85,30 @@ func TestManager_IsDBStartupReady(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %v", err)
 	}
 }
+
+func TestConformance(t *testing.T) {
+	manager, mock, _ := mockDatabase(t)
+	manager.version = "8.0.33"
+
+	conformance.Run(t, conformance.Suite{
+		Manager: manager,
+		Prepare: func(method conformance.Method) {
+			switch method {
+			case conformance.IsDBStartupReady:
+				mock.ExpectPing()
+			case conformance.GetReplicaRole, conformance.GetReplicaRoleInfo:
+				mock.ExpectQuery("show replica status").WillReturnRows(sqlmock.NewRows([]string{"Source_Host"}))
+				mock.ExpectQuery("show slave hosts").WillReturnRows(sqlmock.NewRows([]string{"Server_id"}))
+				mock.ExpectQuery("select @@global.hostname").WillReturnRows(
+					sqlmock.NewRows([]string{"hostname", "version", "read_only", "binlog_format", "log_bin", "log_slave_updates"}).
+						AddRow(fakePodName, "8.0.33", false, "ROW", true, true))
+			case conformance.Exec:
+				mock.ExpectExec("create database test").WillReturnResult(sqlmock.NewResult(0, 1))
+			case conformance.Query:
+				mock.ExpectQuery("select 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+			}
+		},
+		ExecSQL:  "create database test",
+		QuerySQL: "select 1",
+	})
+}
@@ -0,0 +1,130 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const (
+	parameterExistsSQL = "select count(*) from performance_schema.global_variables where variable_name = ?"
+
+	errUnknownSystemVariable = 1193
+	errReadOnlyVariable      = 1238
+)
+
+var _ engines.Reconfigurer = &Manager{}
+
+// staticParameters are the usual read-only variables in my.cnf, MySQL doesn't tell
+// whether a variable is dynamic, so the others are found static by SET PERSIST.
+var staticParameters = map[string]bool{
+	"bind_address":                 true,
+	"datadir":                      true,
+	"innodb_buffer_pool_instances": true,
+	"innodb_data_file_path":        true,
+	"innodb_data_home_dir":         true,
+	"innodb_log_file_size":         true,
+	"innodb_log_files_in_group":    true,
+	"innodb_page_size":             true,
+	"innodb_read_io_threads":       true,
+	"innodb_write_io_threads":      true,
+	"log_bin":                      true,
+	"lower_case_table_names":       true,
+	"performance_schema":           true,
+	"port":                         true,
+	"skip_name_resolve":            true,
+	"socket":                       true,
+}
+
+func (mgr *Manager) ClassifyParameter(ctx context.Context, name string) (models.ParameterKind, error) {
+	name = strings.ToLower(strings.ReplaceAll(name, "-", "_"))
+	if !engines.IsValidParameterName(name) {
+		return "", models.ErrUnknownParameter
+	}
+	var count int
+	if err := mgr.DB.QueryRowContext(ctx, parameterExistsSQL, name).Scan(&count); err != nil {
+		return "", errors.Wrapf(err, "query parameter %s failed", name)
+	}
+	if count == 0 {
+		return "", models.ErrUnknownParameter
+	}
+	if staticParameters[name] {
+		return models.ParameterStatic, nil
+	}
+	return models.ParameterDynamic, nil
+}
+
+// SetParameter sets the global variable and persists it to mysqld-auto.cnf, a
+// static variable is only persisted by SET PERSIST_ONLY for the next start.
+func (mgr *Manager) SetParameter(ctx context.Context, name, value string) error {
+	name = strings.ToLower(strings.ReplaceAll(name, "-", "_"))
+	if !engines.IsValidParameterName(name) {
+		return models.ErrUnknownParameter
+	}
+	if staticParameters[name] {
+		return mgr.persistOnly(ctx, name, value)
+	}
+	_, err := mgr.DB.ExecContext(ctx, fmt.Sprintf("set persist %s = %s", name, parameterValue(value)))
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case errUnknownSystemVariable:
+			return models.ErrUnknownParameter
+		case errReadOnlyVariable:
+			if err = mgr.persistOnly(ctx, name, value); err != nil {
+				return err
+			}
+			return models.ErrRestartRequired
+		}
+	}
+	return err
+}
+
+// persistOnly writes the variable to mysqld-auto.cnf without setting it, a
+// variable which can't be persisted is read-only.
+func (mgr *Manager) persistOnly(ctx context.Context, name, value string) error {
+	_, err := mgr.DB.ExecContext(ctx, fmt.Sprintf("set persist_only %s = %s", name, parameterValue(value)))
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errReadOnlyVariable {
+		return models.ErrReadOnlyParameter
+	}
+	if err != nil {
+		return errors.Wrapf(err, "persist %s failed", name)
+	}
+	return nil
+}
+
+// parameterValue quotes the value unless it's a number, MySQL rejects a quoted
+// value of a numeric variable.
+func parameterValue(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + quote(value) + "'"
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestReconfigure(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock, _ := mockDatabase(t)
+
+	t.Run("classify", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(parameterExistsSQL)).WithArgs("max_connections").
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+		kind, err := manager.ClassifyParameter(ctx, "max-connections")
+		assert.Nil(t, err)
+		assert.Equal(t, models.ParameterDynamic, kind)
+
+		mock.ExpectQuery(regexp.QuoteMeta(parameterExistsSQL)).WithArgs("innodb_page_size").
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+		kind, err = manager.ClassifyParameter(ctx, "innodb_page_size")
+		assert.Nil(t, err)
+		assert.Equal(t, models.ParameterStatic, kind)
+
+		mock.ExpectQuery(regexp.QuoteMeta(parameterExistsSQL)).WithArgs("foo").
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+		_, err = manager.ClassifyParameter(ctx, "foo")
+		assert.ErrorIs(t, err, models.ErrUnknownParameter)
+
+		_, err = manager.ClassifyParameter(ctx, "foo = 1; drop table t")
+		assert.ErrorIs(t, err, models.ErrUnknownParameter)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("set persist max_connections = 200")).WillReturnResult(sqlmock.NewResult(0, 0))
+		assert.Nil(t, manager.SetParameter(ctx, "max_connections", "200"))
+
+		mock.ExpectExec(regexp.QuoteMeta("set persist sql_mode = 'ANSI_QUOTES'")).WillReturnResult(sqlmock.NewResult(0, 0))
+		assert.Nil(t, manager.SetParameter(ctx, "sql_mode", "ANSI_QUOTES"))
+
+		// the static variable is persisted for the next start
+		mock.ExpectExec(regexp.QuoteMeta("set persist_only innodb_log_file_size = 1073741824")).WillReturnResult(sqlmock.NewResult(0, 0))
+		assert.Nil(t, manager.SetParameter(ctx, "innodb_log_file_size", "1073741824"))
+
+		// found static when it's set
+		mock.ExpectExec(regexp.QuoteMeta("set persist innodb_ft_cache_size = 16777216")).
+			WillReturnError(&mysql.MySQLError{Number: errReadOnlyVariable})
+		mock.ExpectExec(regexp.QuoteMeta("set persist_only innodb_ft_cache_size = 16777216")).WillReturnResult(sqlmock.NewResult(0, 0))
+		assert.ErrorIs(t, manager.SetParameter(ctx, "innodb_ft_cache_size", "16777216"), models.ErrRestartRequired)
+
+		mock.ExpectExec(regexp.QuoteMeta("set persist version = 'abc'")).
+			WillReturnError(&mysql.MySQLError{Number: errReadOnlyVariable})
+		mock.ExpectExec(regexp.QuoteMeta("set persist_only version = 'abc'")).
+			WillReturnError(&mysql.MySQLError{Number: errReadOnlyVariable})
+		assert.ErrorIs(t, manager.SetParameter(ctx, "version", "abc"), models.ErrReadOnlyParameter)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// replicaColumns are the columns of SHOW REPLICA STATUS, and slaveColumns are
+// those of SHOW SLAVE STATUS, which is used before 8.0.26, see UseSourceReplica.
+var (
+	replicaColumns = replicationColumns{
+		host:           "Source_Host",
+		port:           "Source_Port",
+		ioRunning:      "Replica_IO_Running",
+		sqlRunning:     "Replica_SQL_Running",
+		secondsBehind:  "Seconds_Behind_Source",
+		logFile:        "Source_Log_File",
+		readLogPos:     "Read_Source_Log_Pos",
+		relayLogFile:   "Relay_Source_Log_File",
+		execLogPos:     "Exec_Source_Log_Pos",
+		lastIOError:    "Last_IO_Error",
+		lastSQLError:   "Last_SQL_Error",
+		retrievedGtids: "Retrieved_Gtid_Set",
+		executedGtids:  "Executed_Gtid_Set",
+	}
+	slaveColumns = replicationColumns{
+		host:           "Master_Host",
+		port:           "Master_Port",
+		ioRunning:      "Slave_IO_Running",
+		sqlRunning:     "Slave_SQL_Running",
+		secondsBehind:  "Seconds_Behind_Master",
+		logFile:        "Master_Log_File",
+		readLogPos:     "Read_Master_Log_Pos",
+		relayLogFile:   "Relay_Master_Log_File",
+		execLogPos:     "Exec_Master_Log_Pos",
+		lastIOError:    "Last_IO_Error",
+		lastSQLError:   "Last_SQL_Error",
+		retrievedGtids: "Retrieved_Gtid_Set",
+		executedGtids:  "Executed_Gtid_Set",
+	}
+)
+
+type replicationColumns struct {
+	host           string
+	port           string
+	ioRunning      string
+	sqlRunning     string
+	secondsBehind  string
+	logFile        string
+	readLogPos     string
+	relayLogFile   string
+	execLogPos     string
+	lastIOError    string
+	lastSQLError   string
+	retrievedGtids string
+	executedGtids  string
+}
+
+func (mgr *Manager) GetReplicationStatus(ctx context.Context) (*models.ReplicationStatus, error) {
+	useSourceReplica, err := mgr.UseSourceReplica(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rowMap, err := mgr.getSlaveStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.ReplicationStatus{}
+	if len(rowMap) == 0 {
+		var executedGtids string
+		err = mgr.DB.QueryRowContext(ctx, "select @@global.gtid_executed").Scan(&executedGtids)
+		if err != nil {
+			return nil, err
+		}
+		status.AppliedPosition = executedGtids
+		return status, nil
+	}
+
+	columns := slaveColumns
+	if useSourceReplica {
+		columns = replicaColumns
+	}
+	status.IsReplica = true
+	status.UpstreamHost = rowMap.GetString(columns.host)
+	status.UpstreamPort = rowMap.GetInt(columns.port)
+	status.IOThreadState = rowMap.GetString(columns.ioRunning)
+	status.SQLThreadState = rowMap.GetString(columns.sqlRunning)
+	// Seconds_Behind_Source is NULL while the SQL thread is not running
+	if secondsBehind := rowMap.GetNullInt64(columns.secondsBehind); secondsBehind.Valid {
+		status.LagSeconds = &secondsBehind.Int64
+	}
+	// the byte positions are comparable only in the same binlog file of the source
+	if rowMap.GetString(columns.logFile) == rowMap.GetString(columns.relayLogFile) {
+		lagBytes := rowMap.GetInt64(columns.readLogPos) - rowMap.GetInt64(columns.execLogPos)
+		status.LagBytes = &lagBytes
+	}
+	status.LastError = rowMap.GetString(columns.lastIOError)
+	if status.LastError == "" {
+		status.LastError = rowMap.GetString(columns.lastSQLError)
+	}
+
+	status.ReceivedPosition = rowMap.GetString(columns.retrievedGtids)
+	status.AppliedPosition = rowMap.GetString(columns.executedGtids)
+	if status.ReceivedPosition == "" && status.AppliedPosition == "" {
+		status.ReceivedPosition = fmt.Sprintf("%s:%s", rowMap.GetString(columns.logFile), rowMap.GetString(columns.readLogPos))
+		status.AppliedPosition = fmt.Sprintf("%s:%s", rowMap.GetString(columns.relayLogFile), rowMap.GetString(columns.execLogPos))
+	}
+	return status, nil
+}
+
+// getSlaveStatus reads the replication status, which is empty if the replication
+// is not configured. It's read for every request rather than kept in the manager,
+// which serves the requests concurrently.
+func (mgr *Manager) getSlaveStatus(ctx context.Context) (RowMap, error) {
+	useSourceReplica, err := mgr.UseSourceReplica(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sql := "show slave status"
+	if useSourceReplica {
+		sql = "show replica status"
+	}
+
+	rows, err := mgr.DB.QueryContext(ctx, sql)
+	if err != nil {
+		mgr.Logger.Info(sql+" failed", "error", err.Error())
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var rowMap RowMap
+	err = ScanRowsToMaps(rows, func(rMap RowMap) error {
+		rowMap = rMap
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rowMap, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+var replicaStatusColumns = []string{
+	"Source_Host", "Source_Port", "Replica_IO_Running", "Replica_SQL_Running", "Seconds_Behind_Source",
+	"Source_Log_File", "Read_Source_Log_Pos", "Relay_Source_Log_File", "Exec_Source_Log_Pos",
+	"Last_IO_Error", "Last_SQL_Error", "Retrieved_Gtid_Set", "Executed_Gtid_Set",
+}
+
+func TestGetReplicationStatus(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock, _ := mockDatabase(t)
+	manager.version = "8.0.33"
+
+	t.Run("replica", func(t *testing.T) {
+		mock.ExpectQuery("show replica status").WillReturnRows(sqlmock.NewRows(replicaStatusColumns).
+			AddRow("mysql-0", 3306, "Yes", "Yes", 2, "binlog.000003", 1200, "binlog.000003", 1000,
+				"", "", "uuid:1-12", "uuid:1-10"))
+
+		status, err := manager.GetReplicationStatus(ctx)
+		assert.Nil(t, err)
+		assert.True(t, status.IsReplica)
+		assert.Equal(t, "mysql-0", status.UpstreamHost)
+		assert.Equal(t, 3306, status.UpstreamPort)
+		assert.Equal(t, "Yes", status.IOThreadState)
+		assert.Equal(t, int64(2), *status.LagSeconds)
+		assert.Equal(t, int64(200), *status.LagBytes)
+		assert.Equal(t, "uuid:1-12", status.ReceivedPosition)
+		assert.Equal(t, "uuid:1-10", status.AppliedPosition)
+	})
+
+	t.Run("replica with stopped sql thread", func(t *testing.T) {
+		mock.ExpectQuery("show replica status").WillReturnRows(sqlmock.NewRows(replicaStatusColumns).
+			AddRow("mysql-0", 3306, "Yes", "No", nil, "binlog.000004", 100, "binlog.000003", 1000,
+				"", "Error 'Duplicate entry'", "", ""))
+
+		status, err := manager.GetReplicationStatus(ctx)
+		assert.Nil(t, err)
+		assert.Nil(t, status.LagSeconds)
+		assert.Nil(t, status.LagBytes)
+		assert.Equal(t, "Error 'Duplicate entry'", status.LastError)
+		assert.Equal(t, "binlog.000004:100", status.ReceivedPosition)
+		assert.Equal(t, "binlog.000003:1000", status.AppliedPosition)
+	})
+
+	t.Run("primary", func(t *testing.T) {
+		mock.ExpectQuery("show replica status").WillReturnRows(sqlmock.NewRows(replicaStatusColumns))
+		mock.ExpectQuery("select @@global.gtid_executed").
+			WillReturnRows(sqlmock.NewRows([]string{"@@global.gtid_executed"}).AddRow("uuid:1-20"))
+
+		status, err := manager.GetReplicationStatus(ctx)
+		assert.Nil(t, err)
+		assert.False(t, status.IsReplica)
+		assert.Equal(t, "uuid:1-20", status.AppliedPosition)
+	})
+
+	t.Run("replica role from the replication status", func(t *testing.T) {
+		mock.ExpectQuery("show replica status").WillReturnRows(sqlmock.NewRows(replicaStatusColumns).
+			AddRow("mysql-0", 3306, "Yes", "Yes", 0, "binlog.000003", 1000, "binlog.000003", 1000,
+				"", "", "uuid:1-10", "uuid:1-10"))
+
+		role, err := manager.GetReplicaRole(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, constant.Secondary, role)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
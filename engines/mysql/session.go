@@ -0,0 +1,85 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// listSessionsSQL skips the threads of the server itself, e.g. the replication
+// applier of system user, and the binlog dump threads serving the replicas, which
+// are not to be killed.
+const listSessionsSQL = `select id, user, host, db, command, time, info from information_schema.processlist
+where id <> connection_id() and user not in ('system user', 'event_scheduler')
+and command not in ('Binlog Dump', 'Binlog Dump GTID')`
+
+var _ engines.SessionManager = &Manager{}
+
+func (mgr *Manager) ListSessions(ctx context.Context) ([]models.Session, error) {
+	rows, err := mgr.DB.QueryContext(ctx, listSessionsSQL)
+	if err != nil {
+		return nil, errors.Wrap(err, "query processlist failed")
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+	for rows.Next() {
+		var (
+			id              int64
+			user, host      string
+			db, info        sql.NullString
+			command         string
+			durationSeconds int64
+		)
+		if err := rows.Scan(&id, &user, &host, &db, &command, &durationSeconds, &info); err != nil {
+			return nil, errors.Wrap(err, "scan processlist failed")
+		}
+		sessions = append(sessions, models.Session{
+			ID:              strconv.FormatInt(id, 10),
+			User:            user,
+			Database:        db.String,
+			Client:          host,
+			State:           command,
+			DurationSeconds: float64(durationSeconds),
+			Query:           info.String,
+		})
+	}
+	return sessions, rows.Err()
+}
+
+func (mgr *Manager) KillSession(ctx context.Context, id string) error {
+	threadID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return errors.Errorf("invalid session id %s", id)
+	}
+	_, err = mgr.DB.ExecContext(ctx, fmt.Sprintf("kill %d", threadID))
+	if err != nil {
+		return errors.Wrapf(err, "kill %s failed", id)
+	}
+	return nil
+}
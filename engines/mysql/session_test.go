@@ -0,0 +1,65 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestListSessions(t *testing.T) {
+	manager, mock, _ := mockDatabase(t)
+
+	// the binlog dump threads of the replicas are not listed
+	mock.ExpectQuery(`select id, user, host, db, command, time, info from information_schema.processlist
+where .*
+and command not in \('Binlog Dump', 'Binlog Dump GTID'\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user", "host", "db", "command", "time", "info"}).
+			AddRow(12, "app", "10.0.0.3:51234", "orders", "Query", 95, "select sleep(100)").
+			AddRow(13, "app", "10.0.0.3:51236", nil, "Sleep", 7, nil))
+
+	sessions, err := manager.ListSessions(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, []models.Session{
+		{ID: "12", User: "app", Database: "orders", Client: "10.0.0.3:51234", State: "Query", DurationSeconds: 95, Query: "select sleep(100)"},
+		{ID: "13", User: "app", Client: "10.0.0.3:51236", State: "Sleep", DurationSeconds: 7},
+	}, sessions)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestKillSession(t *testing.T) {
+	manager, mock, _ := mockDatabase(t)
+
+	mock.ExpectExec("kill 12").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Nil(t, manager.KillSession(context.TODO(), "12"))
+	assert.NotNil(t, manager.KillSession(context.TODO(), "12; drop table t"))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const (
+	memberViewSQL = "select @@global.read_only, " +
+		"(select concat(HOST, ':', PORT) from performance_schema.replication_connection_configuration limit 1)"
+
+	fenceSQL = "set global super_read_only = on"
+)
+
+// GetMemberView connects to the member, MySQL replication has no terms. The member
+// is a replica if its replication source is configured.
+func (mgr *Manager) GetMemberView(ctx context.Context, address string) (*models.MemberView, error) {
+	db, err := mgr.MemberDB(address)
+	if err != nil {
+		return nil, err
+	}
+
+	var readonly bool
+	var source sql.NullString
+	if err = db.QueryRowContext(ctx, memberViewSQL).Scan(&readonly, &source); err != nil {
+		return nil, errors.Wrapf(err, "query member %s failed", address)
+	}
+
+	view := &models.MemberView{
+		Address:  address,
+		Role:     models.PRIMARY,
+		Writable: !readonly,
+	}
+	if source.Valid && source.String != "" {
+		view.Role = models.SECONDARY
+		view.Leader = source.String
+	}
+	return view, nil
+}
+
+// FenceMember sets super_read_only, which rejects the writes of all the users.
+func (mgr *Manager) FenceMember(ctx context.Context, address string) error {
+	db, err := mgr.MemberDB(address)
+	if err != nil {
+		return err
+	}
+	if _, err = db.ExecContext(ctx, fenceSQL); err != nil {
+		return errors.Wrapf(err, "fence member %s failed", address)
+	}
+	return nil
+}
+
+// MemberDB returns the connection pool of the member, the local port is used if
+// the address has no port.
+func (mgr *Manager) MemberDB(address string) (*sql.DB, error) {
+	if address == "" {
+		return mgr.DB, nil
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		port := mgr.config.Port
+		if port == "" {
+			port = strconv.Itoa(defaultDBPort)
+		}
+		address = net.JoinHostPort(address, port)
+	}
+	return mgr.config.GetDBConnWithAddr(address)
+}
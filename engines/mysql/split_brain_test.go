@@ -0,0 +1,67 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestGetMemberView(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock, _ := mockDatabase(t)
+	columns := []string{"@@global.read_only", "source"}
+
+	t.Run("primary", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(memberViewSQL)).WillReturnRows(sqlmock.NewRows(columns).AddRow(false, nil))
+
+		view, err := manager.GetMemberView(ctx, "")
+		assert.Nil(t, err)
+		assert.Equal(t, models.PRIMARY, view.Role)
+		assert.True(t, view.Writable)
+	})
+
+	t.Run("replica", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(memberViewSQL)).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(true, "mysql-0.mysql-headless:3306"))
+
+		view, err := manager.GetMemberView(ctx, "")
+		assert.Nil(t, err)
+		assert.Equal(t, models.SECONDARY, view.Role)
+		assert.False(t, view.Writable)
+		assert.Equal(t, "mysql-0.mysql-headless:3306", view.Leader)
+	})
+
+	t.Run("fence", func(t *testing.T) {
+		mock.ExpectExec(fenceSQL).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.Nil(t, manager.FenceMember(ctx, ""))
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
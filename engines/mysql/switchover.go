@@ -0,0 +1,112 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines"
+)
+
+var _ engines.Switcher = &Manager{}
+
+// Promote stops the replication and lifts the read-only flags, the replication
+// settings are reset so that the member is not reported as a replica any more.
+func (mgr *Manager) Promote(ctx context.Context) error {
+	useSourceReplica, err := mgr.UseSourceReplica(ctx)
+	if err != nil {
+		return err
+	}
+	stmts := []string{"stop slave", "reset slave all"}
+	if useSourceReplica {
+		stmts = []string{"stop replica", "reset replica all"}
+	}
+	stmts = append(stmts, "set global super_read_only = off", "set global read_only = off")
+	return mgr.execStatements(ctx, stmts)
+}
+
+// Demote fences the member and replicates from the leader with the replication
+// user, GTID auto positioning is required to switch the source.
+func (mgr *Manager) Demote(ctx context.Context, leader string) error {
+	useSourceReplica, err := mgr.UseSourceReplica(ctx)
+	if err != nil {
+		return err
+	}
+	host, port, err := mgr.leaderHostPort(leader)
+	if err != nil {
+		return err
+	}
+
+	mgr.config.credentialLock.RLock()
+	user, password := mgr.config.ReplicationUsername, mgr.config.ReplicationPassword
+	mgr.config.credentialLock.RUnlock()
+
+	stmts := []string{fenceSQL}
+	if useSourceReplica {
+		stmts = append(stmts, "stop replica",
+			fmt.Sprintf("change replication source to source_host = '%s', source_port = %d, source_user = '%s', source_password = '%s', source_auto_position = 1",
+				quote(host), port, quote(user), quote(password)),
+			"start replica")
+	} else {
+		stmts = append(stmts, "stop slave",
+			fmt.Sprintf("change master to master_host = '%s', master_port = %d, master_user = '%s', master_password = '%s', master_auto_position = 1",
+				quote(host), port, quote(user), quote(password)),
+			"start slave")
+	}
+	return mgr.execStatements(ctx, stmts)
+}
+
+func (mgr *Manager) execStatements(ctx context.Context, stmts []string) error {
+	for _, stmt := range stmts {
+		if _, err := mgr.DB.ExecContext(ctx, stmt); err != nil {
+			// the options of the change statement are dropped for the password
+			name, _, _ := strings.Cut(stmt, " to ")
+			return errors.Wrapf(err, "%s failed", name)
+		}
+	}
+	return nil
+}
+
+// leaderHostPort splits the address of the leader, the local port is used if
+// the address has no port.
+func (mgr *Manager) leaderHostPort(leader string) (string, int, error) {
+	host, port, err := net.SplitHostPort(leader)
+	if err != nil {
+		host, port = leader, mgr.config.Port
+	}
+	if port == "" {
+		return host, defaultDBPort, nil
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "invalid port of leader %s", leader)
+	}
+	return host, p, nil
+}
+
+func quote(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+}
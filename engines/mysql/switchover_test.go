@@ -0,0 +1,77 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwitchover(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock, _ := mockDatabase(t)
+	manager.config = &Config{
+		Port:                "3306",
+		ReplicationUsername: "repl",
+		ReplicationPassword: "it's",
+	}
+
+	t.Run("promote", func(t *testing.T) {
+		manager.version = "8.0.33"
+		mock.ExpectExec("stop replica").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("reset replica all").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("set global super_read_only = off").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("set global read_only = off").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.Nil(t, manager.Promote(ctx))
+	})
+
+	t.Run("demote", func(t *testing.T) {
+		manager.version = "8.0.33"
+		mock.ExpectExec(fenceSQL).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("stop replica").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("change replication source to source_host = 'mysql-1', source_port = 3306, " +
+			`source_user = 'repl', source_password = 'it\'s', source_auto_position = 1`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("start replica").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.Nil(t, manager.Demote(ctx, "mysql-1"))
+	})
+
+	t.Run("demote before 8.0.26", func(t *testing.T) {
+		manager.version = "8.0.20"
+		mock.ExpectExec(fenceSQL).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("stop slave").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("change master to master_host = 'mysql-1', master_port = 3307")).
+			WillReturnError(assert.AnError)
+
+		err := manager.Demote(ctx, "mysql-1:3307")
+		assert.ErrorContains(t, err, "change master failed")
+		assert.NotContains(t, err.Error(), "it's")
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
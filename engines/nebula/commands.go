@@ -45,7 +45,7 @@ func NewCommands() engines.ClusterCommands {
 		examples: map[models.ClientType]engines.BuildConnectExample{
 			models.CLI: func(info *engines.ConnectionInfo) string {
 				return fmt.Sprintf(`# nebula client connection example
-nebula --addr %s --port %s --user %s -port%s
+nebula-console --addr %s --port %s --user %s --password %s
 `, info.Host, info.Port, info.User, info.Password)
 			},
 		},
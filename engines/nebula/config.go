@@ -0,0 +1,143 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package nebula
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/apecloud/dbctl/constant"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+const (
+	// configSection is the config file section of Nebula, the address is of graphd.
+	configSection = "nebula"
+
+	defaultGraphHost = "127.0.0.1"
+	defaultGraphPort = 9669
+	defaultUser      = "root"
+	defaultPassword  = "nebula"
+
+	defaultConnectTimeout = 3 * time.Second
+	defaultReadTimeout    = 10 * time.Second
+
+	// EnvService is the Nebula service of the local pod, one of graphd, metad and
+	// storaged, it's guessed from the component name if not set.
+	EnvService = "NEBULA_SERVICE"
+	// EnvHTTPPort is the port of the HTTP service of the local pod, which serves /status.
+	EnvHTTPPort = "NEBULA_HTTP_PORT"
+	// EnvGraphHost and EnvGraphPort are the graphd service, the same as nebula-console.
+	EnvGraphHost = "GRAPHD_SVC_NAME"
+	EnvGraphPort = "GRAPHD_SVC_PORT"
+)
+
+// the Nebula services.
+const (
+	Graphd   = "graphd"
+	Metad    = "metad"
+	Storaged = "storaged"
+)
+
+// defaultHTTPPorts are the default ws_http_port of the services.
+var defaultHTTPPorts = map[string]int{
+	Graphd:   19669,
+	Metad:    19559,
+	Storaged: 19779,
+}
+
+type Config struct {
+	// Service is the Nebula service of the local pod.
+	Service string
+	// GraphAddr is the graphd address to run nGQL, metad and storaged are reached
+	// by graphd too.
+	GraphAddr string
+	// HTTPAddr is the HTTP address of the local service.
+	HTTPAddr string
+
+	TLSConfig      *tls.Config
+	ConnectTimeout time.Duration
+	ReadTimeout    time.Duration
+
+	*utilconfig.EngineCredentials
+}
+
+var config *Config
+
+func NewConfig() (*Config, error) {
+	config = &Config{
+		EngineCredentials: utilconfig.NewEngineCredentials(configSection,
+			utilconfig.CredentialLookup{Default: defaultUser, Name: constant.ConfigKeyUserName, Envs: []string{constant.KBEnvServiceUser}},
+			utilconfig.CredentialLookup{Default: defaultPassword, Name: constant.ConfigKeyPassword, Envs: []string{constant.KBEnvServicePassword}}),
+		Service:        getService(),
+		ConnectTimeout: defaultConnectTimeout,
+		ReadTimeout:    defaultReadTimeout,
+	}
+
+	engineConfig, err := utilconfig.GetEngineConfig(configSection)
+	if err != nil {
+		return nil, err
+	}
+	host, port := defaultGraphHost, defaultGraphPort
+	if viper.IsSet(EnvGraphHost) {
+		host = viper.GetString(EnvGraphHost)
+	}
+	if viper.IsSet(EnvGraphPort) {
+		port = viper.GetInt(EnvGraphPort)
+	}
+	config.GraphAddr = engineConfig.Addr(host, port)
+
+	httpPort := defaultHTTPPorts[config.Service]
+	if viper.IsSet(EnvHTTPPort) {
+		httpPort = viper.GetInt(EnvHTTPPort)
+	}
+	config.HTTPAddr = fmt.Sprintf("127.0.0.1:%d", httpPort)
+
+	if config.TLSConfig, err = engineConfig.TLS.ClientConfig(); err != nil {
+		return nil, err
+	}
+	if engineConfig.ConnectTimeout != 0 {
+		config.ConnectTimeout = engineConfig.ConnectTimeout
+	}
+	if engineConfig.ReadTimeout != 0 {
+		config.ReadTimeout = engineConfig.ReadTimeout
+	}
+	config.LoadCredentials(engineConfig)
+	return config, nil
+}
+
+// getService returns the service of the local pod, the KubeBlocks components are
+// named like nebula-metad.
+func getService() string {
+	if viper.IsSet(EnvService) {
+		return strings.ToLower(viper.GetString(EnvService))
+	}
+	compName := constant.GetClusterCompName()
+	for _, service := range []string{Metad, Storaged} {
+		if strings.HasSuffix(compName, service) {
+			return service
+		}
+	}
+	return Graphd
+}
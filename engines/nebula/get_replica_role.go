@@ -0,0 +1,53 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package nebula
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// GetReplicaRole returns the raft role of the local metad, leader if it's the
+// leader by SHOW META LEADER, or follower. The statement is run by graphd, so the
+// role of metad can't be told while graphd is down. graphd is stateless and the
+// partitions of storaged are led by different members, so they have no role, see
+// GetTopology for the partition leaders of storaged.
+func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
+	if mgr.config.Service != Metad {
+		return "", models.ErrNotImplemented
+	}
+
+	metaLeader, err := mgr.getMetaLeader(ctx)
+	if err != nil {
+		return "", err
+	}
+	host, _, err := net.SplitHostPort(metaLeader)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid metad leader %s", metaLeader)
+	}
+	if mgr.isLocalHost(host) {
+		return models.LEADER, nil
+	}
+	return models.FOLLOWER, nil
+}
@@ -0,0 +1,207 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package nebula
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/pkg/errors"
+)
+
+// clientVersion is verified by graphd against its client white list before the
+// authentication, it's the version sent by nebula-go.
+const clientVersion = "3.0.0"
+
+// the error codes of graphd, see common.thrift of Nebula.
+const (
+	errorCodeSucceeded      = 0
+	errorCodeSessionInvalid = -1002
+	errorCodeSessionTimeout = -1003
+)
+
+// graphClient runs nGQL by executeJson of the GraphService, which returns the
+// result set in JSON, so the values of the data set are not decoded by dbctl. The
+// session is kept until the connection breaks or the session expires.
+type graphClient struct {
+	config *Config
+
+	lock      sync.Mutex
+	transport thrift.TTransport
+	client    *thrift.TStandardClient
+	sessionID int64
+}
+
+// graphResponse is the response of executeJson.
+type graphResponse struct {
+	Errors  []graphError  `json:"errors"`
+	Results []graphResult `json:"results"`
+}
+
+type graphError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type graphResult struct {
+	SpaceName string   `json:"spaceName"`
+	Columns   []string `json:"columns"`
+	Data      []struct {
+		Row []any `json:"row"`
+	} `json:"data"`
+}
+
+func (resp *graphResponse) err() *graphError {
+	for i := range resp.Errors {
+		if resp.Errors[i].Code != errorCodeSucceeded {
+			return &resp.Errors[i]
+		}
+	}
+	return nil
+}
+
+func (e *graphError) Error() string {
+	return e.Message
+}
+
+// execute runs the statement, it reconnects once if the session expired.
+func (c *graphClient) execute(ctx context.Context, stmt string) (*graphResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	resp, err := c.executeJSON(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	if graphErr := resp.err(); graphErr != nil {
+		if graphErr.Code != errorCodeSessionInvalid && graphErr.Code != errorCodeSessionTimeout {
+			return nil, errors.Wrapf(graphErr, "error executing %s", stmt)
+		}
+		c.close()
+		if resp, err = c.executeJSON(ctx, stmt); err != nil {
+			return nil, err
+		}
+		if graphErr = resp.err(); graphErr != nil {
+			return nil, errors.Wrapf(graphErr, "error executing %s", stmt)
+		}
+	}
+	return resp, nil
+}
+
+func (c *graphClient) executeJSON(ctx context.Context, stmt string) (*graphResponse, error) {
+	if c.client == nil {
+		if err := c.open(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &executeJSONResult{}
+	args := &executeJSONArgs{SessionID: c.sessionID, Stmt: []byte(stmt)}
+	if _, err := c.client.Call(ctx, "executeJson", args, result); err != nil {
+		c.close()
+		return nil, errors.Wrapf(err, "error executing %s", stmt)
+	}
+
+	// the numbers are kept as json.Number, e.g. the int64 vertex ids
+	resp := &graphResponse{}
+	decoder := json.NewDecoder(bytes.NewReader(result.Success))
+	decoder.UseNumber()
+	if err := decoder.Decode(resp); err != nil {
+		return nil, errors.Wrapf(err, "decode the result of %s failed", stmt)
+	}
+	return resp, nil
+}
+
+// open connects to graphd and authenticates, the connection is closed if it fails.
+func (c *graphClient) open(ctx context.Context) error {
+	conf := &thrift.TConfiguration{
+		ConnectTimeout:    c.config.ConnectTimeout,
+		SocketTimeout:     c.config.ReadTimeout,
+		TLSConfig:         c.config.TLSConfig,
+		THeaderProtocolID: thrift.THeaderProtocolIDPtrMust(thrift.THeaderProtocolCompact),
+	}
+	var socket thrift.TTransport
+	if conf.TLSConfig != nil {
+		socket = thrift.NewTSSLSocketConf(c.config.GraphAddr, conf)
+	} else {
+		socket = thrift.NewTSocketConf(c.config.GraphAddr, conf)
+	}
+	transport := thrift.NewTHeaderTransportConf(socket, conf)
+	if err := transport.Open(); err != nil {
+		return errors.Wrapf(err, "connect to graphd %s failed", c.config.GraphAddr)
+	}
+	protocol := thrift.NewTHeaderProtocolConf(transport, conf)
+	c.transport = transport
+	c.client = thrift.NewTStandardClient(protocol, protocol)
+
+	if err := c.authenticate(ctx); err != nil {
+		c.close()
+		return err
+	}
+	return nil
+}
+
+func (c *graphClient) authenticate(ctx context.Context) error {
+	verifyResult := &verifyClientVersionResult{}
+	verifyArgs := &verifyClientVersionArgs{Req: &verifyClientVersionReq{Version: []byte(clientVersion)}}
+	if _, err := c.client.Call(ctx, "verifyClientVersion", verifyArgs, verifyResult); err != nil {
+		return errors.Wrap(err, "verify client version failed")
+	}
+	if resp := verifyResult.Success; resp == nil || resp.ErrorCode != errorCodeSucceeded {
+		return errors.Errorf("verify client version failed: %s", verifyResult.Success.errorMsg())
+	}
+
+	username, password := c.config.GetCredentials()
+	authResult := &authenticateResult{}
+	authArgs := &authenticateArgs{Username: []byte(username), Password: []byte(password)}
+	if _, err := c.client.Call(ctx, "authenticate", authArgs, authResult); err != nil {
+		return errors.Wrap(err, "authenticate failed")
+	}
+	resp := authResult.Success
+	if resp == nil || resp.ErrorCode != errorCodeSucceeded || resp.SessionID == nil {
+		return errors.Errorf("authenticate failed: %s", resp.errorMsg())
+	}
+	c.sessionID = *resp.SessionID
+	return nil
+}
+
+// close signs out the session and closes the connection, the next statement
+// connects again, e.g. with the rotated credentials.
+func (c *graphClient) close() {
+	if c.client == nil {
+		return
+	}
+	if c.sessionID != 0 {
+		_, _ = c.client.Call(context.Background(), "signout", &signoutArgs{SessionID: c.sessionID}, nil)
+	}
+	_ = c.transport.Close()
+	c.transport = nil
+	c.client = nil
+	c.sessionID = 0
+}
+
+func (c *graphClient) Close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.close()
+}
@@ -0,0 +1,201 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package nebula
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+// fakeGraphd serves the GraphService methods used by dbctl, the results of
+// executeJson are keyed by the statements.
+type fakeGraphd struct {
+	lock        sync.Mutex
+	password    string
+	results     map[string]string
+	sessions    map[int64]bool
+	nextSession int64
+	signouts    int
+}
+
+func startFakeGraphd(t *testing.T, results map[string]string) (*fakeGraphd, string) {
+	graphd := &fakeGraphd{
+		password: defaultPassword,
+		results:  results,
+		sessions: map[int64]bool{},
+	}
+	socket, err := thrift.NewTServerSocket("127.0.0.1:0")
+	require.NoError(t, err)
+	conf := &thrift.TConfiguration{}
+	server := thrift.NewTSimpleServer4(graphd, socket,
+		thrift.NewTHeaderTransportFactoryConf(nil, conf), thrift.NewTHeaderProtocolFactoryConf(conf))
+	require.NoError(t, server.Listen())
+	go func() {
+		_ = server.Serve()
+	}()
+	t.Cleanup(func() {
+		_ = server.Stop()
+	})
+	return graphd, socket.Addr().String()
+}
+
+// expireSessions drops the sessions as graphd does after session_idle_timeout_secs.
+func (f *fakeGraphd) expireSessions() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.sessions = map[int64]bool{}
+}
+
+func (f *fakeGraphd) Process(ctx context.Context, in, out thrift.TProtocol) (bool, thrift.TException) {
+	name, _, seqID, err := in.ReadMessageBegin(ctx)
+	if err != nil {
+		return false, thrift.WrapTException(err)
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var result thrift.TStruct
+	switch name {
+	case "verifyClientVersion":
+		args := &verifyClientVersionArgs{}
+		err = args.Read(ctx, in)
+		resp := &verifyClientVersionResp{}
+		if args.Req == nil || string(args.Req.Version) != clientVersion {
+			resp.ErrorCode, resp.ErrorMsg = -1, []byte("client version is not accepted")
+		}
+		result = &verifyClientVersionResult{Success: resp}
+	case "authenticate":
+		args := &authenticateArgs{}
+		err = args.Read(ctx, in)
+		resp := &authResponse{}
+		if string(args.Username) != defaultUser || string(args.Password) != f.password {
+			resp.ErrorCode, resp.ErrorMsg = -1001, []byte("Invalid password")
+		} else {
+			f.nextSession++
+			f.sessions[f.nextSession] = true
+			sessionID := f.nextSession
+			resp.SessionID = &sessionID
+		}
+		result = &authenticateResult{Success: resp}
+	case "executeJson":
+		args := &executeJSONArgs{}
+		err = args.Read(ctx, in)
+		resp, ok := f.results[string(args.Stmt)]
+		switch {
+		case !f.sessions[args.SessionID]:
+			resp = `{"errors":[{"code":-1002,"message":"Session not existed!"}]}`
+		case !ok:
+			resp = `{"errors":[{"code":-1004,"message":"SyntaxError: syntax error near ` + "`" + string(args.Stmt) + "`" + `"}]}`
+		}
+		result = &executeJSONResult{Success: []byte(resp)}
+	case "signout":
+		args := &signoutArgs{}
+		err = args.Read(ctx, in)
+		delete(f.sessions, args.SessionID)
+		f.signouts++
+	default:
+		err = in.Skip(ctx, thrift.STRUCT)
+	}
+	if err != nil {
+		return false, thrift.WrapTException(err)
+	}
+	if err = in.ReadMessageEnd(ctx); err != nil {
+		return false, thrift.WrapTException(err)
+	}
+	// signout is oneway
+	if result == nil {
+		return true, nil
+	}
+
+	if err = out.WriteMessageBegin(ctx, name, thrift.REPLY, seqID); err != nil {
+		return false, thrift.WrapTException(err)
+	}
+	if err = result.Write(ctx, out); err != nil {
+		return false, thrift.WrapTException(err)
+	}
+	if err = out.WriteMessageEnd(ctx); err != nil {
+		return false, thrift.WrapTException(err)
+	}
+	return true, thrift.WrapTException(out.Flush(ctx))
+}
+
+func (f *fakeGraphd) ProcessorMap() map[string]thrift.TProcessorFunction {
+	return nil
+}
+
+func (f *fakeGraphd) AddToProcessorMap(string, thrift.TProcessorFunction) {}
+
+func newTestConfig(service, graphAddr string) *Config {
+	return &Config{
+		Service:           service,
+		GraphAddr:         graphAddr,
+		ConnectTimeout:    defaultConnectTimeout,
+		ReadTimeout:       defaultReadTimeout,
+		EngineCredentials: utilconfig.NewEngineCredentials(configSection, utilconfig.CredentialLookup{Default: defaultUser}, utilconfig.CredentialLookup{Default: defaultPassword}),
+	}
+}
+
+func TestGraphClientExecute(t *testing.T) {
+	ctx := context.TODO()
+	graphd, addr := startFakeGraphd(t, map[string]string{
+		"YIELD 1 AS one": `{"errors":[{"code":0}],"results":[{"columns":["one"],"data":[{"row":[1],"meta":[null]}]}]}`,
+	})
+	client := &graphClient{config: newTestConfig(Graphd, addr)}
+	defer client.Close()
+
+	resp, err := client.execute(ctx, "YIELD 1 AS one")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one"}, resp.Results[0].Columns)
+	assert.Equal(t, int64(1), client.sessionID)
+
+	_, err = client.execute(ctx, "YIELD")
+	assert.ErrorContains(t, err, "SyntaxError")
+
+	// the expired session is replaced
+	graphd.expireSessions()
+	_, err = client.execute(ctx, "YIELD 1 AS one")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), client.sessionID)
+
+	// the rotated password is used once the session is closed
+	graphd.lock.Lock()
+	graphd.password = "rotated"
+	graphd.lock.Unlock()
+	client.Close()
+	// signout is oneway, the second one may not be served yet
+	assert.Eventually(t, func() bool {
+		graphd.lock.Lock()
+		defer graphd.lock.Unlock()
+		return graphd.signouts == 2
+	}, time.Second, 10*time.Millisecond)
+	_, err = client.execute(ctx, "YIELD 1 AS one")
+	assert.ErrorContains(t, err, "Invalid password")
+	client.config.SetCredentials(defaultUser, "rotated")
+	_, err = client.execute(ctx, "YIELD 1 AS one")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,298 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package nebula
+
+import (
+	"context"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// The thrift structs of the GraphService methods used by dbctl, see graph.thrift
+// of Nebula. They are written by hand for the few methods, the unknown fields
+// are skipped when read, so they work with the later versions of graphd.
+
+type verifyClientVersionReq struct {
+	Version []byte
+}
+
+type verifyClientVersionResp struct {
+	ErrorCode int32
+	ErrorMsg  []byte
+}
+
+type verifyClientVersionArgs struct {
+	Req *verifyClientVersionReq
+}
+
+type verifyClientVersionResult struct {
+	Success *verifyClientVersionResp
+}
+
+type authenticateArgs struct {
+	Username []byte
+	Password []byte
+}
+
+type authResponse struct {
+	ErrorCode int32
+	ErrorMsg  []byte
+	SessionID *int64
+}
+
+type authenticateResult struct {
+	Success *authResponse
+}
+
+type executeJSONArgs struct {
+	SessionID int64
+	Stmt      []byte
+}
+
+type executeJSONResult struct {
+	Success []byte
+}
+
+type signoutArgs struct {
+	SessionID int64
+}
+
+func (p *verifyClientVersionResp) errorMsg() string {
+	if p == nil {
+		return "no response"
+	}
+	return string(p.ErrorMsg)
+}
+
+func (p *authResponse) errorMsg() string {
+	if p == nil {
+		return "no response"
+	}
+	return string(p.ErrorMsg)
+}
+
+func (p *verifyClientVersionReq) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	return readStruct(ctx, iprot, fieldReaders{
+		1: {thrift.STRING, func() (err error) { p.Version, err = iprot.ReadBinary(ctx); return }},
+	})
+}
+
+func (p *verifyClientVersionReq) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	return writeStruct(ctx, oprot, "VerifyClientVersionReq",
+		writeBinaryField(ctx, oprot, "version", 1, p.Version))
+}
+
+func (p *verifyClientVersionResp) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	return readStruct(ctx, iprot, fieldReaders{
+		1: {thrift.I32, func() (err error) { p.ErrorCode, err = iprot.ReadI32(ctx); return }},
+		2: {thrift.STRING, func() (err error) { p.ErrorMsg, err = iprot.ReadBinary(ctx); return }},
+	})
+}
+
+func (p *verifyClientVersionResp) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	return writeStruct(ctx, oprot, "VerifyClientVersionResp",
+		writeI32Field(ctx, oprot, "error_code", 1, p.ErrorCode),
+		writeBinaryField(ctx, oprot, "error_msg", 2, p.ErrorMsg))
+}
+
+func (p *verifyClientVersionArgs) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	return readStruct(ctx, iprot, fieldReaders{
+		1: {thrift.STRUCT, func() error { p.Req = &verifyClientVersionReq{}; return p.Req.Read(ctx, iprot) }},
+	})
+}
+
+func (p *verifyClientVersionArgs) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	return writeStruct(ctx, oprot, "verifyClientVersion_args",
+		writeStructField(ctx, oprot, "req", 1, p.Req))
+}
+
+func (p *verifyClientVersionResult) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	return readStruct(ctx, iprot, fieldReaders{
+		0: {thrift.STRUCT, func() error { p.Success = &verifyClientVersionResp{}; return p.Success.Read(ctx, iprot) }},
+	})
+}
+
+func (p *verifyClientVersionResult) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	return writeStruct(ctx, oprot, "verifyClientVersion_result",
+		writeStructField(ctx, oprot, "success", 0, p.Success))
+}
+
+func (p *authenticateArgs) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	return readStruct(ctx, iprot, fieldReaders{
+		1: {thrift.STRING, func() (err error) { p.Username, err = iprot.ReadBinary(ctx); return }},
+		2: {thrift.STRING, func() (err error) { p.Password, err = iprot.ReadBinary(ctx); return }},
+	})
+}
+
+func (p *authenticateArgs) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	return writeStruct(ctx, oprot, "authenticate_args",
+		writeBinaryField(ctx, oprot, "username", 1, p.Username),
+		writeBinaryField(ctx, oprot, "password", 2, p.Password))
+}
+
+func (p *authResponse) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	return readStruct(ctx, iprot, fieldReaders{
+		1: {thrift.I32, func() (err error) { p.ErrorCode, err = iprot.ReadI32(ctx); return }},
+		2: {thrift.STRING, func() (err error) { p.ErrorMsg, err = iprot.ReadBinary(ctx); return }},
+		3: {thrift.I64, func() error {
+			sessionID, err := iprot.ReadI64(ctx)
+			p.SessionID = &sessionID
+			return err
+		}},
+	})
+}
+
+func (p *authResponse) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	fields := []func() error{
+		writeI32Field(ctx, oprot, "error_code", 1, p.ErrorCode),
+		writeBinaryField(ctx, oprot, "error_msg", 2, p.ErrorMsg),
+	}
+	if p.SessionID != nil {
+		fields = append(fields, writeI64Field(ctx, oprot, "session_id", 3, *p.SessionID))
+	}
+	return writeStruct(ctx, oprot, "AuthResponse", fields...)
+}
+
+func (p *authenticateResult) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	return readStruct(ctx, iprot, fieldReaders{
+		0: {thrift.STRUCT, func() error { p.Success = &authResponse{}; return p.Success.Read(ctx, iprot) }},
+	})
+}
+
+func (p *authenticateResult) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	return writeStruct(ctx, oprot, "authenticate_result",
+		writeStructField(ctx, oprot, "success", 0, p.Success))
+}
+
+func (p *executeJSONArgs) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	return readStruct(ctx, iprot, fieldReaders{
+		1: {thrift.I64, func() (err error) { p.SessionID, err = iprot.ReadI64(ctx); return }},
+		2: {thrift.STRING, func() (err error) { p.Stmt, err = iprot.ReadBinary(ctx); return }},
+	})
+}
+
+func (p *executeJSONArgs) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	return writeStruct(ctx, oprot, "executeJson_args",
+		writeI64Field(ctx, oprot, "sessionId", 1, p.SessionID),
+		writeBinaryField(ctx, oprot, "stmt", 2, p.Stmt))
+}
+
+func (p *executeJSONResult) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	return readStruct(ctx, iprot, fieldReaders{
+		0: {thrift.STRING, func() (err error) { p.Success, err = iprot.ReadBinary(ctx); return }},
+	})
+}
+
+func (p *executeJSONResult) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	return writeStruct(ctx, oprot, "executeJson_result",
+		writeBinaryField(ctx, oprot, "success", 0, p.Success))
+}
+
+func (p *signoutArgs) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	return readStruct(ctx, iprot, fieldReaders{
+		1: {thrift.I64, func() (err error) { p.SessionID, err = iprot.ReadI64(ctx); return }},
+	})
+}
+
+func (p *signoutArgs) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	return writeStruct(ctx, oprot, "signout_args",
+		writeI64Field(ctx, oprot, "sessionId", 1, p.SessionID))
+}
+
+type fieldReader struct {
+	fieldType thrift.TType
+	read      func() error
+}
+
+// fieldReaders are the readers of the known fields by their ids.
+type fieldReaders map[int16]fieldReader
+
+func readStruct(ctx context.Context, iprot thrift.TProtocol, readers fieldReaders) error {
+	if _, err := iprot.ReadStructBegin(ctx); err != nil {
+		return err
+	}
+	for {
+		_, fieldType, id, err := iprot.ReadFieldBegin(ctx)
+		if err != nil {
+			return err
+		}
+		if fieldType == thrift.STOP {
+			break
+		}
+		if reader, ok := readers[id]; ok && reader.fieldType == fieldType {
+			err = reader.read()
+		} else {
+			err = iprot.Skip(ctx, fieldType)
+		}
+		if err != nil {
+			return err
+		}
+		if err = iprot.ReadFieldEnd(ctx); err != nil {
+			return err
+		}
+	}
+	return iprot.ReadStructEnd(ctx)
+}
+
+func writeStruct(ctx context.Context, oprot thrift.TProtocol, name string, fields ...func() error) error {
+	if err := oprot.WriteStructBegin(ctx, name); err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if err := field(); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(ctx); err != nil {
+		return err
+	}
+	return oprot.WriteStructEnd(ctx)
+}
+
+func writeField(ctx context.Context, oprot thrift.TProtocol, name string, fieldType thrift.TType, id int16, write func() error) func() error {
+	return func() error {
+		if err := oprot.WriteFieldBegin(ctx, name, fieldType, id); err != nil {
+			return err
+		}
+		if err := write(); err != nil {
+			return err
+		}
+		return oprot.WriteFieldEnd(ctx)
+	}
+}
+
+func writeBinaryField(ctx context.Context, oprot thrift.TProtocol, name string, id int16, value []byte) func() error {
+	if value == nil {
+		return func() error { return nil }
+	}
+	return writeField(ctx, oprot, name, thrift.STRING, id, func() error { return oprot.WriteBinary(ctx, value) })
+}
+
+func writeI32Field(ctx context.Context, oprot thrift.TProtocol, name string, id int16, value int32) func() error {
+	return writeField(ctx, oprot, name, thrift.I32, id, func() error { return oprot.WriteI32(ctx, value) })
+}
+
+func writeI64Field(ctx context.Context, oprot thrift.TProtocol, name string, id int16, value int64) func() error {
+	return writeField(ctx, oprot, name, thrift.I64, id, func() error { return oprot.WriteI64(ctx, value) })
+}
+
+func writeStructField(ctx context.Context, oprot thrift.TProtocol, name string, id int16, value thrift.TStruct) func() error {
+	return writeField(ctx, oprot, name, thrift.STRUCT, id, func() error { return value.Write(ctx, oprot) })
+}
@@ -0,0 +1,137 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package nebula
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+const statusTimeout = 500 * time.Millisecond
+
+// Manager runs nGQL by graphd, the roles of metad and the partitions of storaged
+// are reported by graphd too, while the readiness is from the HTTP service of the
+// local pod.
+type Manager struct {
+	engines.DBManagerBase
+	config     *Config
+	graph      *graphClient
+	httpClient *http.Client
+}
+
+var _ engines.DBManager = &Manager{}
+
+func NewManager() (engines.DBManager, error) {
+	logger := ctrl.Log.WithName("Nebula")
+	config, err := NewConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	managerBase, err := engines.NewDBManagerBase(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := &Manager{
+		DBManagerBase: *managerBase,
+		config:        config,
+		graph:         &graphClient{config: config},
+		httpClient:    &http.Client{Timeout: statusTimeout},
+	}
+
+	utilconfig.OnCredentialsChange(mgr.reloadCredentials)
+	return mgr, nil
+}
+
+// reloadCredentials signs out the session after the credentials are reloaded, the
+// next statement authenticates with the rotated credentials.
+func (mgr *Manager) reloadCredentials() {
+	if err := mgr.config.ReloadCredentials(); err != nil {
+		mgr.Logger.Info("reload credentials failed", "error", err.Error())
+		return
+	}
+	mgr.graph.Close()
+	mgr.Logger.Info("credentials reloaded")
+}
+
+// serviceStatus is the response of /status of the Nebula services.
+type serviceStatus struct {
+	GitInfoSHA string `json:"git_info_sha"`
+	Status     string `json:"status"`
+}
+
+// IsDBStartupReady checks /status of the local service, which is running once the
+// service is started, e.g. storaged has registered to metad.
+func (mgr *Manager) IsDBStartupReady() bool {
+	if mgr.DBStartupReady {
+		return true
+	}
+
+	status, err := mgr.getServiceStatus(context.Background())
+	if err != nil {
+		mgr.Logger.Info("DB is not ready", "error", err.Error())
+		return false
+	}
+	if status.Status != "running" {
+		mgr.Logger.Info("DB is not ready", "status", status.Status)
+		return false
+	}
+
+	mgr.DBStartupReady = true
+	mgr.Logger.Info("DB startup ready")
+	return true
+}
+
+func (mgr *Manager) getServiceStatus(ctx context.Context) (*serviceStatus, error) {
+	url := fmt.Sprintf("http://%s/status", mgr.config.HTTPAddr)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := mgr.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get %s status %s", url, resp.Status)
+	}
+
+	status := &serviceStatus{}
+	if err = json.NewDecoder(resp.Body).Decode(status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (mgr *Manager) ShutDownWithWait() {
+	mgr.graph.Close()
+}
@@ -0,0 +1,172 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package nebula
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/conformance"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const (
+	showHostsMeta = `{"errors":[{"code":0}],"results":[{"columns":["Host","Port","Status","Role","Git Info Sha","Version"],"data":[
+		{"row":["nebula-metad-0.nebula-metad-headless.default.svc.cluster.local",9559,"ONLINE","META","2d4a2ba","3.6.0"]},
+		{"row":["nebula-metad-1.nebula-metad-headless.default.svc.cluster.local",9559,"ONLINE","META","2d4a2ba","3.6.0"]},
+		{"row":["nebula-metad-2.nebula-metad-headless.default.svc.cluster.local",9559,"OFFLINE","META","2d4a2ba","3.6.0"]}]}]}`
+	showMetaLeaderResult = `{"errors":[{"code":0}],"results":[{"columns":["Meta Leader","secs from last heart beat"],"data":[
+		{"row":["nebula-metad-0.nebula-metad-headless.default.svc.cluster.local:9559",3]}]}]}`
+	showHostsStorage = `{"errors":[{"code":0}],"results":[{"columns":["Host","Port","Status","Leader count","Leader distribution","Partition distribution","Version"],"data":[
+		{"row":["nebula-storaged-0.nebula-storaged-headless",9779,"ONLINE",8,"basketballplayer:5, test:3","basketballplayer:10, test:10","3.6.0"]},
+		{"row":["nebula-storaged-1.nebula-storaged-headless",9779,"ONLINE",0,"No valid partition","No valid partition","3.6.0"]}]}]}`
+	goVertex = `{"errors":[{"code":0}],"results":[{"columns":["id","v"],"data":[
+		{"row":[9007199254740993,{"player.name":"Tim Duncan","player.age":42}]}]}]}`
+)
+
+func mockManager(t *testing.T, service, memberName string) *Manager {
+	_, addr := startFakeGraphd(t, map[string]string{
+		"SHOW HOSTS META":  showHostsMeta,
+		"SHOW META LEADER": showMetaLeaderResult,
+		"SHOW HOSTS":       showHostsStorage,
+		`GO FROM 9007199254740993 OVER follow YIELD id($$) AS id, $$ AS v`: goVertex,
+		`INSERT VERTEX player(name, age) VALUES 100:("Tony Parker", 36)`:   `{"errors":[{"code":0}],"results":[{}]}`,
+	})
+	mgr := &Manager{
+		DBManagerBase: engines.DBManagerBase{
+			CurrentMemberName: memberName,
+			Logger:            ctrl.Log.WithName("Nebula-TEST"),
+		},
+		config:     newTestConfig(service, addr),
+		httpClient: http.DefaultClient,
+	}
+	mgr.graph = &graphClient{config: mgr.config}
+	t.Cleanup(mgr.ShutDownWithWait)
+	return mgr
+}
+
+func TestQueryAndExec(t *testing.T) {
+	ctx := context.TODO()
+	mgr := mockManager(t, Graphd, "nebula-graphd-0")
+
+	result, err := mgr.Query(ctx, `GO FROM 9007199254740993 OVER follow YIELD id($$) AS id, $$ AS v`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id":9007199254740993,"v":{"player.name":"Tim Duncan","player.age":42}}]`, string(result))
+	assert.Contains(t, string(result), "9007199254740993")
+
+	affected, err := mgr.Exec(ctx, `INSERT VERTEX player(name, age) VALUES 100:("Tony Parker", 36)`)
+	assert.NoError(t, err)
+	assert.Zero(t, affected)
+
+	_, err = mgr.Exec(ctx, "INSERT")
+	assert.ErrorContains(t, err, "SyntaxError")
+}
+
+func TestGetReplicaRole(t *testing.T) {
+	ctx := context.TODO()
+
+	role, err := mockManager(t, Metad, "nebula-metad-0").GetReplicaRole(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, models.LEADER, role)
+
+	role, err = mockManager(t, Metad, "nebula-metad-1").GetReplicaRole(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, models.FOLLOWER, role)
+
+	_, err = mockManager(t, Storaged, "nebula-storaged-0").GetReplicaRole(ctx)
+	assert.ErrorIs(t, err, models.ErrNotImplemented)
+}
+
+func TestGetTopology(t *testing.T) {
+	ctx := context.TODO()
+
+	topology, err := mockManager(t, Metad, "nebula-metad-1").GetTopology(ctx)
+	require.NoError(t, err)
+	require.Len(t, topology.Members, 3)
+	assert.Equal(t, models.Member{
+		Name:       "nebula-metad-0",
+		Address:    "nebula-metad-0.nebula-metad-headless.default.svc.cluster.local:9559",
+		Role:       models.LEADER,
+		NativeRole: models.LEADER,
+		Health:     models.HealthHealthy,
+	}, topology.Members[0])
+	assert.True(t, topology.Members[1].Self)
+	// the Role column is the host type, the roles are by SHOW META LEADER
+	assert.Equal(t, models.FOLLOWER, topology.Members[1].Role)
+	assert.Equal(t, models.FOLLOWER, topology.Members[2].Role)
+	assert.Equal(t, models.HealthUnhealthy, topology.Members[2].Health)
+
+	topology, err = mockManager(t, Storaged, "nebula-storaged-1").GetTopology(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Member{
+		{
+			Name:    "nebula-storaged-0",
+			Address: "nebula-storaged-0.nebula-storaged-headless:9779",
+			Health:  models.HealthHealthy,
+			Leaders: map[string]int64{"basketballplayer": 5, "test": 3},
+		},
+		{
+			Name:    "nebula-storaged-1",
+			Address: "nebula-storaged-1.nebula-storaged-headless:9779",
+			Health:  models.HealthHealthy,
+			Self:    true,
+			Leaders: map[string]int64{},
+		},
+	}, topology.Members)
+}
+
+func TestIsDBStartupReady(t *testing.T) {
+	status := `{"git_info_sha":"2d4a2ba","status":"starting"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/status", r.URL.Path)
+		_, _ = w.Write([]byte(status))
+	}))
+	defer server.Close()
+
+	mgr := mockManager(t, Storaged, "nebula-storaged-0")
+	mgr.config.HTTPAddr = strings.TrimPrefix(server.URL, "http://")
+	assert.False(t, mgr.IsDBStartupReady())
+
+	status = `{"git_info_sha":"2d4a2ba","status":"running"}`
+	assert.True(t, mgr.IsDBStartupReady())
+}
+
+func TestConformance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"git_info_sha":"2d4a2ba","status":"running"}`))
+	}))
+	defer server.Close()
+
+	mgr := mockManager(t, Metad, "nebula-metad-0")
+	mgr.config.HTTPAddr = strings.TrimPrefix(server.URL, "http://")
+	conformance.Run(t, conformance.Suite{
+		Manager:  mgr,
+		ExecSQL:  `INSERT VERTEX player(name, age) VALUES 100:("Tony Parker", 36)`,
+		QuerySQL: `GO FROM 9007199254740993 OVER follow YIELD id($$) AS id, $$ AS v`,
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package nebula
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Query returns the rows of the data set as the JSON objects keyed by the column
+// names, the values are the JSON of executeJson, e.g. a vertex is an object of its
+// properties.
+func (mgr *Manager) Query(ctx context.Context, ngql string) ([]byte, error) {
+	mgr.Logger.Info(fmt.Sprintf("query: %s", ngql))
+	rows, err := mgr.query(ctx, ngql)
+	if err != nil {
+		return nil, err
+	}
+	result, err := json.Marshal(rows)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error marshalling query result for %s", ngql)
+	}
+	return result, nil
+}
+
+// Exec runs the statement, nGQL doesn't report the affected rows, so it's always 0.
+func (mgr *Manager) Exec(ctx context.Context, ngql string) (int64, error) {
+	mgr.Logger.Info(fmt.Sprintf("exec: %s", ngql))
+	if _, err := mgr.graph.execute(ctx, ngql); err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+
+func (mgr *Manager) query(ctx context.Context, ngql string) ([]map[string]any, error) {
+	resp, err := mgr.graph.execute(ctx, ngql)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []map[string]any{}
+	for _, result := range resp.Results {
+		for _, data := range result.Data {
+			row := make(map[string]any, len(result.Columns))
+			for i, column := range result.Columns {
+				if i < len(data.Row) {
+					row[column] = data.Row[i]
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package nebula
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// showHostsStmts list the hosts of the services, SHOW HOSTS of storaged reports
+// the partition leaders.
+var showHostsStmts = map[string]string{
+	Graphd:   "SHOW HOSTS GRAPH",
+	Metad:    "SHOW HOSTS META",
+	Storaged: "SHOW HOSTS",
+}
+
+const (
+	noValidPartition = "No valid partition"
+	// showMetaLeader reports the address of the metad leader, the Role column of
+	// SHOW HOSTS META is only the host type META.
+	showMetaLeader = "SHOW META LEADER"
+)
+
+// GetTopology lists the hosts of the local service, metad members are the leader
+// by SHOW META LEADER or followers, and storaged members have the numbers of
+// partition leaders by the spaces.
+func (mgr *Manager) GetTopology(ctx context.Context) (*models.Topology, error) {
+	rows, err := mgr.query(ctx, showHostsStmts[mgr.config.Service])
+	if err != nil {
+		return nil, err
+	}
+	var metaLeader string
+	if mgr.config.Service == Metad {
+		if metaLeader, err = mgr.getMetaLeader(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	topology := &models.Topology{}
+	for _, row := range rows {
+		host := cast.ToString(row["Host"])
+		address := net.JoinHostPort(host, cast.ToString(row["Port"]))
+		member := models.Member{
+			NativeRole: cast.ToString(row["Role"]),
+		}
+		if mgr.config.Service == Metad {
+			role := models.FOLLOWER
+			if address == metaLeader {
+				role = models.LEADER
+			}
+			member = models.NewMember("", role)
+		}
+		member.Name, _, _ = strings.Cut(host, ".")
+		member.Address = address
+		member.Health = hostHealth(cast.ToString(row["Status"]))
+		member.Self = mgr.isLocalHost(host)
+		if mgr.config.Service == Storaged {
+			member.Leaders = parseLeaderDistribution(cast.ToString(row["Leader distribution"]))
+		}
+		topology.Members = append(topology.Members, member)
+	}
+	return topology, nil
+}
+
+// getMetaLeader returns the address of the metad leader, host:port as the hosts
+// of SHOW HOSTS META.
+func (mgr *Manager) getMetaLeader(ctx context.Context) (string, error) {
+	rows, err := mgr.query(ctx, showMetaLeader)
+	if err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", errors.New("no metad leader returned")
+	}
+	return cast.ToString(rows[0]["Meta Leader"]), nil
+}
+
+// isLocalHost tells whether the host is the local pod, the hosts are registered by
+// their FQDNs in KubeBlocks.
+func (mgr *Manager) isLocalHost(host string) bool {
+	return host == mgr.CurrentMemberName || strings.HasPrefix(host, mgr.CurrentMemberName+".")
+}
+
+func hostHealth(status string) string {
+	switch strings.ToUpper(status) {
+	case "ONLINE":
+		return models.HealthHealthy
+	case "OFFLINE":
+		return models.HealthUnhealthy
+	default:
+		return models.HealthUnknown
+	}
+}
+
+// parseLeaderDistribution parses the leader distribution of SHOW HOSTS, e.g.
+// "basketballplayer:5, test:3".
+func parseLeaderDistribution(distribution string) map[string]int64 {
+	leaders := map[string]int64{}
+	if distribution == noValidPartition {
+		return leaders
+	}
+	for _, item := range strings.Split(distribution, ",") {
+		index := strings.LastIndex(item, ":")
+		if index < 0 {
+			continue
+		}
+		space := strings.TrimSpace(item[:index])
+		leaders[space] = cast.ToInt64(strings.TrimSpace(item[index+1:]))
+	}
+	return leaders
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oceanbase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+var _ engines.ClusterCommands = &Commands{}
+
+type Commands struct {
+	info     engines.EngineInfo
+	examples map[models.ClientType]engines.BuildConnectExample
+}
+
+func NewCommands() engines.ClusterCommands {
+	return &Commands{
+		info: engines.EngineInfo{
+			Client:      "obclient",
+			PasswordEnv: "$MYSQL_ROOT_PASSWORD",
+			UserEnv:     "$MYSQL_ROOT_USER",
+			Database:    "oceanbase",
+		},
+		examples: map[models.ClientType]engines.BuildConnectExample{
+			models.CLI: func(info *engines.ConnectionInfo) string {
+				return fmt.Sprintf(`# obclient connection example, the user is in the form of user@tenant, e.g. root@sys
+obclient -h %s -P %s -u %s -p%s -A
+`, info.Host, info.Port, info.User, info.Password)
+			},
+
+			models.JAVA: func(info *engines.ConnectionInfo) string {
+				return fmt.Sprintf(`Class.forName("com.oceanbase.jdbc.Driver");
+Connection conn = DriverManager.getConnection(
+  "jdbc:oceanbase://%s:%s/%s",
+  "%s",
+  "%s");
+`, info.Host, info.Port, info.Database, info.User, info.Password)
+			},
+
+			models.PYTHON: func(info *engines.ConnectionInfo) string {
+				return fmt.Sprintf(`# run the following command in the terminal to install dependencies
+pip install pymysql
+
+# main.py
+import pymysql
+
+connection = pymysql.connect(
+  host="%s",
+  port=%s,
+  user="%s",
+  password="%s",
+  database="%s",
+)
+`, info.Host, info.Port, info.User, info.Password, info.Database)
+			},
+		},
+	}
+}
+
+func (m *Commands) ConnectCommand(connectInfo *engines.AuthInfo) []string {
+	userName := m.info.UserEnv
+	userPass := m.info.PasswordEnv
+
+	if connectInfo != nil {
+		userName = engines.AddSingleQuote(connectInfo.UserName)
+		userPass = engines.AddSingleQuote(connectInfo.UserPasswd)
+	}
+
+	// -A skips reading the table names for completion, which is slow on a tenant
+	// with many tables.
+	obclientCmd := []string{fmt.Sprintf("%s -h127.0.0.1 -P${KB_SERVICE_PORT:-2881} -u%s -p%s -A", m.info.Client, userName, userPass)}
+
+	return []string{"sh", "-c", strings.Join(obclientCmd, " ")}
+}
+
+func (m *Commands) Container() string {
+	return m.info.Container
+}
+
+func (m *Commands) ConnectExample(info *engines.ConnectionInfo, client string) string {
+	if len(info.Database) == 0 {
+		info.Database = m.info.Database
+	}
+	return engines.BuildExample(info, client, m.examples)
+}
+
+func (m *Commands) ExecuteCommand(scripts []string) ([]string, []corev1.EnvVar, error) {
+	var cmd []string
+	cmd = append(cmd, "/bin/sh", "-c", "-ex")
+	cmd = append(cmd, fmt.Sprintf("%s -h$%s -P$%s -u$%s -p$%s -e %s", m.info.Client,
+		engines.EnvVarMap[engines.HOST],
+		engines.EnvVarMap[engines.PORT],
+		engines.EnvVarMap[engines.USER],
+		engines.EnvVarMap[engines.PASSWORD],
+		strconv.Quote(strings.Join(scripts, " "))))
+	return cmd, nil, nil
+}
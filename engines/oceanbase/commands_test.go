@@ -0,0 +1,68 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oceanbase
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/apecloud/dbctl/engines"
+)
+
+var _ = Describe("OceanBase Engine", func() {
+	It("connection command", func() {
+		oceanbase := NewCommands()
+
+		Expect(oceanbase.ConnectCommand(nil)).ShouldNot(BeNil())
+		authInfo := &engines.AuthInfo{
+			UserName:   "root@sys",
+			UserPasswd: "pwd-test",
+		}
+		Expect(oceanbase.ConnectCommand(authInfo)[2]).Should(ContainSubstring("obclient -h127.0.0.1 -P${KB_SERVICE_PORT:-2881} -u'root@sys' -p'pwd-test'"))
+	})
+
+	It("connection example", func() {
+		oceanbase := NewCommands().(*Commands)
+
+		info := &engines.ConnectionInfo{
+			User:     "root@alice",
+			Host:     "host",
+			Password: "*****",
+			Port:     "2881",
+		}
+		for k := range oceanbase.examples {
+			fmt.Printf("%s Connection Example\n", k.String())
+			Expect(oceanbase.ConnectExample(info, k.String())).ShouldNot(BeEmpty())
+		}
+
+		Expect(oceanbase.ConnectExample(info, "")).ShouldNot(BeEmpty())
+	})
+
+	It("execute command", func() {
+		oceanbase := NewCommands()
+
+		cmd, envs, err := oceanbase.ExecuteCommand([]string{"select 1;"})
+		Expect(err).Should(Succeed())
+		Expect(envs).Should(BeEmpty())
+		Expect(cmd).Should(Equal([]string{"/bin/sh", "-c", "-ex", `obclient -h$KB_HOST -P$KB_PORT -u$KB_USER -p$KB_PASSWD -e "select 1;"`}))
+	})
+})
@@ -0,0 +1,49 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oceanbase
+
+import (
+	"github.com/spf13/viper"
+
+	"github.com/apecloud/dbctl/engines/mysql"
+)
+
+// EnvTenantName is the tenant whose role is the role of the replica, the first
+// user tenant is taken if it's not set.
+const EnvTenantName = "TENANT_NAME"
+
+type Config struct {
+	*mysql.Config
+	TenantName string
+}
+
+var config *Config
+
+func NewConfig() (*Config, error) {
+	mysqlConfig, err := mysql.NewConfig()
+	if err != nil {
+		return nil, err
+	}
+	config = &Config{
+		Config:     mysqlConfig,
+		TenantName: viper.GetString(EnvTenantName),
+	}
+	return config, nil
+}
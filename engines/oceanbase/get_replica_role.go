@@ -0,0 +1,155 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oceanbase
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const (
+	tenantSQL = "select tenant_id, tenant_name, tenant_role, status from oceanbase.DBA_OB_TENANTS " +
+		"where tenant_type = 'USER' and (? = '' or tenant_name = ?) order by tenant_id limit 1"
+
+	// localServerSQL joins V$OB_SERVERS, which has the local observer only.
+	localServerSQL = "select s.svr_ip, s.svr_port, s.zone, s.status, s.start_service_time is not null, z.status " +
+		"from oceanbase.DBA_OB_SERVERS s join oceanbase.V$OB_SERVERS l on s.svr_ip = l.svr_ip and s.svr_port = l.svr_port " +
+		"join oceanbase.DBA_OB_ZONES z on s.zone = z.zone"
+
+	// logStatSQL counts the log stream replicas of the tenant on the local observer,
+	// which are there only if a unit of the tenant is on it.
+	logStatSQL = "select count(*), coalesce(sum(in_sync = 'NO'), 0) from oceanbase.V$OB_LOG_STAT where tenant_id = ?"
+
+	tenantRolePrimary  = "PRIMARY"
+	tenantStatusNormal = "NORMAL"
+	statusActive       = "ACTIVE"
+)
+
+var _ engines.RoleInfoGetter = &Manager{}
+
+type tenant struct {
+	id     int64
+	name   string
+	role   string
+	status string
+}
+
+// server is an observer in a zone.
+type server struct {
+	ip         string
+	port       int
+	zone       string
+	status     string
+	started    bool
+	zoneStatus string
+}
+
+// health is unhealthy if the observer or its zone is inactive, e.g. stopped for an
+// upgrade, and recovering until the observer starts the service.
+func (s *server) health() string {
+	switch {
+	case s.status != statusActive || s.zoneStatus != statusActive:
+		return models.HealthUnhealthy
+	case !s.started:
+		return models.HealthRecovering
+	default:
+		return models.HealthHealthy
+	}
+}
+
+// GetReplicaRole returns the role of the tenant, the primary tenant or a standby
+// tenant of the physical standby cluster.
+func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
+	tenant, err := mgr.getTenant(ctx)
+	if err != nil {
+		return "", err
+	}
+	return tenantRole(tenant.role), nil
+}
+
+// GetReplicaRoleInfo is writable for a normal primary tenant on an active observer,
+// and recovering if a log stream replica of the tenant on the local observer is
+// out of sync. An observer without the units of the tenant takes the tenant role,
+// as it still serves the tenant by routing.
+func (mgr *Manager) GetReplicaRoleInfo(ctx context.Context) (*models.RoleInfo, error) {
+	tenant, err := mgr.getTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	server, err := mgr.getLocalServer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var replicas, outOfSync int64
+	if err = mgr.DB.QueryRowContext(ctx, logStatSQL, tenant.id).Scan(&replicas, &outOfSync); err != nil {
+		return nil, errors.Wrapf(err, "error executing %s", logStatSQL)
+	}
+
+	info := &models.RoleInfo{
+		Role:       tenantRole(tenant.role),
+		NativeRole: tenant.role,
+		Voter:      true,
+		Health:     server.health(),
+	}
+	if info.Health == models.HealthHealthy && (tenant.status != tenantStatusNormal || outOfSync > 0) {
+		info.Health = models.HealthRecovering
+	}
+	info.Writable = info.Role == models.PRIMARY && info.Health == models.HealthHealthy
+	return info, nil
+}
+
+func (mgr *Manager) getTenant(ctx context.Context) (*tenant, error) {
+	t := &tenant{}
+	err := mgr.DB.QueryRowContext(ctx, tenantSQL, mgr.tenantName, mgr.tenantName).Scan(&t.id, &t.name, &t.role, &t.status)
+	if errors.Is(err, sql.ErrNoRows) {
+		if mgr.tenantName != "" {
+			return nil, errors.Errorf("tenant %s not found", mgr.tenantName)
+		}
+		return nil, errors.New("no user tenant found")
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error executing %s", tenantSQL)
+	}
+	return t, nil
+}
+
+func (mgr *Manager) getLocalServer(ctx context.Context) (*server, error) {
+	s := &server{}
+	err := mgr.DB.QueryRowContext(ctx, localServerSQL).Scan(&s.ip, &s.port, &s.zone, &s.status, &s.started, &s.zoneStatus)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error executing %s", localServerSQL)
+	}
+	return s, nil
+}
+
+// tenantRole normalizes the tenant role, a tenant being restored or switched over
+// is not the primary.
+func tenantRole(role string) string {
+	if strings.EqualFold(role, tenantRolePrimary) {
+		return models.PRIMARY
+	}
+	return models.SECONDARY
+}
@@ -0,0 +1,137 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oceanbase
+
+import (
+	"context"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/mysql"
+)
+
+// Manager connects to the sys tenant of the local observer by the MySQL protocol,
+// e.g. as root@sys on port 2881.
+type Manager struct {
+	mysql.Manager
+	tenantName string
+}
+
+var _ engines.DBManager = &Manager{}
+
+func NewManager() (engines.DBManager, error) {
+	logger := ctrl.Log.WithName("OceanBase")
+	config, err := NewConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	mysqlMgr, err := mysql.NewManagerWithConfig(config.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := &Manager{
+		Manager:    *mysqlMgr.(*mysql.Manager),
+		tenantName: config.TenantName,
+	}
+
+	mgr.SetLogger(logger)
+	return mgr, nil
+}
+
+// IsDBStartupReady waits for the local observer to start the service, an observer
+// accepts connections before it's able to serve the tenants.
+func (mgr *Manager) IsDBStartupReady() bool {
+	if mgr.DBStartupReady {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	server, err := mgr.getLocalServer(ctx)
+	if err != nil {
+		mgr.Logger.Info("DB is not ready", "error", err.Error())
+		return false
+	}
+	if server.health() != models.HealthHealthy {
+		mgr.Logger.Info("DB is not ready", "status", server.status, "zoneStatus", server.zoneStatus)
+		return false
+	}
+
+	mgr.DBStartupReady = true
+	mgr.Logger.Info("DB startup ready")
+	return true
+}
+
+// GetReplicationStatus is not implemented, the log streams are replicated by
+// OceanBase itself instead of MySQL replication.
+func (mgr *Manager) GetReplicationStatus(ctx context.Context) (*models.ReplicationStatus, error) {
+	return mgr.DBManagerBase.GetReplicationStatus(ctx)
+}
+
+// GetMemberView and FenceMember are not implemented, the leaders of the log streams
+// are elected by OceanBase itself, so an observer is never fenced by dbctl.
+func (mgr *Manager) GetMemberView(ctx context.Context, address string) (*models.MemberView, error) {
+	return mgr.DBManagerBase.GetMemberView(ctx, address)
+}
+
+func (mgr *Manager) FenceMember(ctx context.Context, address string) error {
+	return mgr.DBManagerBase.FenceMember(ctx, address)
+}
+
+// ClassifyParameter and SetParameter are not implemented, the parameters of an
+// observer are set by ALTER SYSTEM rather than SET PERSIST of MySQL.
+func (mgr *Manager) ClassifyParameter(context.Context, string) (models.ParameterKind, error) {
+	return "", models.ErrNotImplemented
+}
+
+func (mgr *Manager) SetParameter(context.Context, string, string) error {
+	return models.ErrNotImplemented
+}
+
+// CheckConfigDrift is not implemented, the parameters of an observer are kept in
+// its own config rather than my.cnf.
+func (mgr *Manager) CheckConfigDrift(context.Context, string) (*models.ConfigDriftReport, error) {
+	return nil, models.ErrNotImplemented
+}
+
+// ListSessions and KillSession are not implemented, the processlist of the sys
+// tenant doesn't cover the sessions of the user tenants.
+func (mgr *Manager) ListSessions(context.Context) ([]models.Session, error) {
+	return nil, models.ErrNotImplemented
+}
+
+func (mgr *Manager) KillSession(context.Context, string) error {
+	return models.ErrNotImplemented
+}
+
+// Promote and Demote are not implemented, the primary tenant is switched over by
+// ALTER SYSTEM SWITCHOVER of OceanBase instead of MySQL replication.
+func (mgr *Manager) Promote(context.Context) error {
+	return models.ErrNotImplemented
+}
+
+func (mgr *Manager) Demote(context.Context, string) error {
+	return models.ErrNotImplemented
+}
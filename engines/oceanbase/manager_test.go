@@ -0,0 +1,180 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oceanbase
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/conformance"
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/mysql"
+)
+
+var (
+	tenantColumns = []string{"tenant_id", "tenant_name", "tenant_role", "status"}
+	serverColumns = []string{"svr_ip", "svr_port", "zone", "status", "started", "zone_status"}
+)
+
+func mockDatabase(t *testing.T, tenantName string) (*Manager, sqlmock.Sqlmock) {
+	manager := &Manager{
+		Manager: mysql.Manager{
+			DBManagerBase: engines.DBManagerBase{
+				CurrentMemberName: "test-oceanbase-0",
+				Logger:            ctrl.Log.WithName("OceanBase-TEST"),
+			},
+		},
+		tenantName: tenantName,
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	manager.DB = db
+	return manager, mock
+}
+
+func expectLocalServer(mock sqlmock.Sqlmock, status string, started bool, zoneStatus string) {
+	mock.ExpectQuery(regexp.QuoteMeta(localServerSQL)).
+		WillReturnRows(sqlmock.NewRows(serverColumns).AddRow("10.0.0.1", 2882, "zone1", status, started, zoneStatus))
+}
+
+func TestGetReplicaRoleInfo(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("primary", func(t *testing.T) {
+		manager, mock := mockDatabase(t, "alice")
+		mock.ExpectQuery(regexp.QuoteMeta(tenantSQL)).WithArgs("alice", "alice").
+			WillReturnRows(sqlmock.NewRows(tenantColumns).AddRow(1002, "alice", "PRIMARY", "NORMAL"))
+		expectLocalServer(mock, "ACTIVE", true, "ACTIVE")
+		mock.ExpectQuery(regexp.QuoteMeta(logStatSQL)).WithArgs(1002).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "out_of_sync"}).AddRow(3, 0))
+
+		info, err := manager.GetReplicaRoleInfo(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, &models.RoleInfo{Role: models.PRIMARY, NativeRole: "PRIMARY", Writable: true, Voter: true, Health: models.HealthHealthy}, info)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("standby out of sync", func(t *testing.T) {
+		manager, mock := mockDatabase(t, "")
+		mock.ExpectQuery(regexp.QuoteMeta(tenantSQL)).WithArgs("", "").
+			WillReturnRows(sqlmock.NewRows(tenantColumns).AddRow(1002, "alice", "STANDBY", "NORMAL"))
+		expectLocalServer(mock, "ACTIVE", true, "ACTIVE")
+		mock.ExpectQuery(regexp.QuoteMeta(logStatSQL)).WithArgs(1002).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "out_of_sync"}).AddRow(3, 1))
+
+		info, err := manager.GetReplicaRoleInfo(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, models.SECONDARY, info.Role)
+		assert.False(t, info.Writable)
+		assert.Equal(t, models.HealthRecovering, info.Health)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("inactive zone", func(t *testing.T) {
+		manager, mock := mockDatabase(t, "alice")
+		mock.ExpectQuery(regexp.QuoteMeta(tenantSQL)).WithArgs("alice", "alice").
+			WillReturnRows(sqlmock.NewRows(tenantColumns).AddRow(1002, "alice", "PRIMARY", "NORMAL"))
+		expectLocalServer(mock, "ACTIVE", true, "INACTIVE")
+		mock.ExpectQuery(regexp.QuoteMeta(logStatSQL)).WithArgs(1002).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "out_of_sync"}).AddRow(3, 0))
+
+		info, err := manager.GetReplicaRoleInfo(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, models.PRIMARY, info.Role)
+		assert.False(t, info.Writable)
+		assert.Equal(t, models.HealthUnhealthy, info.Health)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("tenant not found", func(t *testing.T) {
+		manager, mock := mockDatabase(t, "bob")
+		mock.ExpectQuery(regexp.QuoteMeta(tenantSQL)).WithArgs("bob", "bob").
+			WillReturnRows(sqlmock.NewRows(tenantColumns))
+
+		_, err := manager.GetReplicaRole(ctx)
+		assert.ErrorContains(t, err, "tenant bob not found")
+	})
+}
+
+func TestIsDBStartupReady(t *testing.T) {
+	manager, mock := mockDatabase(t, "")
+	expectLocalServer(mock, "ACTIVE", false, "ACTIVE")
+	assert.False(t, manager.IsDBStartupReady())
+
+	expectLocalServer(mock, "ACTIVE", true, "ACTIVE")
+	assert.True(t, manager.IsDBStartupReady())
+	assert.True(t, manager.IsDBStartupReady())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestConformance(t *testing.T) {
+	manager, mock := mockDatabase(t, "alice")
+
+	conformance.Run(t, conformance.Suite{
+		Manager: manager,
+		Prepare: func(method conformance.Method) {
+			switch method {
+			case conformance.IsDBStartupReady:
+				expectLocalServer(mock, "ACTIVE", true, "ACTIVE")
+			case conformance.GetReplicaRole, conformance.GetReplicaRoleInfo:
+				mock.ExpectQuery(regexp.QuoteMeta(tenantSQL)).WithArgs("alice", "alice").
+					WillReturnRows(sqlmock.NewRows(tenantColumns).AddRow(1002, "alice", "PRIMARY", "NORMAL"))
+				if method == conformance.GetReplicaRoleInfo {
+					expectLocalServer(mock, "ACTIVE", true, "ACTIVE")
+					mock.ExpectQuery(regexp.QuoteMeta(logStatSQL)).WithArgs(1002).
+						WillReturnRows(sqlmock.NewRows([]string{"count", "out_of_sync"}).AddRow(3, 0))
+				}
+			case conformance.Exec:
+				mock.ExpectExec("create database test").WillReturnResult(sqlmock.NewResult(0, 1))
+			case conformance.Query:
+				mock.ExpectQuery("select 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+			}
+		},
+		ExecSQL:  "create database test",
+		QuerySQL: "select 1",
+	})
+}
+
+func TestMySQLCapabilitiesNotImplemented(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock := mockDatabase(t, "")
+
+	_, err := manager.ClassifyParameter(ctx, "max_connections")
+	assert.ErrorIs(t, err, models.ErrNotImplemented)
+	assert.ErrorIs(t, manager.SetParameter(ctx, "max_connections", "200"), models.ErrNotImplemented)
+	_, err = manager.CheckConfigDrift(ctx, "")
+	assert.ErrorIs(t, err, models.ErrNotImplemented)
+	_, err = manager.ListSessions(ctx)
+	assert.ErrorIs(t, err, models.ErrNotImplemented)
+	assert.ErrorIs(t, manager.KillSession(ctx, "12"), models.ErrNotImplemented)
+	assert.ErrorIs(t, manager.Promote(ctx), models.ErrNotImplemented)
+	assert.ErrorIs(t, manager.Demote(ctx, "10.0.0.2:2881"), models.ErrNotImplemented)
+	// nothing is sent to the observer
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,76 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oceanbase
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const serversSQL = "select s.svr_ip, s.svr_port, s.zone, s.status, s.start_service_time is not null, z.status " +
+	"from oceanbase.DBA_OB_SERVERS s join oceanbase.DBA_OB_ZONES z on s.zone = z.zone order by s.zone, s.svr_ip, s.svr_port"
+
+// GetTopology lists the observers of the cluster by zones, the members are named
+// by their zones. All the observers take the role of the tenant, since the tenant
+// is primary or standby in the whole cluster.
+func (mgr *Manager) GetTopology(ctx context.Context) (*models.Topology, error) {
+	tenant, err := mgr.getTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	local, err := mgr.getLocalServer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := mgr.DB.QueryContext(ctx, serversSQL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error executing %s", serversSQL)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	topology := &models.Topology{}
+	for rows.Next() {
+		s := &server{}
+		if err = rows.Scan(&s.ip, &s.port, &s.zone, &s.status, &s.started, &s.zoneStatus); err != nil {
+			return nil, err
+		}
+		member := models.Member{
+			Name:       s.zone,
+			Address:    net.JoinHostPort(s.ip, strconv.Itoa(s.port)),
+			Role:       tenantRole(tenant.role),
+			NativeRole: tenant.role,
+			Health:     s.health(),
+			Self:       s.ip == local.ip && s.port == local.port,
+		}
+		topology.Members = append(topology.Members, member)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return topology, nil
+}
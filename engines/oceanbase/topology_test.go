@@ -0,0 +1,52 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oceanbase
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestGetTopology(t *testing.T) {
+	manager, mock := mockDatabase(t, "alice")
+	mock.ExpectQuery(regexp.QuoteMeta(tenantSQL)).
+		WillReturnRows(sqlmock.NewRows(tenantColumns).AddRow(1002, "alice", "PRIMARY", "NORMAL"))
+	expectLocalServer(mock, "ACTIVE", true, "ACTIVE")
+	mock.ExpectQuery(regexp.QuoteMeta(serversSQL)).
+		WillReturnRows(sqlmock.NewRows(serverColumns).
+			AddRow("10.0.0.1", 2882, "zone1", "ACTIVE", true, "ACTIVE").
+			AddRow("10.0.0.2", 2882, "zone2", "ACTIVE", false, "ACTIVE").
+			AddRow("10.0.0.3", 2882, "zone3", "INACTIVE", false, "ACTIVE"))
+
+	topology, err := manager.GetTopology(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, []models.Member{
+		{Name: "zone1", Address: "10.0.0.1:2882", Role: models.PRIMARY, NativeRole: "PRIMARY", Health: models.HealthHealthy, Self: true},
+		{Name: "zone2", Address: "10.0.0.2:2882", Role: models.PRIMARY, NativeRole: "PRIMARY", Health: models.HealthRecovering},
+		{Name: "zone3", Address: "10.0.0.3:2882", Role: models.PRIMARY, NativeRole: "PRIMARY", Health: models.HealthUnhealthy},
+	}, topology.Members)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,245 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package opengauss
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/apecloud/dbctl/engines/postgres"
+)
+
+// the authentication requests of openGauss besides the ones of PostgreSQL.
+const (
+	authReqOK        = 0
+	authReqSHA256    = 10
+	authReqMD5SHA256 = 11
+
+	// the methods the password is stored with in the sha256 request.
+	plainPassword  = 0
+	md5Password    = 1
+	sha256Password = 2
+
+	// defaultIteration is used by the servers not sending the iteration, which is
+	// only sent to the clients of protocol 3.51.
+	defaultIteration = 2048
+
+	sslRequestCode = 80877103
+	// maxAuthMessageSize is far beyond the authentication messages, it guards
+	// against reading a message of a broken connection.
+	maxAuthMessageSize = 1 << 20
+)
+
+// withAuth makes the connections answer the sha256 password requests of openGauss,
+// which pgx doesn't know. TLS is negotiated by the dial instead of pgx, so that
+// the authentication over TLS can be answered as well.
+func withAuth(config *postgres.Config) {
+	connConfig := config.PoolConfig().ConnConfig
+	dial := connConfig.DialFunc
+	tlsConfig := connConfig.TLSConfig
+	// sslmode prefer falls back to a plain connection
+	allowPlain := tlsConfig == nil
+	for _, fallback := range connConfig.Fallbacks {
+		allowPlain = allowPlain || fallback.TLSConfig == nil
+	}
+	connConfig.TLSConfig = nil
+	connConfig.Fallbacks = nil
+
+	connConfig.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig != nil {
+			conn, err = negotiateTLS(ctx, conn, tlsConfig, allowPlain)
+			if err != nil {
+				return nil, err
+			}
+		}
+		user, password := config.GetCredentials()
+		return &authConn{Conn: conn, user: user, password: password}, nil
+	}
+}
+
+func negotiateTLS(ctx context.Context, conn net.Conn, tlsConfig *tls.Config, allowPlain bool) (net.Conn, error) {
+	request := binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, 8), sslRequestCode)
+	if _, err := conn.Write(request); err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrap(err, "send ssl request failed")
+	}
+	response := make([]byte, 1)
+	if _, err := io.ReadFull(conn, response); err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrap(err, "receive ssl response failed")
+	}
+	if response[0] != 'S' {
+		if allowPlain {
+			return conn, nil
+		}
+		_ = conn.Close()
+		return nil, errors.New("server refused TLS connection")
+	}
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrap(err, "tls handshake failed")
+	}
+	return tlsConn, nil
+}
+
+// authConn answers the password requests of openGauss on behalf of pgx, the other
+// messages are passed through to pgx, and so is everything after the authentication.
+type authConn struct {
+	net.Conn
+	user     string
+	password string
+	done     bool
+	// pending are the messages received but not read by pgx yet.
+	pending []byte
+}
+
+func (c *authConn) Read(p []byte) (int, error) {
+	for !c.done && len(c.pending) == 0 {
+		if err := c.receive(); err != nil {
+			return 0, err
+		}
+	}
+	if len(c.pending) > 0 {
+		n := copy(p, c.pending)
+		c.pending = c.pending[n:]
+		return n, nil
+	}
+	return c.Conn.Read(p)
+}
+
+// receive reads a message of the server, the password requests of openGauss are
+// answered and dropped.
+func (c *authConn) receive() error {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(c.Conn, header); err != nil {
+		return err
+	}
+	length := int(binary.BigEndian.Uint32(header[1:]))
+	if length < 4 || length > maxAuthMessageSize {
+		return errors.Errorf("invalid message length %d", length)
+	}
+	body := make([]byte, length-4)
+	if _, err := io.ReadFull(c.Conn, body); err != nil {
+		return err
+	}
+
+	switch {
+	case header[0] != 'R' || len(body) < 4:
+		// an error response ends the authentication
+		c.done = true
+	case binary.BigEndian.Uint32(body) == authReqSHA256:
+		return c.sendPassword(body[4:])
+	case binary.BigEndian.Uint32(body) == authReqMD5SHA256:
+		return errors.New("md5sha256 authentication of openGauss is not supported")
+	case binary.BigEndian.Uint32(body) == authReqOK:
+		c.done = true
+	}
+	c.pending = append(header, body...)
+	return nil
+}
+
+func (c *authConn) sendPassword(request []byte) error {
+	response, err := passwordResponse(c.user, c.password, request)
+	if err != nil {
+		return err
+	}
+	message := []byte{'p'}
+	message = binary.BigEndian.AppendUint32(message, uint32(len(response)+5))
+	message = append(message, response...)
+	message = append(message, 0)
+	_, err = c.Conn.Write(message)
+	return err
+}
+
+// passwordResponse answers the sha256 request, which carries how the password is
+// stored by the server, followed by the salt and the token for a sha256 password.
+func passwordResponse(user, password string, request []byte) ([]byte, error) {
+	if len(request) < 4 {
+		return nil, errors.New("invalid sha256 authentication request")
+	}
+	method, request := binary.BigEndian.Uint32(request), request[4:]
+	switch method {
+	case plainPassword, sha256Password:
+		if len(request) < 72 {
+			return nil, errors.New("invalid sha256 authentication request")
+		}
+		iteration := defaultIteration
+		if len(request) >= 76 {
+			iteration = int(binary.BigEndian.Uint32(request[72:76]))
+		}
+		return rfc5802Response(password, string(request[:64]), string(request[64:72]), iteration)
+	case md5Password:
+		if len(request) < 4 {
+			return nil, errors.New("invalid md5 authentication request")
+		}
+		return []byte("md5" + md5Hex(md5Hex(password+user)+string(request[:4]))), nil
+	default:
+		return nil, errors.Errorf("unsupported password stored method %d", method)
+	}
+}
+
+// rfc5802Response proves the password as the SCRAM client proof of RFC 5802, but
+// with the salt and the token in hex, and without the server signature checked.
+func rfc5802Response(password, salt, token string, iteration int) ([]byte, error) {
+	saltBytes, err := hex.DecodeString(salt)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid salt")
+	}
+	tokenBytes, err := hex.DecodeString(token)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid token")
+	}
+
+	saltedPassword := pbkdf2.Key([]byte(password), saltBytes, iteration, 32, sha1.New)
+	clientKey := hmacSHA256(saltedPassword, []byte("Client Key"))
+	storedKey := sha256.Sum256(clientKey)
+	proof := hmacSHA256(storedKey[:], tokenBytes)
+	for i := range proof {
+		proof[i] ^= clientKey[i]
+	}
+	return []byte(hex.EncodeToString(proof)), nil
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,182 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package opengauss
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/apecloud/dbctl/engines/postgres"
+)
+
+const (
+	testSalt  = "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+	testToken = "0a1b2c3d"
+)
+
+func authMessage(code uint32, payload []byte) []byte {
+	body := binary.BigEndian.AppendUint32(nil, code)
+	body = append(body, payload...)
+	message := []byte{'R'}
+	message = binary.BigEndian.AppendUint32(message, uint32(len(body)+4))
+	return append(message, body...)
+}
+
+// verifyProof checks the proof as the server does, by the stored key only.
+func verifyProof(t *testing.T, password string, iteration int, response []byte) bool {
+	salt, _ := hex.DecodeString(testSalt)
+	token, _ := hex.DecodeString(testToken)
+	clientKey := hmacSHA256(pbkdf2.Key([]byte(password), salt, iteration, 32, sha1.New), []byte("Client Key"))
+	storedKey := sha256.Sum256(clientKey)
+
+	proof, err := hex.DecodeString(string(response))
+	assert.Nil(t, err)
+	signature := hmacSHA256(storedKey[:], token)
+	for i := range proof {
+		proof[i] ^= signature[i]
+	}
+	recovered := sha256.Sum256(proof)
+	return recovered == storedKey
+}
+
+func TestPasswordResponse(t *testing.T) {
+	request := binary.BigEndian.AppendUint32(nil, sha256Password)
+	request = append(request, testSalt+testToken...)
+	response, err := passwordResponse("gaussdb", "Gauss@123", request)
+	assert.Nil(t, err)
+	assert.True(t, verifyProof(t, "Gauss@123", defaultIteration, response))
+	assert.False(t, verifyProof(t, "wrong", defaultIteration, response))
+
+	request = binary.BigEndian.AppendUint32(request, 10000)
+	response, err = passwordResponse("gaussdb", "Gauss@123", request)
+	assert.Nil(t, err)
+	assert.True(t, verifyProof(t, "Gauss@123", 10000, response))
+
+	request = binary.BigEndian.AppendUint32(nil, md5Password)
+	request = append(request, "salt"...)
+	response, err = passwordResponse("gaussdb", "Gauss@123", request)
+	assert.Nil(t, err)
+	assert.Equal(t, "md5"+md5Hex(md5Hex("Gauss@123gaussdb")+"salt"), string(response))
+
+	_, err = passwordResponse("gaussdb", "Gauss@123", binary.BigEndian.AppendUint32(nil, 9))
+	assert.NotNil(t, err)
+}
+
+func TestAuthConn(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	conn := &authConn{Conn: client, user: "gaussdb", password: "Gauss@123"}
+
+	readyForQuery := []byte{'Z', 0, 0, 0, 5, 'I'}
+	go func() {
+		defer server.Close()
+		payload := binary.BigEndian.AppendUint32(nil, sha256Password)
+		payload = append(payload, testSalt+testToken...)
+		_, _ = server.Write(authMessage(authReqSHA256, payload))
+
+		header := make([]byte, 5)
+		_, _ = io.ReadFull(server, header)
+		body := make([]byte, binary.BigEndian.Uint32(header[1:])-4)
+		_, _ = io.ReadFull(server, body)
+		if header[0] != 'p' || !verifyProof(t, "Gauss@123", defaultIteration, bytes.TrimSuffix(body, []byte{0})) {
+			return
+		}
+		_, _ = server.Write(authMessage(authReqOK, nil))
+		_, _ = server.Write(readyForQuery)
+	}()
+
+	// pgx sees the authentication ok, and the messages after it as they are
+	received, err := io.ReadAll(conn)
+	assert.Nil(t, err)
+	assert.Equal(t, append(authMessage(authReqOK, nil), readyForQuery...), received)
+}
+
+// TestWithAuth connects pgx to a fake server, which refuses TLS and asks for the
+// sha256 password.
+func TestWithAuth(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	viper.Set("postgresql", map[string]any{"host": "127.0.0.1", "port": listener.Addr().(*net.TCPAddr).Port})
+	viper.Set(EnvUser, "gaussdb")
+	viper.Set(EnvPassword, "Gauss@123")
+	defer func() {
+		viper.Set("postgresql", nil)
+		viper.Set(EnvUser, nil)
+		viper.Set(EnvPassword, nil)
+	}()
+	config, err := postgres.NewConfigWithEnvs(EnvUser, EnvPassword)
+	assert.Nil(t, err)
+	withAuth(config)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		readStartup := func() []byte {
+			header := make([]byte, 4)
+			_, _ = io.ReadFull(conn, header)
+			body := make([]byte, binary.BigEndian.Uint32(header)-4)
+			_, _ = io.ReadFull(conn, body)
+			return body
+		}
+		if binary.BigEndian.Uint32(readStartup()) == sslRequestCode {
+			_, _ = conn.Write([]byte{'N'})
+			readStartup()
+		}
+
+		payload := binary.BigEndian.AppendUint32(nil, sha256Password)
+		payload = append(payload, testSalt+testToken...)
+		_, _ = conn.Write(authMessage(authReqSHA256, payload))
+		header := make([]byte, 5)
+		_, _ = io.ReadFull(conn, header)
+		body := make([]byte, binary.BigEndian.Uint32(header[1:])-4)
+		_, _ = io.ReadFull(conn, body)
+		if !verifyProof(t, "Gauss@123", defaultIteration, bytes.TrimSuffix(body, []byte{0})) {
+			return
+		}
+		_, _ = conn.Write(authMessage(authReqOK, nil))
+		_, _ = conn.Write([]byte{'K', 0, 0, 0, 12, 0, 0, 0, 1, 0, 0, 0, 2})
+		_, _ = conn.Write([]byte{'Z', 0, 0, 0, 5, 'I'})
+		_, _ = io.Copy(io.Discard, conn)
+	}()
+
+	conn, err := pgx.ConnectConfig(context.TODO(), config.PoolConfig().ConnConfig)
+	assert.Nil(t, err)
+	if conn != nil {
+		assert.Equal(t, uint32(1), conn.PgConn().PID())
+		_ = conn.Close(context.TODO())
+	}
+}
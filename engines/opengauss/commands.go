@@ -21,6 +21,8 @@ package opengauss
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -71,6 +73,12 @@ func (c *Commands) ConnectExample(info *engines.ConnectionInfo, client string) s
 	return engines.BuildExample(info, client, c.examples)
 }
 
-func (c *Commands) ExecuteCommand(strings []string) ([]string, []corev1.EnvVar, error) {
-	return nil, nil, fmt.Errorf("opengauss execute cammand interface do not implement")
+func (c *Commands) ExecuteCommand(scripts []string) ([]string, []corev1.EnvVar, error) {
+	cmd := []string{"/bin/sh", "-c", "-ex", fmt.Sprintf("%s -h$%s -p$%s -U$%s -W$%s -d postgres -c %s", c.info.Client,
+		engines.EnvVarMap[engines.HOST],
+		engines.EnvVarMap[engines.PORT],
+		engines.EnvVarMap[engines.USER],
+		engines.EnvVarMap[engines.PASSWORD],
+		strconv.Quote(strings.Join(scripts, " ")))}
+	return cmd, nil, nil
 }
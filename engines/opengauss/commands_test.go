@@ -57,4 +57,12 @@ var _ = Describe("OpenGauss Engine", func() {
 		Expect(opengauss.ConnectExample(info, "")).ShouldNot(BeZero())
 	})
 
+	It("execute command", func() {
+		opengauss := NewCommands()
+
+		cmd, envs, err := opengauss.ExecuteCommand([]string{"select 1;"})
+		Expect(err).Should(Succeed())
+		Expect(envs).Should(BeEmpty())
+		Expect(cmd).Should(Equal([]string{"/bin/sh", "-c", "-ex", `gsql -h$KB_HOST -p$KB_PORT -U$KB_USER -W$KB_PASSWD -d postgres -c "select 1;"`}))
+	})
 })
@@ -0,0 +1,77 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package opengauss
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/postgres"
+)
+
+const roleSQL = "select local_role, pg_is_in_recovery() as in_recovery from pg_stat_get_stream_replications()"
+
+var _ engines.RoleInfoGetter = &Manager{}
+
+// localRoles maps the lowercased local_role of openGauss, a single node is normal,
+// and a pending standby waits to be notified as the primary or a standby.
+var localRoles = map[string]models.RoleInfo{
+	"primary":         {Role: models.PRIMARY, Writable: true, Voter: true, Health: models.HealthHealthy},
+	"normal":          {Role: models.PRIMARY, Writable: true, Voter: true, Health: models.HealthHealthy},
+	"standby":         {Role: models.SECONDARY, Voter: true, Health: models.HealthHealthy},
+	"cascade standby": {Role: models.SECONDARY, Health: models.HealthHealthy},
+	"pending":         {Role: models.SECONDARY, Voter: true, Health: models.HealthRecovering},
+}
+
+func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
+	info, err := mgr.GetReplicaRoleInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	return info.Role, nil
+}
+
+// GetReplicaRoleInfo takes the local_role, and pg_is_in_recovery() for an unknown one.
+func (mgr *Manager) GetReplicaRoleInfo(ctx context.Context) (*models.RoleInfo, error) {
+	resp, err := mgr.Query(ctx, roleSQL)
+	if err != nil {
+		return nil, errors.Wrap(err, "query local role failed")
+	}
+	result, err := postgres.ParseQuery(string(resp))
+	if err != nil {
+		return nil, errors.Wrap(err, "parse local role failed")
+	}
+
+	localRole := cast.ToString(result[0]["local_role"])
+	info, ok := localRoles[strings.ToLower(localRole)]
+	if !ok {
+		info = models.RoleInfo{Role: models.PRIMARY, Writable: true, Voter: true, Health: models.HealthUnknown}
+		if cast.ToBool(result[0]["in_recovery"]) {
+			info.Role, info.Writable = models.SECONDARY, false
+		}
+	}
+	info.NativeRole = localRole
+	return &info, nil
+}
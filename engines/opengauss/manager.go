@@ -0,0 +1,99 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package opengauss
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/postgres"
+)
+
+const (
+	EnvUser     = "GS_USERNAME"
+	EnvPassword = "GS_PASSWORD"
+)
+
+// Manager speaks the PostgreSQL protocol, with the password authentication of
+// openGauss answered by the connections.
+type Manager struct {
+	postgres.Manager
+}
+
+var _ engines.DBManager = &Manager{}
+
+func NewManager() (engines.DBManager, error) {
+	logger := ctrl.Log.WithName("openGauss")
+	config, err := postgres.NewConfigWithEnvs(EnvUser, EnvPassword)
+	if err != nil {
+		return nil, err
+	}
+	withAuth(config)
+
+	pgMgr, err := postgres.NewManagerWithConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := &Manager{
+		Manager: *pgMgr.(*postgres.Manager),
+	}
+	mgr.SetLogger(logger)
+	return mgr, nil
+}
+
+// GetTopology is not implemented, pg_stat_replication of openGauss has none of the
+// wal columns of PostgreSQL 10.
+func (mgr *Manager) GetTopology(context.Context) (*models.Topology, error) {
+	return nil, models.ErrNotImplemented
+}
+
+// ListSessions and KillSession are not implemented, pg_stat_activity of openGauss
+// has no backend_type to tell the client sessions.
+func (mgr *Manager) ListSessions(context.Context) ([]models.Session, error) {
+	return nil, models.ErrNotImplemented
+}
+
+func (mgr *Manager) KillSession(context.Context, string) error {
+	return models.ErrNotImplemented
+}
+
+// GetMemberView and FenceMember are not implemented, the member view reads
+// pg_stat_wal_receiver and the fencing is by ALTER SYSTEM of PostgreSQL.
+func (mgr *Manager) GetMemberView(context.Context, string) (*models.MemberView, error) {
+	return nil, models.ErrNotImplemented
+}
+
+func (mgr *Manager) FenceMember(context.Context, string) error {
+	return models.ErrNotImplemented
+}
+
+// Promote and Demote are not implemented, an openGauss member is switched over by
+// gs_ctl rather than pg_promote() and primary_conninfo.
+func (mgr *Manager) Promote(context.Context) error {
+	return models.ErrNotImplemented
+}
+
+func (mgr *Manager) Demote(context.Context, string) error {
+	return models.ErrNotImplemented
+}
@@ -0,0 +1,166 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package opengauss
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/constant"
+	"github.com/apecloud/dbctl/engines/conformance"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func mockDatabase(t *testing.T) (*Manager, pgxmock.PgxPoolIface) {
+	viper.Set(constant.KBEnvPodName, "test-opengauss-0")
+	viper.Set(EnvUser, "gaussdb")
+	t.Cleanup(func() {
+		viper.Set(constant.KBEnvPodName, nil)
+		viper.Set(EnvUser, nil)
+	})
+	mock, err := pgxmock.NewPool(pgxmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dbManager, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := dbManager.(*Manager)
+	user, _ := manager.Config.GetCredentials()
+	assert.Equal(t, "gaussdb", user)
+	manager.Pool = mock
+	return manager, mock
+}
+
+func TestGetReplicaRoleInfo(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock := mockDatabase(t)
+	defer mock.Close()
+	columns := []string{"local_role", "in_recovery"}
+
+	for _, tt := range []struct {
+		localRole  string
+		inRecovery bool
+		expected   *models.RoleInfo
+	}{
+		{"Primary", false, &models.RoleInfo{Role: models.PRIMARY, NativeRole: "Primary", Writable: true, Voter: true, Health: models.HealthHealthy}},
+		{"Normal", false, &models.RoleInfo{Role: models.PRIMARY, NativeRole: "Normal", Writable: true, Voter: true, Health: models.HealthHealthy}},
+		{"Standby", true, &models.RoleInfo{Role: models.SECONDARY, NativeRole: "Standby", Voter: true, Health: models.HealthHealthy}},
+		{"Pending", true, &models.RoleInfo{Role: models.SECONDARY, NativeRole: "Pending", Voter: true, Health: models.HealthRecovering}},
+		{"Unknown", true, &models.RoleInfo{Role: models.SECONDARY, NativeRole: "Unknown", Voter: true, Health: models.HealthUnknown}},
+	} {
+		mock.ExpectQuery(regexp.QuoteMeta(roleSQL)).WillReturnRows(pgxmock.NewRows(columns).AddRow(tt.localRole, tt.inRecovery))
+		info, err := manager.GetReplicaRoleInfo(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.expected, info)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(roleSQL)).WillReturnRows(pgxmock.NewRows(columns).AddRow("Standby", true))
+	role, err := manager.GetReplicaRole(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, models.SECONDARY, role)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestConformance(t *testing.T) {
+	manager, mock := mockDatabase(t)
+	defer mock.Close()
+	// pgxmock only checks the context while delaying
+	standInDelay := 10 * time.Millisecond
+
+	conformance.Run(t, conformance.Suite{
+		Manager: manager,
+		Prepare: func(method conformance.Method) {
+			switch method {
+			case conformance.IsDBStartupReady:
+				mock.ExpectPing()
+			case conformance.GetReplicaRole, conformance.GetReplicaRoleInfo:
+				mock.ExpectQuery(regexp.QuoteMeta(roleSQL)).
+					WillReturnRows(pgxmock.NewRows([]string{"local_role", "in_recovery"}).AddRow("Primary", false)).
+					WillDelayFor(standInDelay)
+			case conformance.Exec:
+				mock.ExpectExec("create database test").
+					WillReturnResult(pgxmock.NewResult("CREATE DATABASE", 0)).
+					WillDelayFor(standInDelay)
+			case conformance.Query:
+				mock.ExpectQuery("select 1").
+					WillReturnRows(pgxmock.NewRows([]string{"?column?"}).AddRow(1)).
+					WillDelayFor(standInDelay)
+			}
+		},
+		ExecSQL:  "create database test",
+		QuerySQL: "select 1",
+	})
+}
+
+func TestGetReplicationStatus(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock := mockDatabase(t)
+	defer mock.Close()
+	columns := []string{"in_recovery", "applied_lsn", "received_lsn", "lag_bytes"}
+
+	mock.ExpectQuery(regexp.QuoteMeta(replicationSQL)).
+		WillReturnRows(pgxmock.NewRows(columns).AddRow(false, "0/3000148", nil, nil))
+	status, err := manager.GetReplicationStatus(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, &models.ReplicationStatus{AppliedPosition: "0/3000148"}, status)
+
+	mock.ExpectQuery(regexp.QuoteMeta(replicationSQL)).
+		WillReturnRows(pgxmock.NewRows(columns).AddRow(true, "0/3000100", "0/3000148", int64(72)))
+	status, err = manager.GetReplicationStatus(ctx)
+	assert.Nil(t, err)
+	lagBytes := int64(72)
+	assert.Equal(t, &models.ReplicationStatus{
+		IsReplica:        true,
+		LagBytes:         &lagBytes,
+		ReceivedPosition: "0/3000148",
+		AppliedPosition:  "0/3000100",
+	}, status)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresCapabilitiesNotImplemented(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock := mockDatabase(t)
+	defer mock.Close()
+
+	_, err := manager.GetTopology(ctx)
+	assert.ErrorIs(t, err, models.ErrNotImplemented)
+	_, err = manager.ListSessions(ctx)
+	assert.ErrorIs(t, err, models.ErrNotImplemented)
+	assert.ErrorIs(t, manager.KillSession(ctx, "4321"), models.ErrNotImplemented)
+	_, err = manager.GetMemberView(ctx, "")
+	assert.ErrorIs(t, err, models.ErrNotImplemented)
+	assert.ErrorIs(t, manager.FenceMember(ctx, ""), models.ErrNotImplemented)
+	assert.ErrorIs(t, manager.Promote(ctx), models.ErrNotImplemented)
+	assert.ErrorIs(t, manager.Demote(ctx, "test-opengauss-1"), models.ErrNotImplemented)
+	// nothing is sent to openGauss
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
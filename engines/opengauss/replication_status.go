@@ -0,0 +1,65 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package opengauss
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/postgres"
+)
+
+// replicationSQL takes the xlog functions of openGauss, which is based on
+// PostgreSQL 9.2 and has none of the wal functions of PostgreSQL 10.
+const replicationSQL = `select pg_is_in_recovery() as in_recovery,
+case when pg_is_in_recovery() then pg_last_xlog_replay_location() else pg_current_xlog_location() end as applied_lsn,
+pg_last_xlog_receive_location() as received_lsn,
+pg_xlog_location_diff(pg_last_xlog_receive_location(), pg_last_xlog_replay_location())::bigint as lag_bytes`
+
+// GetReplicationStatus reads the received and replayed xlog of a standby, the lag
+// is only in bytes, and the upstream is not known.
+func (mgr *Manager) GetReplicationStatus(ctx context.Context) (*models.ReplicationStatus, error) {
+	resp, err := mgr.Query(ctx, replicationSQL)
+	if err != nil {
+		return nil, errors.Wrap(err, "query replication status failed")
+	}
+	result, err := postgres.ParseQuery(string(resp))
+	if err != nil {
+		return nil, errors.Wrap(err, "parse replication status failed")
+	}
+
+	row := result[0]
+	status := &models.ReplicationStatus{
+		IsReplica:       cast.ToBool(row["in_recovery"]),
+		AppliedPosition: cast.ToString(row["applied_lsn"]),
+	}
+	if !status.IsReplica {
+		return status, nil
+	}
+	status.ReceivedPosition = cast.ToString(row["received_lsn"])
+	if row["lag_bytes"] != nil {
+		lagBytes := cast.ToInt64(row["lag_bytes"])
+		status.LagBytes = &lagBytes
+	}
+	return status, nil
+}
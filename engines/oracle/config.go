@@ -0,0 +1,131 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oracle
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	go_ora "github.com/sijms/go-ora/v2"
+	"github.com/spf13/viper"
+
+	"github.com/apecloud/dbctl/constant"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+const (
+	// configSection is the config file section of Oracle.
+	configSection = "oracle"
+
+	defaultHost    = "127.0.0.1"
+	defaultPort    = 1521
+	defaultService = "ORCLCDB"
+	defaultUser    = "sys"
+	defaultTimeout = 5 * time.Second
+
+	EnvUser     = "ORACLE_USER"
+	EnvPassword = "ORACLE_PWD"
+	EnvSID      = "ORACLE_SID"
+)
+
+type Config struct {
+	Host           string
+	Port           int
+	Service        string
+	MaxOpenConns   int
+	MaxIdleConns   int
+	ConnectTimeout time.Duration
+	tlsEnabled     bool
+	tlsSkipVerify  bool
+
+	*utilconfig.EngineCredentials
+}
+
+var config *Config
+
+func NewConfig() (*Config, error) {
+	config = &Config{
+		EngineCredentials: utilconfig.NewEngineCredentials(configSection,
+			utilconfig.CredentialLookup{Default: defaultUser, Name: constant.ConfigKeyUserName, Envs: []string{EnvUser}},
+			utilconfig.CredentialLookup{Name: constant.ConfigKeyPassword, Envs: []string{EnvPassword}}),
+		Host:           defaultHost,
+		Port:           defaultPort,
+		Service:        defaultService,
+		MaxOpenConns:   5,
+		MaxIdleConns:   1,
+		ConnectTimeout: defaultTimeout,
+	}
+	if viper.IsSet(EnvSID) {
+		config.Service = viper.GetString(EnvSID)
+	}
+
+	engineConfig, err := utilconfig.GetEngineConfig(configSection)
+	if err != nil {
+		return nil, err
+	}
+	config.mergeEngineConfig(engineConfig)
+	config.LoadCredentials(engineConfig)
+
+	if viper.IsSet(constant.KBEnvServicePort) {
+		config.Port = viper.GetInt(constant.KBEnvServicePort)
+	}
+	return config, nil
+}
+
+func (config *Config) mergeEngineConfig(engineConfig *utilconfig.EngineConfig) {
+	if engineConfig.Host != "" {
+		config.Host = engineConfig.Host
+	}
+	if engineConfig.Port != 0 {
+		config.Port = engineConfig.Port
+	}
+	if engineConfig.Database != "" {
+		config.Service = engineConfig.Database
+	}
+	if engineConfig.MaxOpenConns != 0 {
+		config.MaxOpenConns = engineConfig.MaxOpenConns
+	}
+	if engineConfig.MaxIdleConns != 0 {
+		config.MaxIdleConns = engineConfig.MaxIdleConns
+	}
+	if engineConfig.ConnectTimeout != 0 {
+		config.ConnectTimeout = engineConfig.ConnectTimeout
+	}
+	config.tlsEnabled = engineConfig.TLS.Enabled
+	config.tlsSkipVerify = engineConfig.TLS.InsecureSkipVerify
+}
+
+// URL returns the go-ora url with the current credentials, sys is connected as
+// sysdba, as it can't be connected otherwise.
+func (config *Config) URL() string {
+	username, password := config.GetCredentials()
+	options := map[string]string{
+		"TIMEOUT": strconv.Itoa(int(config.ConnectTimeout.Seconds())),
+	}
+	if strings.EqualFold(username, defaultUser) {
+		options["DBA PRIVILEGE"] = "SYSDBA"
+	}
+	if config.tlsEnabled {
+		options["SSL"] = "TRUE"
+		options["SSL VERIFY"] = strconv.FormatBool(!config.tlsSkipVerify)
+	}
+	return go_ora.BuildUrl(config.Host, config.Port, config.Service, username, password, options)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oracle
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const databaseRoleSQL = "select database_role, open_mode from v$database"
+
+const (
+	rolePrimary         = "PRIMARY"
+	rolePhysicalStandby = "PHYSICAL STANDBY"
+	openModeReadWrite   = "READ WRITE"
+	openModeMounted     = "MOUNTED"
+)
+
+var _ engines.RoleInfoGetter = &Manager{}
+
+func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
+	info, err := mgr.GetReplicaRoleInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	return info.Role, nil
+}
+
+// GetReplicaRoleInfo maps the Data Guard role, a standby of any kind is secondary,
+// and only the physical standby is taken as a failover target. A mounted primary
+// is recovering, while a mounted standby is applying the redo as usual.
+func (mgr *Manager) GetReplicaRoleInfo(ctx context.Context) (*models.RoleInfo, error) {
+	var role, openMode string
+	if err := mgr.DB.QueryRowContext(ctx, databaseRoleSQL).Scan(&role, &openMode); err != nil {
+		return nil, errors.Wrapf(err, "error executing %s", databaseRoleSQL)
+	}
+
+	info := &models.RoleInfo{
+		Role:       models.SECONDARY,
+		NativeRole: role,
+		Health:     models.HealthHealthy,
+	}
+	switch strings.ToUpper(role) {
+	case rolePrimary:
+		info.Role = models.PRIMARY
+		info.Voter = true
+		info.Writable = strings.EqualFold(openMode, openModeReadWrite)
+		if strings.EqualFold(openMode, openModeMounted) {
+			info.Health = models.HealthRecovering
+		}
+	case rolePhysicalStandby:
+		info.Voter = true
+	}
+	return info, nil
+}
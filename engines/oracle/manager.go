@@ -0,0 +1,115 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	go_ora "github.com/sijms/go-ora/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+type Manager struct {
+	engines.DBManagerBase
+	DB     *sql.DB
+	config *Config
+}
+
+var _ engines.DBManager = &Manager{}
+
+func NewManager() (engines.DBManager, error) {
+	logger := ctrl.Log.WithName("Oracle")
+	config, err := NewConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	managerBase, err := engines.NewDBManagerBase(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	db := sql.OpenDB(&connector{config: config})
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+
+	mgr := &Manager{
+		DBManagerBase: *managerBase,
+		DB:            db,
+		config:        config,
+	}
+
+	utilconfig.OnCredentialsChange(mgr.reloadCredentials)
+	return mgr, nil
+}
+
+// connector connects with the url of the current credentials, so that the
+// connection pool keeps working after the secrets are rotated.
+type connector struct {
+	config *Config
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	return go_ora.NewConnector(c.config.URL()).Connect(ctx)
+}
+
+func (c *connector) Driver() driver.Driver {
+	return go_ora.NewDriver()
+}
+
+// reloadCredentials drops the idle connections after the credentials are reloaded,
+// new connections are established with the rotated credentials.
+func (mgr *Manager) reloadCredentials() {
+	if err := mgr.config.ReloadCredentials(); err != nil {
+		mgr.Logger.Info("reload credentials failed", "error", err.Error())
+		return
+	}
+	mgr.DB.SetMaxIdleConns(0)
+	mgr.DB.SetMaxIdleConns(mgr.config.MaxIdleConns)
+	mgr.Logger.Info("credentials reloaded")
+}
+
+func (mgr *Manager) IsDBStartupReady() bool {
+	if mgr.DBStartupReady {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	err := mgr.DB.PingContext(ctx)
+	if err != nil {
+		mgr.Logger.Info("DB is not ready", "error", err.Error())
+		return false
+	}
+
+	mgr.DBStartupReady = true
+	mgr.Logger.Info("DB startup ready")
+	return true
+}
+
+func (mgr *Manager) ShutDownWithWait() {
+	_ = mgr.DB.Close()
+}
@@ -0,0 +1,106 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// numberTypes are the column types go-ora returns in the decimal strings.
+var numberTypes = map[string]bool{
+	"NUMBER":   true,
+	"FLOAT":    true,
+	"BInteger": true,
+	"VarNum":   true,
+}
+
+func (mgr *Manager) Query(ctx context.Context, sql string) ([]byte, error) {
+	mgr.Logger.Info(fmt.Sprintf("query: %s", sql))
+	rows, err := mgr.DB.QueryContext(ctx, sql)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error executing %s", sql)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	result, err := jsonify(rows)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error marshalling query result for %s", sql)
+	}
+	return result, nil
+}
+
+func (mgr *Manager) Exec(ctx context.Context, sql string) (int64, error) {
+	mgr.Logger.Info(fmt.Sprintf("exec: %s", sql))
+	res, err := mgr.DB.ExecContext(ctx, sql)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error executing %s", sql)
+	}
+	return res.RowsAffected()
+}
+
+// jsonify returns the rows as JSON objects keyed by the column names, a NUMBER is
+// a JSON number in its exact decimal digits, and a DATE or TIMESTAMP is in RFC 3339.
+func jsonify(rows *sql.Rows) ([]byte, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]any{}
+	values := make([]any, len(columnTypes))
+	pointers := make([]any, len(columnTypes))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err = rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]any, len(columnTypes))
+		for i, columnType := range columnTypes {
+			row[columnType.Name()] = typedValue(columnType.DatabaseTypeName(), values[i])
+		}
+		result = append(result, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(result)
+}
+
+func typedValue(databaseType string, value any) any {
+	switch v := value.(type) {
+	case string:
+		if numberTypes[databaseType] && json.Valid([]byte(v)) {
+			return json.Number(v)
+		}
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+)
+
+type Manager struct {
+	engines.DBManagerBase
+	engineDir string
+}
+
+var _ engines.DBManager = &Manager{}
+
+// NewManagerFunc returns the constructor of the manager backed by the plugin in engineDir.
+func NewManagerFunc(engineType, engineDir string) func() (engines.DBManager, error) {
+	return func() (engines.DBManager, error) {
+		logger := ctrl.Log.WithName("Plugin").WithValues("engine", engineType)
+		managerBase, err := engines.NewDBManagerBase(logger)
+		if err != nil {
+			return nil, err
+		}
+
+		mgr := &Manager{
+			DBManagerBase: *managerBase,
+			engineDir:     engineDir,
+		}
+		return mgr, nil
+	}
+}
+
+func (mgr *Manager) IsDBStartupReady() bool {
+	if mgr.DBStartupReady {
+		return true
+	}
+	resp, err := call(context.Background(), mgr.engineDir, &Request{Method: MethodHealth})
+	if err != nil {
+		mgr.Logger.Info("DB is not ready", "error", err.Error())
+		return false
+	}
+	if !resp.Ready {
+		return false
+	}
+	mgr.DBStartupReady = true
+	mgr.Logger.Info("DB startup ready")
+	return true
+}
+
+func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
+	resp, err := call(ctx, mgr.engineDir, &Request{Method: MethodGetRole})
+	if err != nil {
+		return "", err
+	}
+	return resp.Role, nil
+}
+
+func (mgr *Manager) Exec(ctx context.Context, sql string) (int64, error) {
+	resp, err := call(ctx, mgr.engineDir, &Request{Method: MethodExec, SQL: sql})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+func (mgr *Manager) Query(ctx context.Context, sql string) ([]byte, error) {
+	resp, err := call(ctx, mgr.engineDir, &Request{Method: MethodQuery, SQL: sql})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result, nil
+}
+
+func (mgr *Manager) ShutDownWithWait() {
+	_, err := call(context.Background(), mgr.engineDir, &Request{Method: MethodShutdown})
+	if err != nil {
+		mgr.Logger.Info("shutdown failed", "error", err.Error())
+	}
+}
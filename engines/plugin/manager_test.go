@@ -0,0 +1,111 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/constant"
+	"github.com/apecloud/dbctl/engines/conformance"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func writeMethod(t *testing.T, engineDir, method, script string) {
+	path := filepath.Join(engineDir, method)
+	assert.Nil(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755))
+}
+
+func mockPlugin(t *testing.T) (string, *Manager) {
+	viper.Set(constant.KBEnvPodName, "test-pod-0")
+	t.Cleanup(viper.Reset)
+
+	pluginDir := t.TempDir()
+	engineDir := filepath.Join(pluginDir, "fakedb")
+	assert.Nil(t, os.Mkdir(engineDir, 0755))
+	assert.Nil(t, os.Mkdir(filepath.Join(pluginDir, "empty"), 0755))
+	writeMethod(t, engineDir, MethodGetRole, `echo '{"role": "primary"}'`)
+	writeMethod(t, engineDir, MethodHealth, `echo '{"ready": true}'`)
+	writeMethod(t, engineDir, MethodQuery, `grep -q '"sql":"select 1"' && echo '{"result": [{"1": 1}]}'`)
+	writeMethod(t, engineDir, MethodExec, `echo "connection refused" >&2; exit 1`)
+
+	dbManager, err := NewManagerFunc("fakedb", engineDir)()
+	assert.Nil(t, err)
+	return pluginDir, dbManager.(*Manager)
+}
+
+func TestDiscover(t *testing.T) {
+	pluginDir, _ := mockPlugin(t)
+
+	plugins, err := Discover(pluginDir)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"fakedb": filepath.Join(pluginDir, "fakedb")}, plugins)
+
+	_, err = Discover(filepath.Join(pluginDir, "not-exist"))
+	assert.NotNil(t, err)
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.TODO()
+	_, manager := mockPlugin(t)
+
+	t.Run("health", func(t *testing.T) {
+		assert.True(t, manager.IsDBStartupReady())
+	})
+
+	t.Run("get role", func(t *testing.T) {
+		role, err := manager.GetReplicaRole(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, "primary", role)
+	})
+
+	t.Run("query", func(t *testing.T) {
+		result, err := manager.Query(ctx, "select 1")
+		assert.Nil(t, err)
+		assert.JSONEq(t, `[{"1": 1}]`, string(result))
+	})
+
+	t.Run("exec failed", func(t *testing.T) {
+		_, err := manager.Exec(ctx, "create database test")
+		assert.NotNil(t, err)
+		assert.ErrorContains(t, err, "connection refused")
+	})
+
+	t.Run("method not implemented", func(t *testing.T) {
+		assert.Nil(t, os.Remove(filepath.Join(manager.engineDir, MethodGetRole)))
+		_, err := manager.GetReplicaRole(ctx)
+		assert.ErrorIs(t, err, models.ErrNotImplemented)
+	})
+}
+
+func TestConformance(t *testing.T) {
+	_, manager := mockPlugin(t)
+	writeMethod(t, manager.engineDir, MethodExec, `echo '{"count": 1}'`)
+	conformance.Run(t, conformance.Suite{
+		Manager:  manager,
+		ExecSQL:  "create database test",
+		QuerySQL: "select 1",
+	})
+}
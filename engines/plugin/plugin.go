@@ -0,0 +1,156 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// An engine plugin is a directory named after the engine type under the plugin dir,
+// holding an executable for each method it implements:
+//
+//	<plugin-dir>/<engine>/getrole
+//	<plugin-dir>/<engine>/exec
+//	<plugin-dir>/<engine>/query
+//	<plugin-dir>/<engine>/health
+//	<plugin-dir>/<engine>/shutdown
+//
+// The executable reads a Request in JSON from stdin, and writes a Response in JSON
+// to stdout. A non-zero exit code means the method failed, and the error in the
+// response, or stderr if it's empty, is returned. The methods without executable
+// are not implemented.
+const (
+	MethodGetRole  = "getrole"
+	MethodExec     = "exec"
+	MethodQuery    = "query"
+	MethodHealth   = "health"
+	MethodShutdown = "shutdown"
+
+	defaultTimeout = 10 * time.Second
+)
+
+var methods = []string{MethodGetRole, MethodExec, MethodQuery, MethodHealth, MethodShutdown}
+
+// Request is written to the stdin of the method executable.
+type Request struct {
+	Method string `json:"method"`
+	// SQL is the statement of exec and query.
+	SQL string `json:"sql,omitempty"`
+}
+
+// Response is read from the stdout of the method executable.
+type Response struct {
+	// Role is the replica role returned by getrole.
+	Role string `json:"role,omitempty"`
+	// Count is the affected rows returned by exec.
+	Count int64 `json:"count,omitempty"`
+	// Result is the result returned by query, in any JSON value.
+	Result json.RawMessage `json:"result,omitempty"`
+	// Ready is the readiness returned by health.
+	Ready bool   `json:"ready,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Discover returns the engine plugins under the plugin dir, keyed by the engine type.
+func Discover(pluginDir string) (map[string]string, error) {
+	entries, err := os.ReadDir(pluginDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read plugin dir %s failed", pluginDir)
+	}
+
+	plugins := map[string]string{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		engineDir := filepath.Join(pluginDir, entry.Name())
+		for _, method := range methods {
+			if isExecutable(filepath.Join(engineDir, method)) {
+				plugins[entry.Name()] = engineDir
+				break
+			}
+		}
+	}
+	return plugins, nil
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+}
+
+// call runs the executable of the method with the request, ErrNotImplemented is
+// returned if the plugin doesn't implement the method.
+func call(ctx context.Context, engineDir string, req *Request) (*Response, error) {
+	path := filepath.Join(engineDir, req.Method)
+	if !isExecutable(path) {
+		return nil, models.ErrNotImplemented
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
+		defer cancel()
+	}
+
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Dir = engineDir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	resp := &Response{}
+	if output := bytes.TrimSpace(stdout.Bytes()); len(output) > 0 {
+		if err = json.Unmarshal(output, resp); err != nil && runErr == nil {
+			return nil, errors.Wrapf(err, "plugin %s returned invalid response", path)
+		}
+	}
+	if runErr != nil {
+		msg := resp.Error
+		if msg == "" {
+			msg = strings.TrimSpace(stderr.String())
+		}
+		return nil, errors.Wrapf(runErr, "plugin %s failed: %s", path, msg)
+	}
+	if resp.Error != "" {
+		return nil, errors.Errorf("plugin %s failed: %s", path, resp.Error)
+	}
+	return resp, nil
+}
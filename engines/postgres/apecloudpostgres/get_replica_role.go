@@ -21,8 +21,18 @@ package apecloudpostgres
 
 import (
 	"context"
+
+	"github.com/apecloud/dbctl/engines/models"
 )
 
 func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
 	return mgr.GetMemberRoleWithHost(ctx, "")
 }
+
+func (mgr *Manager) GetReplicaRoleInfo(ctx context.Context) (*models.RoleInfo, error) {
+	role, err := mgr.getConsensusRole(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return models.NewRoleInfo(role), nil
+}
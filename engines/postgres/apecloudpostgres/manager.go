@@ -51,6 +51,15 @@ func NewManager() (engines.DBManager, error) {
 }
 
 func (mgr *Manager) GetMemberRoleWithHost(ctx context.Context, host string) (string, error) {
+	role, err := mgr.getConsensusRole(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(role), nil
+}
+
+// getConsensusRole returns the role as consensus_member_status reports it, e.g. "Leader".
+func (mgr *Manager) getConsensusRole(ctx context.Context, host string) (string, error) {
 	sql := `select role from consensus_member_status;`
 
 	resp, err := mgr.QueryWithHost(ctx, sql, host)
@@ -63,5 +72,5 @@ func (mgr *Manager) GetMemberRoleWithHost(ctx context.Context, host string) (str
 		return "", err
 	}
 
-	return strings.ToLower(cast.ToString(resMap[0]["role"])), nil
+	return cast.ToString(resMap[0]["role"]), nil
 }
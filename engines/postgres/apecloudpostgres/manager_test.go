@@ -24,12 +24,14 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pashagolub/pgxmock/v2"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/apecloud/dbctl/constant"
+	"github.com/apecloud/dbctl/engines/conformance"
 	"github.com/apecloud/dbctl/engines/models"
 	"github.com/apecloud/dbctl/engines/postgres"
 )
@@ -98,3 +100,54 @@ func TestGetMemberRoleWithHost(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %v", err)
 	}
 }
+
+func TestGetReplicaRoleInfo(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock, _ := MockDatabase(t)
+	defer mock.Close()
+
+	mock.ExpectQuery("select role from consensus_member_status;").
+		WillReturnRows(pgxmock.NewRows([]string{"role"}).AddRow("Leader"))
+
+	info, err := manager.GetReplicaRoleInfo(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, models.LEADER, info.Role)
+	assert.Equal(t, "Leader", info.NativeRole)
+	assert.True(t, info.Writable)
+	assert.True(t, info.Voter)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	manager, mock, _ := MockDatabase(t)
+	defer mock.Close()
+	// pgxmock only checks the context while delaying
+	standInDelay := 10 * time.Millisecond
+
+	conformance.Run(t, conformance.Suite{
+		Manager: manager,
+		Prepare: func(method conformance.Method) {
+			switch method {
+			case conformance.IsDBStartupReady:
+				mock.ExpectPing()
+			case conformance.GetReplicaRole, conformance.GetReplicaRoleInfo:
+				mock.ExpectQuery("select role from consensus_member_status;").
+					WillReturnRows(pgxmock.NewRows([]string{"role"}).AddRow("Leader")).
+					WillDelayFor(standInDelay)
+			case conformance.Exec:
+				mock.ExpectExec("create database test").
+					WillReturnResult(pgxmock.NewResult("CREATE DATABASE", 0)).
+					WillDelayFor(standInDelay)
+			case conformance.Query:
+				mock.ExpectQuery("select 1").
+					WillReturnRows(pgxmock.NewRows([]string{"?column?"}).AddRow(1)).
+					WillDelayFor(standInDelay)
+			}
+		},
+		ExecSQL:  "create database test",
+		QuerySQL: "select 1",
+	})
+}
@@ -20,11 +20,16 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package postgres
 
 import (
+	"context"
 	"fmt"
+	"sync"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pkg/errors"
-	"github.com/spf13/viper"
+
+	"github.com/apecloud/dbctl/constant"
+	utilconfig "github.com/apecloud/dbctl/util/config"
 )
 
 const (
@@ -33,6 +38,9 @@ const (
 	EnvRootUser      = "POSTGRES_USER"
 	EnvRootPassword  = "POSTGRES_PASSWORD"
 
+	// configSection is the config file section shared by all PostgreSQL engines.
+	configSection = "postgresql"
+
 	DefaultUrl                  = "user=postgres password=docker host=localhost port=5432 dbname=postgres pool_min_conns=1 pool_max_conns=10"
 	DefaultMaxConnectionTimeout = "5"
 )
@@ -47,13 +55,32 @@ type Config struct {
 	maxConnections int32
 	minConnections int32
 	connectTimeout string
+	sslMode        string
+	sslRootCert    string
 	pgxConfig      *pgxpool.Config
+	userEnv        string
+	passwordEnv    string
+	// instance is the instance whose envs and credentials are looked up.
+	instance string
+
+	// credentialLock guards the credentials which are reloaded on rotation.
+	credentialLock sync.RWMutex
 }
 
 var config *Config
 
 func NewConfig() (*Config, error) {
-	config = &Config{}
+	return NewConfigWithEnvs(EnvRootUser, EnvRootPassword)
+}
+
+// NewConfigWithEnvs reads the credentials from the envs of an engine speaking the
+// PostgreSQL protocol, e.g. GS_USERNAME of openGauss, before the envs of PostgreSQL.
+func NewConfigWithEnvs(userEnv, passwordEnv string) (*Config, error) {
+	config = &Config{
+		userEnv:     userEnv,
+		passwordEnv: passwordEnv,
+		instance:    utilconfig.CurrentInstance(),
+	}
 
 	poolConfig, err := pgxpool.ParseConfig(DefaultUrl)
 	if err != nil {
@@ -69,20 +96,105 @@ func NewConfig() (*Config, error) {
 	config.minConnections = poolConfig.MinConns
 	config.connectTimeout = DefaultMaxConnectionTimeout
 
-	if viper.IsSet(EnvRootUser) {
-		config.username = viper.GetString(EnvRootUser)
-	}
-	if viper.IsSet(EnvRootPassword) {
-		config.password = viper.GetString(EnvRootPassword)
+	engineConfig, err := utilconfig.GetInstanceEngineConfig(config.instance, configSection)
+	if err != nil {
+		return nil, err
 	}
+	config.mergeEngineConfig(engineConfig)
+	config.setCredentials(engineConfig)
 
 	config.url = config.GetConnectURLWithHost(config.host)
-	pgxConfig, _ := pgxpool.ParseConfig(config.url)
+	pgxConfig, err := pgxpool.ParseConfig(config.url)
+	if err != nil {
+		return nil, errors.Errorf("error parsing DB connection url: %v", err)
+	}
+	pgxConfig.MaxConns = config.maxConnections
+	pgxConfig.MinConns = config.minConnections
+	// new connections always pick up the latest credentials, so that the
+	// connection pool keeps working after the secrets are rotated. The config is
+	// captured rather than the package level one, which is replaced by NewConfig.
+	current := config
+	pgxConfig.BeforeConnect = func(_ context.Context, connConfig *pgx.ConnConfig) error {
+		connConfig.User, connConfig.Password = current.GetCredentials()
+		return nil
+	}
 	config.pgxConfig = pgxConfig
 
 	return config, nil
 }
 
+func (config *Config) mergeEngineConfig(engineConfig *utilconfig.EngineConfig) {
+	if engineConfig.Host != "" {
+		config.host = engineConfig.Host
+	}
+	if engineConfig.Port != 0 {
+		config.port = engineConfig.Port
+	}
+	if engineConfig.Database != "" {
+		config.database = engineConfig.Database
+	}
+	if engineConfig.MaxOpenConns != 0 {
+		config.maxConnections = int32(engineConfig.MaxOpenConns)
+	}
+	if engineConfig.MaxIdleConns != 0 {
+		config.minConnections = int32(engineConfig.MaxIdleConns)
+	}
+	if engineConfig.ConnectTimeout != 0 {
+		config.connectTimeout = fmt.Sprintf("%d", int(engineConfig.ConnectTimeout.Seconds()))
+	}
+	if engineConfig.TLS.Enabled {
+		switch {
+		case engineConfig.TLS.CAFile != "":
+			config.sslMode = "verify-full"
+			config.sslRootCert = engineConfig.TLS.CAFile
+		default:
+			config.sslMode = "require"
+		}
+	}
+}
+
+// ReloadCredentials reads the credentials again, it's called when the mounted secrets are rotated.
+func (config *Config) ReloadCredentials() error {
+	engineConfig, err := utilconfig.GetInstanceEngineConfig(config.instance, configSection)
+	if err != nil {
+		return err
+	}
+	config.setCredentials(engineConfig)
+	return nil
+}
+
+// GetCredentials returns the current username and password to connect to PostgreSQL.
+func (config *Config) GetCredentials() (string, string) {
+	config.credentialLock.RLock()
+	defer config.credentialLock.RUnlock()
+	return config.username, config.password
+}
+
+func (config *Config) setCredentials(engineConfig *utilconfig.EngineConfig) {
+	config.credentialLock.Lock()
+	defer config.credentialLock.Unlock()
+
+	// credentials from env take precedence over the config file
+	if engineConfig.Username != "" {
+		config.username = engineConfig.Username
+	}
+	if engineConfig.Password != "" {
+		config.password = engineConfig.Password
+	}
+	if username, ok := utilconfig.LookupInstanceCredential(config.instance, constant.ConfigKeyUserName, config.userEnv, EnvRootUser); ok {
+		config.username = username
+	}
+	if password, ok := utilconfig.LookupInstanceCredential(config.instance, constant.ConfigKeyPassword, config.passwordEnv, EnvRootPassword); ok {
+		config.password = password
+	}
+}
+
+// PoolConfig returns the config of the connection pool, to be customized before the
+// pool is created, e.g. the authentication of openGauss.
+func (config *Config) PoolConfig() *pgxpool.Config {
+	return config.pgxConfig
+}
+
 func (config *Config) GetDBPort() int {
 	if config.port == 0 {
 		return DefaultPort
@@ -92,6 +204,14 @@ func (config *Config) GetDBPort() int {
 }
 
 func (config *Config) GetConnectURLWithHost(host string) string {
-	return fmt.Sprintf("user=%s password=%s host=%s port=%d dbname=%s",
-		config.username, config.password, host, config.port, config.database)
+	username, password := config.GetCredentials()
+	url := fmt.Sprintf("user=%s password=%s host=%s port=%d dbname=%s connect_timeout=%s",
+		username, password, host, config.port, config.database, config.connectTimeout)
+	if config.sslMode != "" {
+		url += " sslmode=" + config.sslMode
+	}
+	if config.sslRootCert != "" {
+		url += " sslrootcert=" + config.sslRootCert
+	}
+	return url
 }
@@ -0,0 +1,117 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/configfile"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const (
+	configFileSQL = "select current_setting('config_file') as config_file, current_setting('data_directory') as data_directory"
+
+	settingsSQL = "select name, setting, unit from pg_settings"
+
+	autoConfFile = "postgresql.auto.conf"
+)
+
+var _ engines.ConfigDriftChecker = &Manager{}
+
+// unitSizes are the memory units in bytes and the time units in milliseconds.
+var unitSizes = map[string]float64{
+	"b":   1,
+	"kb":  1 << 10,
+	"mb":  1 << 20,
+	"gb":  1 << 30,
+	"tb":  1 << 40,
+	"us":  0.001,
+	"ms":  1,
+	"s":   1000,
+	"min": 60 * 1000,
+	"h":   60 * 60 * 1000,
+	"d":   24 * 60 * 60 * 1000,
+}
+
+// CheckConfigDrift compares postgresql.conf and postgresql.auto.conf with pg_settings
+// by default, the values with units are compared in the units of pg_settings.
+func (mgr *Manager) CheckConfigDrift(ctx context.Context, file string) (*models.ConfigDriftReport, error) {
+	files := []string{file}
+	if file == "" {
+		rows, err := mgr.queryRows(ctx, configFileSQL)
+		if err != nil {
+			return nil, err
+		}
+		files = []string{cast.ToString(rows[0]["config_file"])}
+		autoConf := filepath.Join(cast.ToString(rows[0]["data_directory"]), autoConfFile)
+		if _, err = os.Stat(autoConf); err == nil {
+			files = append(files, autoConf)
+		}
+	}
+	config, err := configfile.ParsePostgresqlConf(files...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := mgr.queryRows(ctx, settingsSQL)
+	if err != nil {
+		return nil, err
+	}
+	live := map[string]string{}
+	settingUnits := map[string]string{}
+	for _, row := range rows {
+		name := cast.ToString(row["name"])
+		live[name] = cast.ToString(row["setting"])
+		settingUnits[name] = cast.ToString(row["unit"])
+	}
+
+	return configfile.Diff(config, live, func(name, fileValue, liveValue string) bool {
+		return equalSetting(fileValue, liveValue, settingUnits[name])
+	}), nil
+}
+
+// equalSetting converts the value in the file to the unit of the setting, e.g.
+// 128MB is 16384 in 8kB, a value without a unit is in the unit of the setting.
+func equalSetting(fileValue, liveValue, unit string) bool {
+	if unit != "" {
+		if _, err := strconv.Atoi(unit[:1]); err != nil {
+			unit = "1" + unit
+		}
+		base, ok := configfile.ParseSize(unit, unitSizes)
+		if ok {
+			value, ok := configfile.ParseSize(fileValue, nil)
+			if !ok {
+				value, ok = configfile.ParseSize(fileValue, unitSizes)
+				value /= base
+			}
+			if live, err := strconv.ParseFloat(liveValue, 64); ok && err == nil {
+				return value == live
+			}
+		}
+	}
+	return configfile.EqualValues(fileValue, liveValue, nil)
+}
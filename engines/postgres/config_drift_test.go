@@ -0,0 +1,71 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestCheckConfigDrift(t *testing.T) {
+	manager, mock, _ := MockDatabase(t)
+	defer mock.Close()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "postgresql.conf")
+	assert.Nil(t, os.WriteFile(file, []byte("shared_buffers = 256MB\nwork_mem = '4MB'\nlog_min_duration_statement = 1s\nfsync = on\n"), 0644))
+	autoConf := filepath.Join(dir, autoConfFile)
+	assert.Nil(t, os.WriteFile(autoConf, []byte("work_mem = '64MB'\n"), 0644))
+
+	mock.ExpectQuery(regexp.QuoteMeta(configFileSQL)).
+		WillReturnRows(pgxmock.NewRows([]string{"config_file", "data_directory"}).AddRow(file, dir))
+	mock.ExpectQuery(regexp.QuoteMeta(settingsSQL)).
+		WillReturnRows(pgxmock.NewRows([]string{"name", "setting", "unit"}).
+			AddRow("shared_buffers", "16384", "8kB").
+			AddRow("work_mem", "65536", "kB").
+			AddRow("log_min_duration_statement", "1000", "ms").
+			AddRow("fsync", "on", nil))
+
+	report, err := manager.CheckConfigDrift(context.TODO(), "")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{file, autoConf}, report.Files)
+	assert.Equal(t, []models.ParameterDrift{{Name: "shared_buffers", FileValue: "256MB", LiveValue: "16384", File: file}}, report.Drifts)
+	assert.Empty(t, report.Unknown)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestEqualSetting(t *testing.T) {
+	assert.True(t, equalSetting("128MB", "16384", "8kB"))
+	assert.True(t, equalSetting("16384", "16384", "8kB"))
+	assert.True(t, equalSetting("5min", "300", "s"))
+	assert.False(t, equalSetting("4MB", "65536", "kB"))
+	assert.True(t, equalSetting("off", "off", ""))
+	assert.True(t, equalSetting("Replica", "replica", ""))
+}
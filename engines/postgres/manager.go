@@ -21,6 +21,7 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pkg/errors"
@@ -28,6 +29,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/apecloud/dbctl/engines"
+	utilconfig "github.com/apecloud/dbctl/util/config"
 )
 
 type Manager struct {
@@ -38,12 +40,17 @@ type Manager struct {
 }
 
 func NewManager() (engines.DBManager, error) {
-	logger := ctrl.Log.WithName("PostgreSQL")
 	config, err := NewConfig()
 	if err != nil {
 		return nil, err
 	}
+	return NewManagerWithConfig(config)
+}
 
+// NewManagerWithConfig returns the manager connecting with the config, which is
+// customized by the engines speaking the PostgreSQL protocol.
+func NewManagerWithConfig(config *Config) (engines.DBManager, error) {
+	logger := ctrl.Log.WithName("PostgreSQL")
 	pool, err := pgxpool.NewWithConfig(context.Background(), config.pgxConfig)
 	if err != nil {
 		return nil, errors.Errorf("unable to ping the DB: %v", err)
@@ -61,9 +68,43 @@ func NewManager() (engines.DBManager, error) {
 		MajorVersion:  viper.GetInt(PGMAJOR),
 	}
 
+	utilconfig.OnCredentialsChange(mgr.reloadCredentials)
 	return mgr, nil
 }
 
+// reloadCredentials resets the connection pool after the credentials are reloaded,
+// idle connections are closed at once, and the acquired ones are closed when released.
+func (mgr *Manager) reloadCredentials() {
+	if err := mgr.Config.ReloadCredentials(); err != nil {
+		mgr.Logger.Info("reload credentials failed", "error", err.Error())
+		return
+	}
+	mgr.Pool.Reset()
+	mgr.Logger.Info("credentials reloaded")
+}
+
+func (mgr *Manager) IsDBStartupReady() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if mgr.DBStartupReady {
+		return true
+	}
+
+	if !mgr.IsPgReady(ctx) {
+		return false
+	}
+
+	mgr.DBStartupReady = true
+	mgr.Logger.Info("DB startup ready")
+	return true
+}
+
+var _ engines.Pinger = &Manager{}
+
+func (mgr *Manager) Ping(ctx context.Context) error {
+	return mgr.Pool.Ping(ctx)
+}
+
 func (mgr *Manager) IsPgReady(ctx context.Context) bool {
 	err := mgr.Pool.Ping(ctx)
 	if err != nil {
@@ -0,0 +1,154 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const (
+	parameterContextSQL = "select context from pg_settings where name = '%s'"
+
+	// the contexts of pg_settings
+	contextInternal   = "internal"
+	contextPostmaster = "postmaster"
+)
+
+// listParameters are the GUC_LIST_QUOTE parameters, each element of them is
+// quoted on its own, or the whole value is taken as a single element.
+var listParameters = map[string]bool{
+	"local_preload_libraries":   true,
+	"search_path":               true,
+	"session_preload_libraries": true,
+	"shared_preload_libraries":  true,
+	"temp_tablespaces":          true,
+	"unix_socket_directories":   true,
+}
+
+var _ engines.Reconfigurer = &Manager{}
+
+// ClassifyParameter tells by the context of the parameter, a postmaster parameter
+// is static, and an internal one can't be changed.
+func (mgr *Manager) ClassifyParameter(ctx context.Context, name string) (models.ParameterKind, error) {
+	name = strings.ToLower(name)
+	if !engines.IsValidParameterName(name) {
+		return "", models.ErrUnknownParameter
+	}
+	rows, err := mgr.queryRows(ctx, fmt.Sprintf(parameterContextSQL, name))
+	if err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", models.ErrUnknownParameter
+	}
+	switch cast.ToString(rows[0]["context"]) {
+	case contextInternal:
+		return "", models.ErrReadOnlyParameter
+	case contextPostmaster:
+		return models.ParameterStatic, nil
+	default:
+		return models.ParameterDynamic, nil
+	}
+}
+
+// SetParameter writes the parameter to postgresql.auto.conf, and reloads it. A
+// postmaster parameter is written as well, and taken at the next start.
+func (mgr *Manager) SetParameter(ctx context.Context, name, value string) error {
+	name = strings.ToLower(name)
+	if !engines.IsValidParameterName(name) {
+		return models.ErrUnknownParameter
+	}
+	sql := fmt.Sprintf("alter system set %s = %s", name, quoteParameterValue(name, value))
+	if _, err := mgr.Exec(ctx, sql); err != nil {
+		return err
+	}
+	return mgr.reloadConf(ctx)
+}
+
+// quoteParameterValue quotes the value as a string literal. The value of a list
+// parameter is split by the commas out of the double quotes, and each element is
+// quoted, postgres quotes them as identifiers again, so the double quotes around
+// an element are removed. An empty list is set to the default.
+func quoteParameterValue(name, value string) string {
+	if !listParameters[name] {
+		return quoteLiteral(value)
+	}
+	elements := splitListValue(value)
+	if len(elements) == 0 {
+		return "default"
+	}
+	for i, element := range elements {
+		elements[i] = quoteLiteral(element)
+	}
+	return strings.Join(elements, ", ")
+}
+
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// splitListValue splits the value like SplitIdentifierString of postgres, the
+// double quotes are removed and the doubled ones are unescaped.
+func splitListValue(value string) []string {
+	var elements []string
+	var element strings.Builder
+	quoted, inQuotes := false, false
+	flush := func() {
+		e := element.String()
+		if !quoted {
+			e = strings.TrimSpace(e)
+		}
+		if e != "" || quoted {
+			elements = append(elements, e)
+		}
+		element.Reset()
+		quoted = false
+	}
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '"' && inQuotes && i+1 < len(value) && value[i+1] == '"':
+			element.WriteByte('"')
+			i++
+		case c == '"':
+			if !inQuotes {
+				// drop the spaces before the opening quote
+				element.Reset()
+			}
+			inQuotes = !inQuotes
+			quoted = true
+		case c == ',' && !inQuotes:
+			flush()
+		case quoted && !inQuotes:
+			// the spaces after the closing quote
+		default:
+			element.WriteByte(c)
+		}
+	}
+	flush()
+	return elements
+}
@@ -0,0 +1,112 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestReconfigure(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock, _ := MockDatabase(t)
+	defer mock.Close()
+
+	t.Run("classify", func(t *testing.T) {
+		for name, pgContext := range map[string]string{"work_mem": "user", "shared_buffers": contextPostmaster, "block_size": contextInternal} {
+			mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(parameterContextSQL, name))).
+				WillReturnRows(pgxmock.NewRows([]string{"context"}).AddRow(pgContext))
+		}
+		mock.MatchExpectationsInOrder(false)
+		defer mock.MatchExpectationsInOrder(true)
+
+		kind, err := manager.ClassifyParameter(ctx, "work_mem")
+		assert.Nil(t, err)
+		assert.Equal(t, models.ParameterDynamic, kind)
+
+		kind, err = manager.ClassifyParameter(ctx, "shared_buffers")
+		assert.Nil(t, err)
+		assert.Equal(t, models.ParameterStatic, kind)
+
+		_, err = manager.ClassifyParameter(ctx, "block_size")
+		assert.ErrorIs(t, err, models.ErrReadOnlyParameter)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(parameterContextSQL, "foo"))).
+			WillReturnRows(pgxmock.NewRows([]string{"context"}))
+
+		_, err := manager.ClassifyParameter(ctx, "foo")
+		assert.ErrorIs(t, err, models.ErrUnknownParameter)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("alter system set work_mem = '64MB'")).
+			WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+		mock.ExpectQuery(regexp.QuoteMeta("select pg_reload_conf()")).
+			WillReturnRows(pgxmock.NewRows([]string{"pg_reload_conf"}).AddRow(true))
+
+		assert.Nil(t, manager.SetParameter(ctx, "work_mem", "64MB"))
+
+		// the static parameter is written for the next start
+		mock.ExpectExec(regexp.QuoteMeta("alter system set shared_buffers = '1GB'")).
+			WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+		mock.ExpectQuery(regexp.QuoteMeta("select pg_reload_conf()")).
+			WillReturnRows(pgxmock.NewRows([]string{"pg_reload_conf"}).AddRow(true))
+		assert.Nil(t, manager.SetParameter(ctx, "shared_buffers", "1GB"))
+
+		// each element of the list parameter is quoted
+		mock.ExpectExec(regexp.QuoteMeta(`alter system set shared_preload_libraries = 'pg_stat_statements', 'auto_explain'`)).
+			WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+		mock.ExpectQuery(regexp.QuoteMeta("select pg_reload_conf()")).
+			WillReturnRows(pgxmock.NewRows([]string{"pg_reload_conf"}).AddRow(true))
+		assert.Nil(t, manager.SetParameter(ctx, "shared_preload_libraries", "pg_stat_statements, auto_explain"))
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestQuoteParameterValue(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"work_mem", "64MB", "'64MB'"},
+		{"application_name", "a, b", "'a, b'"},
+		{"log_line_prefix", "it's", "'it''s'"},
+		{"shared_preload_libraries", "pg_stat_statements,auto_explain", "'pg_stat_statements', 'auto_explain'"},
+		{"search_path", `"$user", public`, `'$user', 'public'`},
+		{"search_path", `"a,""b""" , c`, `'a,"b"', 'c'`},
+		{"temp_tablespaces", "", "default"},
+		{"search_path", `""`, "''"},
+	} {
+		assert.Equal(t, tc.expected, quoteParameterValue(tc.name, tc.value), tc.value)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const (
+	currentLSNSQL = `select pg_is_in_recovery() as in_recovery,
+case when pg_is_in_recovery() then pg_last_wal_replay_lsn() else pg_current_wal_lsn() end::text as applied_lsn;`
+
+	walReceiverSQL = `select status, sender_host, sender_port, latest_end_lsn::text as received_lsn,
+pg_wal_lsn_diff(latest_end_lsn, pg_last_wal_replay_lsn())::bigint as lag_bytes,
+case when latest_end_lsn = pg_last_wal_replay_lsn() then 0
+else extract(epoch from now() - pg_last_xact_replay_timestamp())::bigint end as lag_seconds
+from pg_stat_wal_receiver;`
+)
+
+// GetReplicationStatus reads the WAL receiver of a standby, the lag in seconds is
+// the age of the last replayed transaction unless all the received WAL is replayed.
+func (mgr *Manager) GetReplicationStatus(ctx context.Context) (*models.ReplicationStatus, error) {
+	rows, err := mgr.queryRows(ctx, currentLSNSQL)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no current lsn returned")
+	}
+
+	status := &models.ReplicationStatus{
+		IsReplica:       cast.ToBool(rows[0]["in_recovery"]),
+		AppliedPosition: cast.ToString(rows[0]["applied_lsn"]),
+	}
+	if !status.IsReplica {
+		return status, nil
+	}
+
+	rows, err = mgr.queryRows(ctx, walReceiverSQL)
+	if err != nil {
+		return nil, err
+	}
+	// there is no WAL receiver while restoring from the archive or reconnecting
+	if len(rows) == 0 {
+		return status, nil
+	}
+
+	receiver := rows[0]
+	status.UpstreamHost = cast.ToString(receiver["sender_host"])
+	status.UpstreamPort = cast.ToInt(receiver["sender_port"])
+	status.IOThreadState = cast.ToString(receiver["status"])
+	status.ReceivedPosition = cast.ToString(receiver["received_lsn"])
+	if receiver["lag_bytes"] != nil {
+		lagBytes := cast.ToInt64(receiver["lag_bytes"])
+		status.LagBytes = &lagBytes
+	}
+	if receiver["lag_seconds"] != nil {
+		lagSeconds := cast.ToInt64(receiver["lag_seconds"])
+		status.LagSeconds = &lagSeconds
+	}
+	return status, nil
+}
+
+func (mgr *Manager) queryRows(ctx context.Context, sql string) ([]map[string]any, error) {
+	resp, err := mgr.Query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]any
+	if err = json.Unmarshal(resp, &rows); err != nil {
+		return nil, errors.Wrap(err, "json unmarshal failed")
+	}
+	return rows, nil
+}
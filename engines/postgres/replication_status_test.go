@@ -0,0 +1,80 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReplicationStatus(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock, _ := MockDatabase(t)
+	defer mock.Close()
+
+	t.Run("primary", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(currentLSNSQL)).
+			WillReturnRows(pgxmock.NewRows([]string{"in_recovery", "applied_lsn"}).AddRow(false, "0/3000148"))
+
+		status, err := manager.GetReplicationStatus(ctx)
+		assert.Nil(t, err)
+		assert.False(t, status.IsReplica)
+		assert.Equal(t, "0/3000148", status.AppliedPosition)
+	})
+
+	t.Run("standby", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(currentLSNSQL)).
+			WillReturnRows(pgxmock.NewRows([]string{"in_recovery", "applied_lsn"}).AddRow(true, "0/3000060"))
+		mock.ExpectQuery(regexp.QuoteMeta(walReceiverSQL)).
+			WillReturnRows(pgxmock.NewRows([]string{"status", "sender_host", "sender_port", "received_lsn", "lag_bytes", "lag_seconds"}).
+				AddRow("streaming", "pg-0", int32(5432), "0/3000148", int64(232), int64(3)))
+
+		status, err := manager.GetReplicationStatus(ctx)
+		assert.Nil(t, err)
+		assert.True(t, status.IsReplica)
+		assert.Equal(t, "pg-0", status.UpstreamHost)
+		assert.Equal(t, 5432, status.UpstreamPort)
+		assert.Equal(t, "streaming", status.IOThreadState)
+		assert.Equal(t, "0/3000148", status.ReceivedPosition)
+		assert.Equal(t, "0/3000060", status.AppliedPosition)
+		assert.Equal(t, int64(232), *status.LagBytes)
+		assert.Equal(t, int64(3), *status.LagSeconds)
+	})
+
+	t.Run("standby without wal receiver", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(currentLSNSQL)).
+			WillReturnRows(pgxmock.NewRows([]string{"in_recovery", "applied_lsn"}).AddRow(true, "0/3000060"))
+		mock.ExpectQuery(regexp.QuoteMeta(walReceiverSQL)).
+			WillReturnRows(pgxmock.NewRows([]string{"status", "sender_host", "sender_port", "received_lsn", "lag_bytes", "lag_seconds"}))
+
+		status, err := manager.GetReplicationStatus(ctx)
+		assert.Nil(t, err)
+		assert.True(t, status.IsReplica)
+		assert.Nil(t, status.LagBytes)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// listSessionsSQL lists the client backends, the duration is of the running
+// query, or of the last query for an idle session.
+const listSessionsSQL = `select pid, usename, datname, coalesce(host(client_addr) || ':' || client_port, '') as client, state,
+extract(epoch from now() - coalesce(query_start, backend_start))::float8 as duration, query
+from pg_stat_activity where backend_type = 'client backend' and pid <> pg_backend_pid()`
+
+const terminateBackendSQL = "select pg_terminate_backend(%d)"
+
+var _ engines.SessionManager = &Manager{}
+
+func (mgr *Manager) ListSessions(ctx context.Context) ([]models.Session, error) {
+	rows, err := mgr.queryRows(ctx, listSessionsSQL)
+	if err != nil {
+		return nil, errors.Wrap(err, "query pg_stat_activity failed")
+	}
+
+	sessions := make([]models.Session, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, models.Session{
+			ID:              cast.ToString(row["pid"]),
+			User:            cast.ToString(row["usename"]),
+			Database:        cast.ToString(row["datname"]),
+			Client:          cast.ToString(row["client"]),
+			State:           cast.ToString(row["state"]),
+			DurationSeconds: cast.ToFloat64(row["duration"]),
+			Query:           cast.ToString(row["query"]),
+		})
+	}
+	return sessions, nil
+}
+
+func (mgr *Manager) KillSession(ctx context.Context, id string) error {
+	pid, err := strconv.ParseInt(id, 10, 32)
+	if err != nil {
+		return errors.Errorf("invalid session id %s", id)
+	}
+	rows, err := mgr.queryRows(ctx, fmt.Sprintf(terminateBackendSQL, pid))
+	if err != nil {
+		return errors.Wrapf(err, "terminate backend %s failed", id)
+	}
+	if len(rows) == 0 || !cast.ToBool(rows[0]["pg_terminate_backend"]) {
+		return errors.Errorf("backend %s not found", id)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestSessions(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock, _ := MockDatabase(t)
+	defer mock.Close()
+
+	t.Run("list", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(listSessionsSQL)).
+			WillReturnRows(pgxmock.NewRows([]string{"pid", "usename", "datname", "client", "state", "duration", "query"}).
+				AddRow(int32(4321), "app", "orders", "10.0.0.3:51234", "active", 95.5, "select pg_sleep(100)").
+				AddRow(int32(4322), "app", "orders", "", "idle", 3.0, "commit"))
+
+		sessions, err := manager.ListSessions(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []models.Session{
+			{ID: "4321", User: "app", Database: "orders", Client: "10.0.0.3:51234", State: "active", DurationSeconds: 95.5, Query: "select pg_sleep(100)"},
+			{ID: "4322", User: "app", Database: "orders", State: "idle", DurationSeconds: 3, Query: "commit"},
+		}, sessions)
+	})
+
+	t.Run("kill", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(terminateBackendSQL, 4321))).
+			WillReturnRows(pgxmock.NewRows([]string{"pg_terminate_backend"}).AddRow(true))
+		mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(terminateBackendSQL, 4323))).
+			WillReturnRows(pgxmock.NewRows([]string{"pg_terminate_backend"}).AddRow(false))
+
+		assert.Nil(t, manager.KillSession(ctx, "4321"))
+		assert.NotNil(t, manager.KillSession(ctx, "4323"))
+		assert.NotNil(t, manager.KillSession(ctx, "x"))
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
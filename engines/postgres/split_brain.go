@@ -0,0 +1,124 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package postgres
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const (
+	memberViewSQL = `select pg_is_in_recovery() as in_recovery,
+current_setting('default_transaction_read_only') = 'on' as read_only,
+(select timeline_id from pg_control_checkpoint()) as timeline,
+(select sender_host from pg_stat_wal_receiver) as sender_host;`
+
+	fenceSQL   = "alter system set default_transaction_read_only = on"
+	unfenceSQL = "alter system reset default_transaction_read_only"
+
+	// fenceMarkerSQL marks the fencing as set by dbctl, so that a read-only server
+	// set by the users is never unfenced.
+	fenceMarkerSQL   = "alter system set dbctl.fenced = on"
+	unfenceMarkerSQL = "alter system reset dbctl.fenced"
+
+	// fencedSQL reads the marker from postgresql.auto.conf, which is kept over the
+	// restarts of the server.
+	fencedSQL = `select exists(select 1 from pg_file_settings
+where name = 'dbctl.fenced' and setting = 'on' and sourcefile like '%postgresql.auto.conf') as fenced;`
+)
+
+// GetMemberView connects to the member, the timeline is the term since a promotion
+// switches to a new timeline.
+func (mgr *Manager) GetMemberView(ctx context.Context, address string) (*models.MemberView, error) {
+	resp, err := mgr.QueryWithHost(ctx, memberViewSQL, memberHost(address))
+	if err != nil {
+		return nil, err
+	}
+	rows, err := ParseQuery(string(resp))
+	if err != nil {
+		return nil, err
+	}
+
+	row := rows[0]
+	view := &models.MemberView{
+		Address: address,
+		Role:    models.PRIMARY,
+		Term:    cast.ToInt64(row["timeline"]),
+	}
+	if cast.ToBool(row["in_recovery"]) {
+		view.Role = models.SECONDARY
+		view.Leader = cast.ToString(row["sender_host"])
+		return view, nil
+	}
+	view.Writable = !cast.ToBool(row["read_only"])
+	return view, nil
+}
+
+// FenceMember makes the new transactions of the member read-only, the sessions
+// may still turn it off, so the stale primary should be demoted afterwards. The
+// fencing is written to postgresql.auto.conf with the marker of dbctl, so it's
+// kept over the restarts until the member is promoted by liftFence.
+func (mgr *Manager) FenceMember(ctx context.Context, address string) error {
+	host := memberHost(address)
+	for _, sql := range []string{fenceSQL, fenceMarkerSQL} {
+		if _, err := mgr.ExecWithHost(ctx, sql, host); err != nil {
+			return err
+		}
+	}
+	if _, err := mgr.QueryWithHost(ctx, "select pg_reload_conf()", host); err != nil {
+		return errors.Wrap(err, "reload conf failed")
+	}
+	return nil
+}
+
+// liftFence resets the fencing of the local server if it's set by dbctl, the
+// default_transaction_read_only set by the users is kept.
+func (mgr *Manager) liftFence(ctx context.Context) error {
+	resp, err := mgr.Query(ctx, fencedSQL)
+	if err != nil {
+		return errors.Wrap(err, "check the fencing failed")
+	}
+	rows, err := ParseQuery(string(resp))
+	if err != nil {
+		return err
+	}
+	if !cast.ToBool(rows[0]["fenced"]) {
+		return nil
+	}
+	for _, sql := range []string{unfenceSQL, unfenceMarkerSQL} {
+		if _, err = mgr.Exec(ctx, sql); err != nil {
+			return err
+		}
+	}
+	return mgr.reloadConf(ctx)
+}
+
+// memberHost strips the port of the address, the members listen on the same port.
+func memberHost(address string) string {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
@@ -0,0 +1,109 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestGetMemberView(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock, _ := MockDatabase(t)
+	defer mock.Close()
+	columns := []string{"in_recovery", "read_only", "timeline", "sender_host"}
+
+	t.Run("primary", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(memberViewSQL)).
+			WillReturnRows(pgxmock.NewRows(columns).AddRow(false, false, int32(3), nil))
+
+		view, err := manager.GetMemberView(ctx, "")
+		assert.Nil(t, err)
+		assert.Equal(t, models.PRIMARY, view.Role)
+		assert.True(t, view.Writable)
+		assert.Equal(t, int64(3), view.Term)
+		assert.Empty(t, view.Leader)
+	})
+
+	t.Run("fenced primary", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(memberViewSQL)).
+			WillReturnRows(pgxmock.NewRows(columns).AddRow(false, true, int32(3), nil))
+
+		view, err := manager.GetMemberView(ctx, "")
+		assert.Nil(t, err)
+		assert.False(t, view.Writable)
+	})
+
+	t.Run("standby", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(memberViewSQL)).
+			WillReturnRows(pgxmock.NewRows(columns).AddRow(true, false, int32(3), "pg-0.pg-headless"))
+
+		view, err := manager.GetMemberView(ctx, "")
+		assert.Nil(t, err)
+		assert.Equal(t, models.SECONDARY, view.Role)
+		assert.False(t, view.Writable)
+		assert.Equal(t, "pg-0.pg-headless", view.Leader)
+	})
+
+	t.Run("fence", func(t *testing.T) {
+		mock.ExpectExec(fenceSQL).WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+		mock.ExpectExec(fenceMarkerSQL).WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+		mock.ExpectQuery(regexp.QuoteMeta("select pg_reload_conf()")).
+			WillReturnRows(pgxmock.NewRows([]string{"pg_reload_conf"}).AddRow(true))
+
+		assert.Nil(t, manager.FenceMember(ctx, ""))
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestLiftFence(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock, _ := MockDatabase(t)
+	defer mock.Close()
+
+	t.Run("not fenced by dbctl", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(fencedSQL)).WillReturnRows(pgxmock.NewRows([]string{"fenced"}).AddRow(false))
+
+		assert.Nil(t, manager.liftFence(ctx))
+	})
+
+	t.Run("fenced by dbctl", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(fencedSQL)).WillReturnRows(pgxmock.NewRows([]string{"fenced"}).AddRow(true))
+		mock.ExpectExec(unfenceSQL).WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+		mock.ExpectExec(unfenceMarkerSQL).WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+		mock.ExpectQuery(regexp.QuoteMeta("select pg_reload_conf()")).
+			WillReturnRows(pgxmock.NewRows([]string{"pg_reload_conf"}).AddRow(true))
+
+		assert.Nil(t, manager.liftFence(ctx))
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const (
+	inRecoverySQL = "select pg_is_in_recovery(), current_setting('data_directory') as data_directory"
+
+	promoteSQL = "select pg_promote()"
+
+	standbySignal = "standby.signal"
+	postmasterPID = "postmaster.pid"
+)
+
+var _ engines.Switcher = &Manager{}
+
+// pgCtl restarts the local server to demote it, it's a variable for testing.
+var pgCtl = func(ctx context.Context, dataDir string) error {
+	out, err := exec.CommandContext(ctx, "pg_ctl", "restart", "-D", dataDir, "-m", "fast", "-w").CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "pg_ctl restart failed: %s", out)
+	}
+	return nil
+}
+
+// checkLocalServer tells whether dbctl runs in the container of the server, i.e.
+// it has pg_ctl and sees the postmaster process. As a sidecar it has neither, and
+// it can't restart the server, it's a variable for testing.
+var checkLocalServer = func(dataDir string) error {
+	if _, err := exec.LookPath("pg_ctl"); err != nil {
+		return errors.Wrap(models.ErrNotImplemented, "pg_ctl is not found, dbctl doesn't run in the container of the server")
+	}
+	data, err := os.ReadFile(filepath.Join(dataDir, postmasterPID))
+	if err != nil {
+		return errors.Wrapf(models.ErrNotImplemented, "read %s failed, dbctl doesn't run in the container of the server", postmasterPID)
+	}
+	pid, _, _ := strings.Cut(string(data), "\n")
+	if _, err = os.Stat(filepath.Join("/proc", strings.TrimSpace(pid))); err != nil {
+		return errors.Wrapf(models.ErrNotImplemented, "postmaster %s is not visible, dbctl doesn't share the process namespace of the server", pid)
+	}
+	return nil
+}
+
+// Promote promotes the standby and lifts the fencing set by dbctl, a primary is
+// only unfenced. It's called by the HA controller once the member holds the
+// leadership.
+func (mgr *Manager) Promote(ctx context.Context) error {
+	inRecovery, _, err := mgr.isInRecovery(ctx)
+	if err != nil {
+		return err
+	}
+	if inRecovery {
+		if _, err = mgr.Query(ctx, promoteSQL); err != nil {
+			return errors.Wrap(err, "promote failed")
+		}
+	}
+	return mgr.liftFence(ctx)
+}
+
+// Demote points the primary_conninfo to the leader, which is reloaded by a standby.
+// A primary is fenced and restarted as a standby, it's not rewound, so a primary
+// which has diverged from the leader has to be rebuilt. The restart is only done
+// if dbctl runs in the container of the server, otherwise the primary is left
+// fenced with the primary_conninfo set, and ErrNotImplemented is returned.
+func (mgr *Manager) Demote(ctx context.Context, leader string) error {
+	inRecovery, dataDir, err := mgr.isInRecovery(ctx)
+	if err != nil {
+		return err
+	}
+	if !inRecovery {
+		if err = mgr.FenceMember(ctx, ""); err != nil {
+			return err
+		}
+	}
+
+	sql := fmt.Sprintf("alter system set primary_conninfo = '%s'", strings.ReplaceAll(mgr.primaryConnInfo(leader), "'", "''"))
+	if _, err = mgr.Exec(ctx, sql); err != nil {
+		return errors.Wrap(err, "set primary_conninfo failed")
+	}
+	if inRecovery {
+		return mgr.reloadConf(ctx)
+	}
+
+	if err = checkLocalServer(dataDir); err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(dataDir, standbySignal), nil, 0600); err != nil {
+		return errors.Wrap(err, "create standby.signal failed")
+	}
+	return pgCtl(ctx, dataDir)
+}
+
+func (mgr *Manager) isInRecovery(ctx context.Context) (bool, string, error) {
+	resp, err := mgr.Query(ctx, inRecoverySQL)
+	if err != nil {
+		return false, "", err
+	}
+	rows, err := ParseQuery(string(resp))
+	if err != nil {
+		return false, "", err
+	}
+	return cast.ToBool(rows[0]["pg_is_in_recovery"]), cast.ToString(rows[0]["data_directory"]), nil
+}
+
+func (mgr *Manager) reloadConf(ctx context.Context) error {
+	if _, err := mgr.Query(ctx, "select pg_reload_conf()"); err != nil {
+		return errors.Wrap(err, "reload conf failed")
+	}
+	return nil
+}
+
+// primaryConnInfo connects to the leader with the local credentials, the local
+// port is used if the address has no port.
+func (mgr *Manager) primaryConnInfo(leader string) string {
+	host, port, err := net.SplitHostPort(leader)
+	if err != nil {
+		host, port = leader, strconv.Itoa(mgr.Config.GetDBPort())
+	}
+	username, password := mgr.Config.GetCredentials()
+	values := []string{
+		"host=" + connInfoValue(host),
+		"port=" + port,
+		"user=" + connInfoValue(username),
+		"password=" + connInfoValue(password),
+		"application_name=" + connInfoValue(mgr.CurrentMemberName),
+	}
+	return strings.Join(values, " ")
+}
+
+func connInfoValue(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,140 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestSwitchover(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock, _ := MockDatabase(t)
+	defer mock.Close()
+	columns := []string{"pg_is_in_recovery", "data_directory"}
+	dataDir := t.TempDir()
+
+	var restarted string
+	defer func(restart func(context.Context, string) error) {
+		pgCtl = restart
+	}(pgCtl)
+	pgCtl = func(_ context.Context, dir string) error {
+		restarted = dir
+		return nil
+	}
+	defer func(check func(string) error) {
+		checkLocalServer = check
+	}(checkLocalServer)
+	checkLocalServer = func(string) error {
+		return nil
+	}
+
+	t.Run("promote standby", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(inRecoverySQL)).
+			WillReturnRows(pgxmock.NewRows(columns).AddRow(true, dataDir))
+		mock.ExpectQuery(regexp.QuoteMeta(promoteSQL)).
+			WillReturnRows(pgxmock.NewRows([]string{"pg_promote"}).AddRow(true))
+		mock.ExpectQuery(regexp.QuoteMeta(fencedSQL)).WillReturnRows(pgxmock.NewRows([]string{"fenced"}).AddRow(true))
+		mock.ExpectExec(unfenceSQL).WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+		mock.ExpectExec(unfenceMarkerSQL).WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+		mock.ExpectQuery(regexp.QuoteMeta("select pg_reload_conf()")).
+			WillReturnRows(pgxmock.NewRows([]string{"pg_reload_conf"}).AddRow(true))
+
+		assert.Nil(t, manager.Promote(ctx))
+	})
+
+	t.Run("standby follows new leader", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(inRecoverySQL)).
+			WillReturnRows(pgxmock.NewRows(columns).AddRow(true, dataDir))
+		mock.ExpectExec(regexp.QuoteMeta("alter system set primary_conninfo = 'host=''pg-1'' port=5433")).
+			WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+		mock.ExpectQuery(regexp.QuoteMeta("select pg_reload_conf()")).
+			WillReturnRows(pgxmock.NewRows([]string{"pg_reload_conf"}).AddRow(true))
+
+		assert.Nil(t, manager.Demote(ctx, "pg-1:5433"))
+		assert.Empty(t, restarted)
+	})
+
+	t.Run("demote primary", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(inRecoverySQL)).
+			WillReturnRows(pgxmock.NewRows(columns).AddRow(false, dataDir))
+		mock.ExpectExec(fenceSQL).WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+		mock.ExpectExec(fenceMarkerSQL).WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+		mock.ExpectQuery(regexp.QuoteMeta("select pg_reload_conf()")).
+			WillReturnRows(pgxmock.NewRows([]string{"pg_reload_conf"}).AddRow(true))
+		mock.ExpectExec(regexp.QuoteMeta("alter system set primary_conninfo = 'host=''pg-1''")).
+			WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+
+		assert.Nil(t, manager.Demote(ctx, "pg-1"))
+		assert.FileExists(t, filepath.Join(dataDir, standbySignal))
+		assert.Equal(t, dataDir, restarted)
+		_ = os.Remove(filepath.Join(dataDir, standbySignal))
+	})
+
+	t.Run("demote primary from a sidecar", func(t *testing.T) {
+		restarted = ""
+		checkLocalServer = func(string) error {
+			return models.ErrNotImplemented
+		}
+		mock.ExpectQuery(regexp.QuoteMeta(inRecoverySQL)).
+			WillReturnRows(pgxmock.NewRows(columns).AddRow(false, dataDir))
+		mock.ExpectExec(fenceSQL).WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+		mock.ExpectExec(fenceMarkerSQL).WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+		mock.ExpectQuery(regexp.QuoteMeta("select pg_reload_conf()")).
+			WillReturnRows(pgxmock.NewRows([]string{"pg_reload_conf"}).AddRow(true))
+		mock.ExpectExec(regexp.QuoteMeta("alter system set primary_conninfo = 'host=''pg-1''")).
+			WillReturnResult(pgxmock.NewResult("ALTER SYSTEM", 0))
+
+		assert.ErrorIs(t, manager.Demote(ctx, "pg-1"), models.ErrNotImplemented)
+		assert.NoFileExists(t, filepath.Join(dataDir, standbySignal))
+		assert.Empty(t, restarted)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestCheckLocalServer(t *testing.T) {
+	// a sidecar sees neither pg_ctl nor the postmaster of the server
+	assert.ErrorIs(t, checkLocalServer(t.TempDir()), models.ErrNotImplemented)
+}
+
+func TestPrimaryConnInfo(t *testing.T) {
+	manager, mock, _ := MockDatabase(t)
+	defer mock.Close()
+	// the pool may be connecting in the background with the credentials
+	manager.Config.credentialLock.Lock()
+	manager.Config.password = `it's\secret`
+	manager.Config.credentialLock.Unlock()
+
+	connInfo := manager.primaryConnInfo("pg-0.pg-headless")
+	assert.Contains(t, connInfo, "host='pg-0.pg-headless' port=5432")
+	assert.Contains(t, connInfo, `password='it\'s\\secret'`)
+	assert.Contains(t, connInfo, "application_name='test-pod-0'")
+}
@@ -0,0 +1,94 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const replicationSQL = `select application_name, client_addr::text as client_addr, state, sync_state,
+pg_wal_lsn_diff(pg_current_wal_lsn(), replay_lsn)::bigint as lag_bytes,
+extract(epoch from replay_lag)::bigint as lag_seconds
+from pg_stat_replication;`
+
+// GetTopology lists the standbys in pg_stat_replication on a primary, while a
+// standby reports itself and the upstream of its WAL receiver.
+func (mgr *Manager) GetTopology(ctx context.Context) (*models.Topology, error) {
+	status, err := mgr.GetReplicationStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	topology := &models.Topology{}
+	if status.IsReplica {
+		self := models.NewMember("", models.SECONDARY)
+		self.Name = mgr.CurrentMemberName
+		self.Self = true
+		self.LagSeconds = status.LagSeconds
+		self.LagBytes = status.LagBytes
+		topology.Members = append(topology.Members, self)
+		if status.UpstreamHost != "" {
+			upstream := models.NewMember(fmt.Sprintf("%s:%d", status.UpstreamHost, status.UpstreamPort), models.PRIMARY)
+			if status.IOThreadState != "streaming" {
+				upstream.Health = models.HealthUnknown
+			}
+			topology.Members = append(topology.Members, upstream)
+		}
+		return topology, nil
+	}
+
+	self := models.NewMember("", models.PRIMARY)
+	self.Name = mgr.CurrentMemberName
+	self.Self = true
+	topology.Members = append(topology.Members, self)
+
+	rows, err := mgr.queryRows(ctx, replicationSQL)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		member := models.NewMember(cast.ToString(row["client_addr"]), models.SECONDARY)
+		member.Name = cast.ToString(row["application_name"])
+		member.NativeRole = cast.ToString(row["sync_state"])
+		switch cast.ToString(row["state"]) {
+		case "streaming":
+			member.Health = models.HealthHealthy
+		case "startup", "catchup", "backup":
+			member.Health = models.HealthRecovering
+		default:
+			member.Health = models.HealthUnknown
+		}
+		if row["lag_bytes"] != nil {
+			lagBytes := cast.ToInt64(row["lag_bytes"])
+			member.LagBytes = &lagBytes
+		}
+		if row["lag_seconds"] != nil {
+			lagSeconds := cast.ToInt64(row["lag_seconds"])
+			member.LagSeconds = &lagSeconds
+		}
+		topology.Members = append(topology.Members, member)
+	}
+	return topology, nil
+}
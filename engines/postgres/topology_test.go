@@ -0,0 +1,79 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestGetTopology(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock, _ := MockDatabase(t)
+	defer mock.Close()
+
+	t.Run("primary", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(currentLSNSQL)).
+			WillReturnRows(pgxmock.NewRows([]string{"in_recovery", "applied_lsn"}).AddRow(false, "0/3000148"))
+		mock.ExpectQuery(regexp.QuoteMeta(replicationSQL)).
+			WillReturnRows(pgxmock.NewRows([]string{"application_name", "client_addr", "state", "sync_state", "lag_bytes", "lag_seconds"}).
+				AddRow("pg-1", "10.0.0.2", "streaming", "async", int64(128), int64(1)).
+				AddRow("pg-2", "10.0.0.3", "catchup", "async", nil, nil))
+
+		topology, err := manager.GetTopology(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, topology.Members, 3)
+		assert.Equal(t, models.PRIMARY, topology.Members[0].Role)
+		assert.True(t, topology.Members[0].Self)
+		assert.Equal(t, "pg-1", topology.Members[1].Name)
+		assert.Equal(t, models.SECONDARY, topology.Members[1].Role)
+		assert.Equal(t, "async", topology.Members[1].NativeRole)
+		assert.Equal(t, models.HealthHealthy, topology.Members[1].Health)
+		assert.Equal(t, int64(128), *topology.Members[1].LagBytes)
+		assert.Equal(t, models.HealthRecovering, topology.Members[2].Health)
+		assert.Nil(t, topology.Members[2].LagBytes)
+	})
+
+	t.Run("standby", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(currentLSNSQL)).
+			WillReturnRows(pgxmock.NewRows([]string{"in_recovery", "applied_lsn"}).AddRow(true, "0/3000060"))
+		mock.ExpectQuery(regexp.QuoteMeta(walReceiverSQL)).
+			WillReturnRows(pgxmock.NewRows([]string{"status", "sender_host", "sender_port", "received_lsn", "lag_bytes", "lag_seconds"}).
+				AddRow("streaming", "pg-0", int32(5432), "0/3000148", int64(232), int64(3)))
+
+		topology, err := manager.GetTopology(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, topology.Members, 2)
+		assert.Equal(t, models.SECONDARY, topology.Members[0].Role)
+		assert.Equal(t, int64(232), *topology.Members[0].LagBytes)
+		assert.Equal(t, "pg-0:5432", topology.Members[1].Address)
+		assert.Equal(t, models.PRIMARY, topology.Members[1].Role)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
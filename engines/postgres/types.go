@@ -55,6 +55,7 @@ type PgxIFace interface {
 type PgxPoolIFace interface {
 	PgxIFace
 	Acquire(ctx context.Context) (*pgxpool.Conn, error)
+	Reset()
 	Close()
 }
 
@@ -25,7 +25,6 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cast"
@@ -56,22 +55,6 @@ func NewManager() (engines.DBManager, error) {
 	return Mgr, nil
 }
 
-func (mgr *Manager) IsDBStartupReady() bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
-	defer cancel()
-	if mgr.DBStartupReady {
-		return true
-	}
-
-	if !mgr.IsPgReady(ctx) {
-		return false
-	}
-
-	mgr.DBStartupReady = true
-	mgr.Logger.Info("DB startup ready")
-	return true
-}
-
 func (mgr *Manager) GetMemberRoleWithHost(ctx context.Context, host string) (string, error) {
 	getRoleFromPatroni := func() (string, error) {
 		patroniPort := viper.GetString("PATRONI_PORT")
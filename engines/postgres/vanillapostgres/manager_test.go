@@ -0,0 +1,89 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package vanillapostgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/constant"
+	"github.com/apecloud/dbctl/engines/conformance"
+	"github.com/apecloud/dbctl/engines/postgres"
+)
+
+func MockDatabase(t *testing.T) (*Manager, pgxmock.PgxPoolIface, error) {
+	testConfig, err := postgres.NewConfig()
+	assert.NotNil(t, testConfig)
+	assert.Nil(t, err)
+
+	viper.Set(constant.KBEnvPodName, "test-pod-0")
+	viper.Set(constant.KBEnvClusterCompName, "test")
+	viper.Set(constant.KBEnvNamespace, "default")
+	viper.Set(postgres.PGDATA, "test")
+	mock, err := pgxmock.NewPool(pgxmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dbManager, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager := dbManager.(*Manager)
+	manager.Pool = mock
+
+	return manager, mock, err
+}
+
+func TestConformance(t *testing.T) {
+	manager, mock, _ := MockDatabase(t)
+	defer mock.Close()
+	// pgxmock only checks the context while delaying
+	standInDelay := 10 * time.Millisecond
+
+	conformance.Run(t, conformance.Suite{
+		Manager: manager,
+		Prepare: func(method conformance.Method) {
+			switch method {
+			case conformance.IsDBStartupReady:
+				mock.ExpectPing()
+			case conformance.GetReplicaRole, conformance.GetReplicaRoleInfo:
+				mock.ExpectQuery("select pg_is_in_recovery()").
+					WillReturnRows(pgxmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(false)).
+					WillDelayFor(standInDelay)
+			case conformance.Exec:
+				mock.ExpectExec("create database test").
+					WillReturnResult(pgxmock.NewResult("CREATE DATABASE", 0)).
+					WillDelayFor(standInDelay)
+			case conformance.Query:
+				mock.ExpectQuery("select 1").
+					WillReturnRows(pgxmock.NewRows([]string{"?column?"}).AddRow(1)).
+					WillDelayFor(standInDelay)
+			}
+		},
+		ExecSQL:  "create database test",
+		QuerySQL: "select 1",
+	})
+}
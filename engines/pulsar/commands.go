@@ -21,6 +21,7 @@ package pulsar
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -28,6 +29,8 @@ import (
 	"github.com/apecloud/dbctl/engines/models"
 )
 
+const adminClient = "bin/pulsar-admin"
+
 type Commands struct {
 	info     engines.EngineInfo
 	examples map[models.ClientType]engines.BuildConnectExample
@@ -36,11 +39,11 @@ type Commands struct {
 var _ engines.ClusterCommands = &Commands{}
 
 func NewBrokerCommands() engines.ClusterCommands {
-	return NewCommands("broker")
+	return NewCommands(Broker)
 }
 
 func NewProxyCommands() engines.ClusterCommands {
-	return NewCommands("proxy")
+	return NewCommands(Proxy)
 }
 
 func NewCommands(containName string) engines.ClusterCommands {
@@ -69,6 +72,13 @@ func (r *Commands) ConnectExample(info *engines.ConnectionInfo, client string) s
 	return engines.BuildExample(info, client, r.examples)
 }
 
-func (r *Commands) ExecuteCommand([]string) ([]string, []corev1.EnvVar, error) {
-	return nil, nil, fmt.Errorf("%s not implemented", r.info.Client)
+// ExecuteCommand runs the scripts by pulsar-admin of the container, e.g.
+// "topics list public/default", it's connected to the local web service as
+// configured by conf/client.conf.
+func (r *Commands) ExecuteCommand(scripts []string) ([]string, []corev1.EnvVar, error) {
+	args := make([]string, 0, len(scripts))
+	for _, script := range scripts {
+		args = append(args, fmt.Sprintf("%s %s", adminClient, script))
+	}
+	return []string{"/bin/sh", "-c", "-ex", strings.Join(args, " && ")}, nil, nil
 }
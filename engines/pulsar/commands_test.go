@@ -73,4 +73,13 @@ var _ = Describe("Pulsar Engine", func() {
 
 		Expect(pulsar.ConnectExample(info, "")).ShouldNot(BeZero())
 	})
+
+	It("execute command", func() {
+		pulsar := NewBrokerCommands()
+
+		cmd, envs, err := pulsar.ExecuteCommand([]string{"tenants list", "namespaces list public"})
+		Expect(err).Should(Succeed())
+		Expect(envs).Should(BeEmpty())
+		Expect(cmd).Should(Equal([]string{"/bin/sh", "-c", "-ex", "bin/pulsar-admin tenants list && bin/pulsar-admin namespaces list public"}))
+	})
 })
@@ -0,0 +1,90 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package pulsar
+
+import (
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+const (
+	// configSection is the config file section of Pulsar, shared by the brokers and
+	// the proxies, the address is of the web service which serves the admin API.
+	configSection = "pulsar"
+
+	defaultHost    = "127.0.0.1"
+	defaultPort    = 8080
+	defaultTLSPort = 8443
+	defaultTimeout = 5 * time.Second
+
+	// EnvAuthToken is the JWT to authenticate to the admin API, the admin API is
+	// called anonymously if it's not set.
+	EnvAuthToken = "PULSAR_AUTH_TOKEN"
+	// configKeyToken is the file of the token in the credentials dir.
+	configKeyToken = "token"
+)
+
+type Config struct {
+	// URL is the base URL of the web service, e.g. http://127.0.0.1:8080.
+	URL       string
+	TLSConfig *tls.Config
+	Timeout   time.Duration
+
+	// EngineCredentials holds the token as the password.
+	*utilconfig.EngineCredentials
+}
+
+func NewConfig() (*Config, error) {
+	engineConfig, err := utilconfig.GetEngineConfig(configSection)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{
+		// the password of the config file is taken as the token, and the token from
+		// env takes precedence over it
+		EngineCredentials: utilconfig.NewEngineCredentials(configSection,
+			utilconfig.CredentialLookup{},
+			utilconfig.CredentialLookup{Name: configKeyToken, Envs: []string{EnvAuthToken}}),
+		Timeout: defaultTimeout,
+	}
+	if config.TLSConfig, err = engineConfig.TLS.ClientConfig(); err != nil {
+		return nil, err
+	}
+	scheme, port := "http", defaultPort
+	if config.TLSConfig != nil {
+		scheme, port = "https", defaultTLSPort
+	}
+	config.URL = fmt.Sprintf("%s://%s", scheme, engineConfig.Addr(defaultHost, port))
+	if engineConfig.ReadTimeout != 0 {
+		config.Timeout = engineConfig.ReadTimeout
+	}
+	config.LoadCredentials(engineConfig)
+	return config, nil
+}
+
+// GetToken returns the current token to authenticate to the admin API.
+func (config *Config) GetToken() string {
+	_, token := config.GetCredentials()
+	return token
+}
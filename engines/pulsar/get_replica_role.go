@@ -0,0 +1,73 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package pulsar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const leaderBrokerPath = "/admin/v2/brokers/leaderBroker"
+
+// leaderBroker is the leader of the brokers, which runs the load manager to
+// assign the bundles.
+type leaderBroker struct {
+	ServiceURL string `json:"serviceUrl"`
+	BrokerID   string `json:"brokerId"`
+}
+
+// GetReplicaRole returns leader if the local broker is the leader broker, or
+// follower otherwise. The proxies have no role.
+func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
+	if mgr.component != Broker {
+		return "", models.ErrNotImplemented
+	}
+
+	data, err := mgr.request(ctx, http.MethodGet, leaderBrokerPath, nil)
+	if err != nil {
+		return "", err
+	}
+	leader := &leaderBroker{}
+	if err = json.Unmarshal(data, leader); err != nil {
+		return "", errors.Wrapf(err, "decode the leader broker %s failed", data)
+	}
+	serviceURL, err := url.Parse(leader.ServiceURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "parse the service url %s of the leader broker failed", leader.ServiceURL)
+	}
+
+	if mgr.isLocalHost(serviceURL.Hostname()) {
+		return models.LEADER, nil
+	}
+	return models.FOLLOWER, nil
+}
+
+// isLocalHost tells whether the host is the local pod, the brokers advertise
+// their FQDNs in KubeBlocks.
+func (mgr *Manager) isLocalHost(host string) bool {
+	return host == mgr.CurrentMemberName || strings.HasPrefix(host, mgr.CurrentMemberName+".")
+}
@@ -0,0 +1,161 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package pulsar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+// the Pulsar components served by dbctl.
+const (
+	Broker = "broker"
+	Proxy  = "proxy"
+)
+
+const healthPath = "/admin/v2/brokers/health"
+
+// Manager calls the admin REST API of the local web service, a proxy forwards
+// the admin requests to the brokers.
+type Manager struct {
+	engines.DBManagerBase
+	component string
+	config    *Config
+	client    *http.Client
+}
+
+var _ engines.DBManager = &Manager{}
+
+func NewBrokerManager() (engines.DBManager, error) {
+	return NewManager(Broker)
+}
+
+func NewProxyManager() (engines.DBManager, error) {
+	return NewManager(Proxy)
+}
+
+func NewManager(component string) (engines.DBManager, error) {
+	logger := ctrl.Log.WithName("Pulsar")
+	config, err := NewConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	managerBase, err := engines.NewDBManagerBase(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := &Manager{
+		DBManagerBase: *managerBase,
+		component:     component,
+		config:        config,
+		client: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: &http.Transport{TLSClientConfig: config.TLSConfig},
+		},
+	}
+
+	utilconfig.OnCredentialsChange(mgr.reloadCredentials)
+	return mgr, nil
+}
+
+// reloadCredentials reloads the token, which is read for every request.
+func (mgr *Manager) reloadCredentials() {
+	if err := mgr.config.ReloadCredentials(); err != nil {
+		mgr.Logger.Info("reload credentials failed", "error", err.Error())
+		return
+	}
+	mgr.Logger.Info("credentials reloaded")
+}
+
+// IsDBStartupReady checks the health of the broker, which produces and consumes
+// a message on the health check topic. A proxy is ready once it reaches a healthy
+// broker.
+func (mgr *Manager) IsDBStartupReady() bool {
+	if mgr.DBStartupReady {
+		return true
+	}
+
+	if _, err := mgr.request(context.Background(), http.MethodGet, healthPath, nil); err != nil {
+		mgr.Logger.Info("DB is not ready", "error", err.Error())
+		return false
+	}
+
+	mgr.DBStartupReady = true
+	mgr.Logger.Info("DB startup ready")
+	return true
+}
+
+// adminError is the error response of the admin API.
+type adminError struct {
+	Reason string `json:"reason"`
+}
+
+// request sends the admin request and returns the response body, the error of a
+// failed request is the reason Pulsar responds with.
+func (mgr *Manager) request(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, mgr.config.URL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) != 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if token := mgr.config.GetToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := mgr.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s failed", method, path)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read the response of %s %s failed", method, path)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		reason := strings.TrimSpace(string(data))
+		adminErr := &adminError{}
+		if json.Unmarshal(data, adminErr) == nil && adminErr.Reason != "" {
+			reason = adminErr.Reason
+		}
+		return nil, errors.Errorf("%s %s failed, status %s: %s", method, path, resp.Status, reason)
+	}
+	return data, nil
+}
+
+func (mgr *Manager) ShutDownWithWait() {
+	mgr.client.CloseIdleConnections()
+}
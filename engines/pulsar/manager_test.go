@@ -0,0 +1,170 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package pulsar
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/conformance"
+	"github.com/apecloud/dbctl/engines/models"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+// mockAdmin serves the admin API of a broker, the requests are recorded as
+// "METHOD path body".
+type mockAdmin struct {
+	healthy  bool
+	leader   string
+	requests []string
+}
+
+func (m *mockAdmin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	m.requests = append(m.requests, r.Method+" "+r.URL.Path+" "+string(body))
+	if r.Header.Get("Authorization") != "Bearer test-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"reason":"Authentication required"}`))
+		return
+	}
+
+	switch r.Method + " " + r.URL.Path {
+	case "GET " + healthPath:
+		if !m.healthy {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"reason":"Timed out waiting for the health check topic"}`))
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	case "GET " + leaderBrokerPath:
+		_, _ = w.Write([]byte(`{"serviceUrl":"` + m.leader + `","brokerId":"pulsar-broker-0:8080"}`))
+	case "GET /admin/v2/persistent/public/default":
+		_, _ = w.Write([]byte(`["persistent://public/default/t1"]`))
+	case "PUT /admin/v2/persistent/public/default/t2/partitions":
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"reason":"Not found"}`))
+	}
+}
+
+func mockManager(t *testing.T, component string) (*Manager, *mockAdmin) {
+	admin := &mockAdmin{
+		healthy: true,
+		leader:  "http://pulsar-broker-0.pulsar-broker-headless.default.svc.cluster.local:8080",
+	}
+	server := httptest.NewServer(admin)
+	t.Cleanup(server.Close)
+
+	mgr := &Manager{
+		DBManagerBase: engines.DBManagerBase{
+			CurrentMemberName: "pulsar-broker-0",
+			Logger:            ctrl.Log.WithName("Pulsar-TEST"),
+		},
+		component: component,
+		config: &Config{
+			URL:               server.URL,
+			EngineCredentials: utilconfig.NewEngineCredentials(configSection, utilconfig.CredentialLookup{}, utilconfig.CredentialLookup{Default: "test-token"}),
+		},
+		client: server.Client(),
+	}
+	return mgr, admin
+}
+
+func TestIsDBStartupReady(t *testing.T) {
+	mgr, admin := mockManager(t, Broker)
+
+	admin.healthy = false
+	assert.False(t, mgr.IsDBStartupReady())
+
+	admin.healthy = true
+	assert.True(t, mgr.IsDBStartupReady())
+
+	// the token is required
+	mgr, _ = mockManager(t, Proxy)
+	mgr.config.SetCredentials("", "")
+	assert.False(t, mgr.IsDBStartupReady())
+}
+
+func TestGetReplicaRole(t *testing.T) {
+	ctx := context.TODO()
+	mgr, admin := mockManager(t, Broker)
+
+	role, err := mgr.GetReplicaRole(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.LEADER, role)
+
+	admin.leader = "http://pulsar-broker-1.pulsar-broker-headless.default.svc.cluster.local:8080"
+	role, err = mgr.GetReplicaRole(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.FOLLOWER, role)
+
+	mgr, _ = mockManager(t, Proxy)
+	_, err = mgr.GetReplicaRole(ctx)
+	assert.ErrorIs(t, err, models.ErrNotImplemented)
+}
+
+func TestQueryAndExec(t *testing.T) {
+	ctx := context.TODO()
+	mgr, admin := mockManager(t, Broker)
+
+	result, err := mgr.Query(ctx, "/admin/v2/persistent/public/default")
+	require.NoError(t, err)
+	assert.JSONEq(t, `["persistent://public/default/t1"]`, string(result))
+
+	result, err = mgr.Query(ctx, "GET admin/v2/brokers/health")
+	require.NoError(t, err)
+	assert.Equal(t, `"ok"`, string(result))
+
+	_, err = mgr.Query(ctx, "GET /admin/v2/persistent/public/default/t3/stats")
+	assert.ErrorContains(t, err, "Not found")
+	_, err = mgr.Query(ctx, "DELETE /admin/v2/persistent/public/default/t1")
+	assert.ErrorContains(t, err, "use exec")
+	_, err = mgr.Query(ctx, "/metrics")
+	assert.ErrorContains(t, err, "not a path of the admin API")
+
+	affected, err := mgr.Exec(ctx, "put /admin/v2/persistent/public/default/t2/partitions\n3\n")
+	require.NoError(t, err)
+	assert.Zero(t, affected)
+	assert.Equal(t, "PUT /admin/v2/persistent/public/default/t2/partitions 3", admin.requests[len(admin.requests)-1])
+
+	_, err = mgr.Exec(ctx, "GET /admin/v2/persistent/public/default")
+	assert.ErrorContains(t, err, "use query")
+	_, err = mgr.Exec(ctx, "PUT /admin/v2/tenants/t1 extra")
+	assert.ErrorContains(t, err, "invalid request line")
+}
+
+func TestConformance(t *testing.T) {
+	mgr, _ := mockManager(t, Broker)
+
+	conformance.Run(t, conformance.Suite{
+		Manager:  mgr,
+		ExecSQL:  "PUT /admin/v2/persistent/public/default/t2/partitions\n3",
+		QuerySQL: "GET /admin/v2/persistent/public/default",
+	})
+}
@@ -0,0 +1,107 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package pulsar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Query sends an admin GET request, e.g. "/admin/v2/persistent/public/default"
+// to list the topics, or "/admin/v2/persistent/public/default/t1/stats". The
+// response is returned as it is, a response other than JSON is returned as a
+// JSON string.
+func (mgr *Manager) Query(ctx context.Context, request string) ([]byte, error) {
+	mgr.Logger.Info(fmt.Sprintf("query: %s", request))
+	method, path, body, err := parseRequest(request, http.MethodGet)
+	if err != nil {
+		return nil, err
+	}
+	if method != http.MethodGet {
+		return nil, errors.Errorf("query only sends GET requests, use exec for %s", method)
+	}
+
+	data, err := mgr.request(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(data) {
+		return json.Marshal(string(data))
+	}
+	return data, nil
+}
+
+// Exec sends an admin POST, PUT or DELETE request, POST by default, the JSON body
+// follows the request line, e.g.
+//
+//	PUT /admin/v2/persistent/public/default/t1/partitions
+//	3
+//
+// The admin API doesn't report the affected rows, so it's always 0.
+func (mgr *Manager) Exec(ctx context.Context, request string) (int64, error) {
+	mgr.Logger.Info(fmt.Sprintf("exec: %s", request))
+	method, path, body, err := parseRequest(request, http.MethodPost)
+	if err != nil {
+		return 0, err
+	}
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodDelete:
+	default:
+		return 0, errors.Errorf("exec only sends POST, PUT or DELETE requests, use query for %s", method)
+	}
+
+	if _, err = mgr.request(ctx, method, path, body); err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+
+// parseRequest parses the request line, "[METHOD] path", and the body in the
+// following lines. Only the paths of the admin API are allowed.
+func parseRequest(request, defaultMethod string) (string, string, []byte, error) {
+	line, body, _ := strings.Cut(strings.TrimSpace(request), "\n")
+	fields := strings.Fields(line)
+	method, path := defaultMethod, ""
+	switch len(fields) {
+	case 1:
+		path = fields[0]
+	case 2:
+		method, path = strings.ToUpper(fields[0]), fields[1]
+	default:
+		return "", "", nil, errors.Errorf("invalid request line %q, it should be [METHOD] path", line)
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if !strings.HasPrefix(path, "/admin/") {
+		return "", "", nil, errors.Errorf("%s is not a path of the admin API", path)
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return method, path, nil, nil
+	}
+	return method, path, []byte(body), nil
+}
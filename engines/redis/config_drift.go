@@ -0,0 +1,81 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package redis
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/configfile"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+var _ engines.ConfigDriftChecker = &Manager{}
+
+// sizeUnits are the memory units of redis.conf, k is 1000 while kb is 1024.
+var sizeUnits = map[string]float64{
+	"k":  1e3,
+	"kb": 1 << 10,
+	"m":  1e6,
+	"mb": 1 << 20,
+	"g":  1e9,
+	"gb": 1 << 30,
+}
+
+// secretParameters are compared, but not reported.
+var secretParameters = map[string]bool{
+	"requirepass": true,
+	"masterauth":  true,
+}
+
+// CheckConfigDrift compares the config file, which is the one in INFO server by
+// default, with CONFIG GET *.
+func (mgr *Manager) CheckConfigDrift(ctx context.Context, file string) (*models.ConfigDriftReport, error) {
+	if file == "" {
+		result, err := mgr.getClient().Info(ctx, "server").Result()
+		if err != nil {
+			return nil, errors.Wrap(err, "info server failed")
+		}
+		if file = parseInfo(result)["config_file"]; file == "" {
+			return nil, errors.New("redis is running without a config file")
+		}
+	}
+	config, err := configfile.ParseRedisConf(file)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := mgr.getClient().ConfigGet(ctx, "*").Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "config get failed")
+	}
+
+	report := configfile.Diff(config, live, func(_, fileValue, liveValue string) bool {
+		return configfile.EqualValues(fileValue, liveValue, sizeUnits)
+	})
+	for i, drift := range report.Drifts {
+		if secretParameters[drift.Name] {
+			report.Drifts[i].FileValue, report.Drifts[i].LiveValue = "******", "******"
+		}
+	}
+	return report, nil
+}
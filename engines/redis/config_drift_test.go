@@ -0,0 +1,65 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package redis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestCheckConfigDrift(t *testing.T) {
+	manager, standIn := mockRedis(t, masterReplicationInfo)
+	file := filepath.Join(t.TempDir(), "redis.conf")
+	assert.Nil(t, os.WriteFile(file, []byte("maxmemory 1gb\nappendonly yes\nrequirepass secret\nrename-command FLUSHALL \"\"\n"), 0644))
+
+	live := map[string]string{"maxmemory": "1073741824", "appendonly": "no", "requirepass": "other"}
+	standIn.Server().SetPreHook(func(c *server.Peer, cmd string, args ...string) bool {
+		switch {
+		case strings.EqualFold(cmd, "info"):
+			c.WriteBulk("# Server\r\nredis_version:7.2.4\r\nconfig_file:" + file + "\r\n")
+		case strings.EqualFold(cmd, "config"):
+			c.WriteMapLen(len(live))
+			for name, value := range live {
+				c.WriteBulk(name)
+				c.WriteBulk(value)
+			}
+		default:
+			return false
+		}
+		return true
+	})
+
+	report, err := manager.CheckConfigDrift(context.TODO(), "")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{file}, report.Files)
+	assert.Equal(t, []models.ParameterDrift{
+		{Name: "appendonly", FileValue: "yes", LiveValue: "no", File: file},
+		{Name: "requirepass", FileValue: "******", LiveValue: "******", File: file},
+	}, report.Drifts)
+	assert.Equal(t, []string{"rename-command"}, report.Unknown)
+}
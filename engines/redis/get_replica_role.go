@@ -31,7 +31,7 @@ func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
 	// when we can't get role from sentinel, we query redis instead
 	getRoleFromRedisClient := func() (string, error) {
 		var role string
-		result, err := mgr.client.Info(ctx, "Replication").Result()
+		result, err := mgr.getClient().Info(ctx, "Replication").Result()
 		if err != nil {
 			mgr.Logger.Info("Role query failed", "error", err.Error())
 			return role, err
@@ -71,7 +71,7 @@ func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
 // When currentRedisHost is a domain name, it does not include dnsDomain by default,
 // and prefix matching can override the matching of domain names or IPs.
 func (mgr *Manager) checkPrimary(masterIP, masterPort string) string {
-	if !strings.HasPrefix(masterIP, mgr.currentRedisHost) || masterPort != mgr.currentRedisPort {
+	if !mgr.isCurrentRedis(masterIP, masterPort) {
 		return models.SECONDARY
 	}
 	return models.PRIMARY
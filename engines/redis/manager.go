@@ -26,6 +26,7 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -34,11 +35,8 @@ import (
 
 	"github.com/apecloud/dbctl/constant"
 	"github.com/apecloud/dbctl/engines"
-)
-
-var (
-	redisUser   = "default"
-	redisPasswd = ""
+	"github.com/apecloud/dbctl/engines/models"
+	utilconfig "github.com/apecloud/dbctl/util/config"
 )
 
 type Manager struct {
@@ -49,26 +47,40 @@ type Manager struct {
 	masterName       string
 	currentRedisHost string
 	currentRedisPort string
+	majorVersion     int
+	// instance is the instance whose envs and credentials are looked up.
+	instance string
+
+	// credentialLock guards the credentials which are reloaded on rotation.
+	credentialLock sync.RWMutex
+	username       string
+	password       string
+	// clientLock guards the client, a failover client is replaced when the
+	// credentials are rotated.
+	clientLock sync.RWMutex
 }
 
 var _ engines.DBManager = &Manager{}
 
 func NewManager() (engines.DBManager, error) {
 	logger := ctrl.Log.WithName("Redis")
-	properties := map[string]string{
-		"redisHost": "127.0.0.1:6379",
+	engineConfig, err := utilconfig.GetEngineConfig(string(models.Redis))
+	if err != nil {
+		return nil, err
 	}
 
-	if viper.IsSet("REDIS_DEFAULT_USER") {
-		redisUser = viper.GetString("REDIS_DEFAULT_USER")
+	redisHost, redisPort := "127.0.0.1", "6379"
+	if engineConfig.Host != "" {
+		redisHost = engineConfig.Host
 	}
-
-	if viper.IsSet("REDIS_DEFAULT_PASSWORD") {
-		redisPasswd = viper.GetString("REDIS_DEFAULT_PASSWORD")
+	if engineConfig.Port != 0 {
+		redisPort = strconv.Itoa(engineConfig.Port)
 	}
-
 	if viper.IsSet(constant.KBEnvServicePort) {
-		properties["redisHost"] = fmt.Sprintf("127.0.0.1:%s", viper.GetString(constant.KBEnvServicePort))
+		redisPort = viper.GetString(constant.KBEnvServicePort)
+	}
+	properties := map[string]string{
+		"redisHost": net.JoinHostPort(redisHost, redisPort),
 	}
 
 	managerBase, err := engines.NewDBManagerBase(logger)
@@ -77,6 +89,7 @@ func NewManager() (engines.DBManager, error) {
 	}
 	mgr := &Manager{
 		DBManagerBase: *managerBase,
+		instance:      utilconfig.CurrentInstance(),
 	}
 
 	mgr.masterName = mgr.ClusterCompName
@@ -128,29 +141,111 @@ func NewManager() (engines.DBManager, error) {
 		return nil, err
 	}
 
-	majorVersion, err := getRedisMajorVersion()
+	mgr.majorVersion, err = getRedisMajorVersion()
 	if err != nil {
 		return nil, err
 	}
-	// The username is supported after 6.0
-	if majorVersion < 6 {
-		redisUser = ""
-	}
+	mgr.setCredentials(engineConfig)
 
 	defaultSettings := &Settings{
-		Password:  redisPasswd,
-		Username:  redisUser,
-		EnableTLS: viper.GetBool("TLS_ENABLED"),
+		Password:     mgr.password,
+		Username:     mgr.username,
+		EnableTLS:    engineConfig.TLS.Enabled,
+		PoolSize:     engineConfig.MaxOpenConns,
+		MinIdleConns: engineConfig.MaxIdleConns,
+		DialTimeout:  Duration(engineConfig.ConnectTimeout),
+		ReadTimeout:  Duration(engineConfig.ReadTimeout),
+		WriteTimeout: Duration(engineConfig.WriteTimeout),
+	}
+	if viper.IsSet("TLS_ENABLED") {
+		defaultSettings.EnableTLS = viper.GetBool("TLS_ENABLED")
 	}
+	defaultSettings.CredentialsProvider = mgr.getCredentials
 	mgr.client, mgr.clientSettings, err = ParseClientFromProperties(properties, defaultSettings)
 	if err != nil {
 		return nil, err
 	}
 
-	mgr.sentinelClient = newSentinelClient(mgr.clientSettings, mgr.ClusterCompName, majorVersion)
+	mgr.sentinelClient = newSentinelClient(mgr.clientSettings, mgr.instance, mgr.ClusterCompName, mgr.majorVersion)
+	utilconfig.OnCredentialsChange(mgr.reloadCredentials)
 	return mgr, nil
 }
 
+// closeGracePeriod is the time the old failover client is kept open after it's
+// replaced, longer than the read and write timeouts of the commands.
+const closeGracePeriod = time.Minute
+
+// reloadCredentials reads the credentials again when the mounted secrets are rotated,
+// the client and the sentinel client pick them up for new connections through the
+// credentials provider, while a failover client is rebuilt with them, and the old
+// one is closed after the grace period.
+func (mgr *Manager) reloadCredentials() {
+	engineConfig, err := utilconfig.GetInstanceEngineConfig(mgr.instance, string(models.Redis))
+	if err != nil {
+		mgr.Logger.Info("reload credentials failed", "error", err.Error())
+		return
+	}
+	mgr.setCredentials(engineConfig)
+	if mgr.clientSettings != nil && mgr.clientSettings.Failover {
+		settings := *mgr.clientSettings
+		settings.Username, settings.Password = mgr.getCredentials()
+		client := newFailoverClient(&settings)
+
+		mgr.clientLock.Lock()
+		oldClient := mgr.client
+		mgr.client = client
+		mgr.clientLock.Unlock()
+		if oldClient != nil {
+			// the in-flight commands on the old client are given the grace period
+			go func() {
+				time.Sleep(closeGracePeriod)
+				if err := oldClient.Close(); err != nil {
+					mgr.Logger.Info("failed to close the old client", "error", err.Error())
+				}
+			}()
+		}
+	}
+	mgr.Logger.Info("credentials reloaded")
+}
+
+// getClient returns the current client, which may be replaced when the credentials
+// are rotated.
+func (mgr *Manager) getClient() redis.UniversalClient {
+	mgr.clientLock.RLock()
+	defer mgr.clientLock.RUnlock()
+	return mgr.client
+}
+
+func (mgr *Manager) setCredentials(engineConfig *utilconfig.EngineConfig) {
+	username, password := "default", ""
+	if engineConfig.Username != "" {
+		username = engineConfig.Username
+	}
+	if engineConfig.Password != "" {
+		password = engineConfig.Password
+	}
+	if user, ok := utilconfig.LookupInstanceCredential(mgr.instance, constant.ConfigKeyUserName, "REDIS_DEFAULT_USER"); ok {
+		username = user
+	}
+	if passwd, ok := utilconfig.LookupInstanceCredential(mgr.instance, constant.ConfigKeyPassword, "REDIS_DEFAULT_PASSWORD"); ok {
+		password = passwd
+	}
+	// The username is supported after 6.0
+	if mgr.majorVersion < 6 {
+		username = ""
+	}
+
+	mgr.credentialLock.Lock()
+	defer mgr.credentialLock.Unlock()
+	mgr.username, mgr.password = username, password
+}
+
+func (mgr *Manager) getCredentials() (string, string) {
+	mgr.credentialLock.RLock()
+	defer mgr.credentialLock.RUnlock()
+	return mgr.username, mgr.password
+}
+
 func (mgr *Manager) IsDBStartupReady() bool {
 	if mgr.DBStartupReady {
 		return true
@@ -158,7 +253,7 @@ func (mgr *Manager) IsDBStartupReady() bool {
 	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
 	defer cancel()
 
-	if _, err := mgr.client.Ping(ctx).Result(); err != nil {
+	if _, err := mgr.getClient().Ping(ctx).Result(); err != nil {
 		mgr.Logger.Info("connecting to redis failed", "host", mgr.clientSettings.Host, "error", err)
 		return false
 	}
@@ -168,6 +263,12 @@ func (mgr *Manager) IsDBStartupReady() bool {
 	return true
 }
 
+var _ engines.Pinger = &Manager{}
+
+func (mgr *Manager) Ping(ctx context.Context) error {
+	return mgr.getClient().Ping(ctx).Err()
+}
+
 func tokenizeCmd2Args(cmd string) []interface{} {
 	args := strings.Split(cmd, " ")
 	redisArgs := make([]interface{}, 0, len(args))
@@ -0,0 +1,114 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package redis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/alicebob/miniredis/v2/server"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/apecloud/dbctl/constant"
+	"github.com/apecloud/dbctl/engines/conformance"
+)
+
+const masterReplicationInfo = "# Replication\r\nrole:master\r\nconnected_slaves:0\r\nmaster_repl_offset:0\r\n"
+
+func mockRedis(t *testing.T, replicationInfo string) (*Manager, *miniredis.Miniredis) {
+	standIn := miniredis.RunT(t)
+	// miniredis doesn't support the replication section of info
+	standIn.Server().SetPreHook(func(c *server.Peer, cmd string, args ...string) bool {
+		if strings.EqualFold(cmd, "info") && len(args) == 1 && strings.EqualFold(args[0], "replication") {
+			c.WriteBulk(replicationInfo)
+			return true
+		}
+		return false
+	})
+
+	viper.Set(constant.KBEnvServicePort, standIn.Port())
+	viper.Set("REDIS_VERSION", "7.2.4")
+	t.Cleanup(func() {
+		viper.Set(constant.KBEnvServicePort, nil)
+		viper.Set("REDIS_VERSION", nil)
+	})
+
+	dbManager, err := NewManager()
+	assert.Nil(t, err)
+	return dbManager.(*Manager), standIn
+}
+
+func TestConformance(t *testing.T) {
+	manager, _ := mockRedis(t, masterReplicationInfo)
+
+	conformance.Run(t, conformance.Suite{
+		Manager:  manager,
+		ExecSQL:  "SET foo bar",
+		QuerySQL: "GET foo",
+	})
+}
+
+func TestSentinelClientCredentials(t *testing.T) {
+	sentinel := miniredis.RunT(t)
+	sentinel.RequireAuth("old-password")
+	viper.Set("SENTINEL_COMPONENT_NAME", "redis-sentinel")
+	viper.Set("SENTINEL_HEADLESS_SERVICE_NAME", sentinel.Host())
+	viper.Set("SENTINEL_SERVICE_PORT", sentinel.Port())
+	t.Cleanup(func() {
+		viper.Set("SENTINEL_COMPONENT_NAME", nil)
+		viper.Set("SENTINEL_HEADLESS_SERVICE_NAME", nil)
+		viper.Set("SENTINEL_SERVICE_PORT", nil)
+	})
+
+	password := "old-password"
+	settings := &Settings{
+		Password:            password,
+		CredentialsProvider: func() (string, string) { return "", password },
+	}
+	client := newSentinelClient(settings, "", "redis", 7)
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	ctx := context.TODO()
+	require.NoError(t, client.Ping(ctx).Err())
+
+	// the rotated password is used for the new connections
+	sentinel.RequireAuth("new-password")
+	password = "new-password"
+	sentinel.Restart()
+	require.NoError(t, client.Ping(ctx).Err())
+
+	// the sentinel credentials of a named instance are looked up by its envs
+	sentinel.RequireAuth("sentinel-password")
+	sentinel.Restart()
+	viper.Set("REDIS2_SENTINEL_PASSWORD", "sentinel-password")
+	t.Cleanup(func() {
+		viper.Set("REDIS2_SENTINEL_PASSWORD", nil)
+	})
+	instanceClient := newSentinelClient(settings, "redis2", "redis", 7)
+	t.Cleanup(func() {
+		_ = instanceClient.Close()
+	})
+	require.NoError(t, instanceClient.Ping(ctx).Err())
+}
@@ -26,13 +26,13 @@ import (
 
 func (mgr *Manager) Exec(ctx context.Context, cmd string) (int64, error) {
 	args := tokenizeCmd2Args(cmd)
-	return 0, mgr.client.Do(ctx, args...).Err()
+	return 0, mgr.getClient().Do(ctx, args...).Err()
 }
 
 func (mgr *Manager) Query(ctx context.Context, cmd string) ([]byte, error) {
 	args := tokenizeCmd2Args(cmd)
 	// parse result into a slice of string
-	data, err := mgr.client.Do(ctx, args...).Result()
+	data, err := mgr.getClient().Do(ctx, args...).Result()
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,89 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package redis
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+var _ engines.Reconfigurer = &Manager{}
+
+// staticParameters are the immutable configs of Redis, CONFIG SET rejects them.
+var staticParameters = map[string]bool{
+	"cluster-config-file":   true,
+	"cluster-enabled":       true,
+	"daemonize":             true,
+	"databases":             true,
+	"enable-debug-command":  true,
+	"enable-module-command": true,
+	"io-threads":            true,
+	"logfile":               true,
+	"pidfile":               true,
+	"supervised":            true,
+	"syslog-enabled":        true,
+	"syslog-facility":       true,
+	"syslog-ident":          true,
+	"unixsocket":            true,
+	"unixsocketperm":        true,
+}
+
+func (mgr *Manager) ClassifyParameter(ctx context.Context, name string) (models.ParameterKind, error) {
+	name = strings.ToLower(name)
+	if !engines.IsValidParameterName(name) {
+		return "", models.ErrUnknownParameter
+	}
+	configs, err := mgr.getClient().ConfigGet(ctx, name).Result()
+	if err != nil {
+		return "", errors.Wrapf(err, "get config %s failed", name)
+	}
+	if _, ok := configs[name]; !ok {
+		return "", models.ErrUnknownParameter
+	}
+	if staticParameters[name] {
+		return models.ParameterStatic, nil
+	}
+	return models.ParameterDynamic, nil
+}
+
+// SetParameter sets the config, and rewrites the config file with it. An immutable
+// config can't be persisted, CONFIG REWRITE only writes the running configs, so
+// it's reported to be set in the config file and take effect after a restart.
+func (mgr *Manager) SetParameter(ctx context.Context, name, value string) error {
+	name = strings.ToLower(name)
+	if staticParameters[name] {
+		return models.ErrNotPersisted
+	}
+	if err := mgr.getClient().ConfigSet(ctx, name, value).Err(); err != nil {
+		if strings.Contains(err.Error(), "immutable") {
+			return models.ErrNotPersisted
+		}
+		return err
+	}
+	if err := mgr.getClient().ConfigRewrite(ctx).Err(); err != nil {
+		return errors.Wrap(err, "config rewrite failed")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package redis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestReconfigure(t *testing.T) {
+	ctx := context.TODO()
+	manager, standIn := mockRedis(t, masterReplicationInfo)
+
+	// miniredis doesn't support config
+	configs := map[string]string{"maxmemory": "0", "daemonize": "no"}
+	rewritten := false
+	standIn.Server().SetPreHook(func(c *server.Peer, cmd string, args ...string) bool {
+		if !strings.EqualFold(cmd, "config") {
+			return false
+		}
+		switch strings.ToLower(args[0]) {
+		case "get":
+			if value, ok := configs[args[1]]; ok {
+				c.WriteMapLen(1)
+				c.WriteBulk(args[1])
+				c.WriteBulk(value)
+			} else {
+				c.WriteMapLen(0)
+			}
+		case "set":
+			if args[1] == "appendfilename" {
+				c.WriteError("ERR CONFIG SET failed (possibly related to argument 'appendfilename') - can't set immutable config")
+				return true
+			}
+			configs[args[1]] = args[2]
+			c.WriteOK()
+		case "rewrite":
+			rewritten = true
+			c.WriteOK()
+		}
+		return true
+	})
+
+	kind, err := manager.ClassifyParameter(ctx, "maxmemory")
+	assert.Nil(t, err)
+	assert.Equal(t, models.ParameterDynamic, kind)
+
+	kind, err = manager.ClassifyParameter(ctx, "daemonize")
+	assert.Nil(t, err)
+	assert.Equal(t, models.ParameterStatic, kind)
+
+	_, err = manager.ClassifyParameter(ctx, "foo")
+	assert.ErrorIs(t, err, models.ErrUnknownParameter)
+
+	assert.Nil(t, manager.SetParameter(ctx, "maxmemory", "1gb"))
+	assert.Equal(t, "1gb", configs["maxmemory"])
+	assert.True(t, rewritten)
+
+	// the immutable configs can't be persisted by CONFIG REWRITE
+	assert.ErrorIs(t, manager.SetParameter(ctx, "daemonize", "yes"), models.ErrNotPersisted)
+	assert.ErrorIs(t, manager.SetParameter(ctx, "appendfilename", "redis.aof"), models.ErrNotPersisted)
+}
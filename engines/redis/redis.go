@@ -27,6 +27,8 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"github.com/spf13/viper"
+
+	utilconfig "github.com/apecloud/dbctl/util/config"
 )
 
 const (
@@ -50,6 +52,9 @@ func ParseClientFromProperties(properties map[string]string, defaultSettings *Se
 	return newClient(settings), settings, nil
 }
 
+// newFailoverClient returns a client which follows the master by sentinel, the
+// failover options have no credentials provider, so the client is rebuilt with
+// the new credentials when they are rotated.
 func newFailoverClient(s *Settings) redis.UniversalClient {
 	if s == nil {
 		return nil
@@ -105,6 +110,8 @@ func newClient(s *Settings) redis.UniversalClient {
 			PoolSize:        s.PoolSize,
 			MinIdleConns:    s.MinIdleConns,
 			PoolTimeout:     time.Duration(s.PoolTimeout),
+
+			CredentialsProvider: s.CredentialsProvider,
 		}
 		/* #nosec */
 		if s.EnableTLS {
@@ -130,6 +137,8 @@ func newClient(s *Settings) redis.UniversalClient {
 		PoolSize:        s.PoolSize,
 		MinIdleConns:    s.MinIdleConns,
 		PoolTimeout:     time.Duration(s.PoolTimeout),
+
+		CredentialsProvider: s.CredentialsProvider,
 	}
 
 	/* #nosec */
@@ -142,7 +151,7 @@ func newClient(s *Settings) redis.UniversalClient {
 	return redis.NewClient(options)
 }
 
-func newSentinelClient(s *Settings, clusterCompName string, majorVersion int) *redis.SentinelClient {
+func newSentinelClient(s *Settings, instance, clusterCompName string, majorVersion int) *redis.SentinelClient {
 	if !viper.IsSet("SENTINEL_COMPONENT_NAME") {
 		// cluster has no sentinel
 		return nil
@@ -157,23 +166,29 @@ func newSentinelClient(s *Settings, clusterCompName string, majorVersion int) *r
 		sentinelPort = viper.GetString("SENTINEL_SERVICE_PORT")
 	}
 
-	sentinelUser := s.Username
-	if viper.IsSet("SENTINEL_USER") {
-		sentinelUser = viper.GetString("SENTINEL_USER")
-	}
-	sentinelPassword := s.Password
-	if viper.IsSet("SENTINEL_PASSWORD") {
-		sentinelPassword = viper.GetString("SENTINEL_PASSWORD")
-	}
-	if majorVersion < 6 {
-		sentinelUser = ""
+	// the sentinel shares the credentials of redis unless it has its own, which
+	// are read for every new connection to pick up the rotated ones, the envs of
+	// the instance take precedence
+	sentinelCredentials := func() (string, string) {
+		username, password := s.Username, s.Password
+		if s.CredentialsProvider != nil {
+			username, password = s.CredentialsProvider()
+		}
+		if user, ok := utilconfig.LookupInstanceEnv(instance, "SENTINEL_USER"); ok {
+			username = user
+		}
+		if passwd, ok := utilconfig.LookupInstanceEnv(instance, "SENTINEL_PASSWORD"); ok {
+			password = passwd
+		}
+		if majorVersion < 6 {
+			username = ""
+		}
+		return username, password
 	}
 
 	opt := &redis.Options{
 		DB:              s.DB,
 		Addr:            fmt.Sprintf("%s:%s", sentinelHost, sentinelPort),
-		Password:        sentinelPassword,
-		Username:        sentinelUser,
 		MaxRetries:      s.RedisMaxRetries,
 		MaxRetryBackoff: time.Duration(s.RedisMaxRetryInterval),
 		MinRetryBackoff: time.Duration(s.RedisMinRetryInterval),
@@ -183,6 +198,8 @@ func newSentinelClient(s *Settings, clusterCompName string, majorVersion int) *r
 		PoolSize:        s.PoolSize,
 		MinIdleConns:    s.MinIdleConns,
 		PoolTimeout:     time.Duration(s.PoolTimeout),
+
+		CredentialsProvider: sentinelCredentials,
 	}
 
 	/* #nosec */
@@ -0,0 +1,102 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package redis
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// GetReplicationStatus reads INFO replication. Redis tells no lag on the replica
+// side, since the offset of the master is only known to the master, and
+// master_last_io_seconds_ago is the time since the last contact with the master,
+// which is idle without writes. So the lag is read from the master while the link
+// is up: the bytes are master_repl_offset minus the offset of the replica, and the
+// seconds are the lag the master reports for the replica.
+func (mgr *Manager) GetReplicationStatus(ctx context.Context) (*models.ReplicationStatus, error) {
+	info, err := mgr.getReplicationInfo(ctx, "")
+	if err != nil {
+		mgr.Logger.Info("Replication info query failed", "error", err.Error())
+		return nil, err
+	}
+
+	status := &models.ReplicationStatus{}
+	if info["role"] != models.SLAVE {
+		status.AppliedPosition = info["master_repl_offset"]
+		return status, nil
+	}
+
+	status.IsReplica = true
+	status.UpstreamHost = info["master_host"]
+	status.UpstreamPort = cast.ToInt(info["master_port"])
+	status.IOThreadState = info["master_link_status"]
+	if info["master_sync_in_progress"] == "1" {
+		status.SQLThreadState = "sync"
+	}
+	if downSince, ok := info["master_link_down_since_seconds"]; ok {
+		status.LastError = "master link down since " + downSince + " seconds"
+	}
+	// slave_read_repl_offset is available since redis 7.0
+	status.ReceivedPosition = info["slave_read_repl_offset"]
+	status.AppliedPosition = info["slave_repl_offset"]
+	if status.IOThreadState == "up" {
+		mgr.setLag(ctx, status)
+	}
+	return status, nil
+}
+
+// setLag leaves the lag out if the master can't be reached.
+func (mgr *Manager) setLag(ctx context.Context, status *models.ReplicationStatus) {
+	masterAddr := net.JoinHostPort(status.UpstreamHost, cast.ToString(status.UpstreamPort))
+	master, err := mgr.getReplicationInfo(ctx, masterAddr)
+	if err != nil {
+		mgr.Logger.Info("failed to get the replication info of the master", "master", masterAddr, "error", err.Error())
+		return
+	}
+	lagBytes := max(cast.ToInt64(master["master_repl_offset"])-cast.ToInt64(status.AppliedPosition), 0)
+	status.LagBytes = &lagBytes
+	for _, slave := range parseSlaves(master) {
+		if mgr.isCurrentRedis(slave["ip"], slave["port"]) {
+			lagSeconds := cast.ToInt64(slave["lag"])
+			status.LagSeconds = &lagSeconds
+			break
+		}
+	}
+}
+
+// parseInfo parses the `key:value` lines of the INFO command, the section headers are skipped.
+func parseInfo(result string) map[string]string {
+	info := map[string]string{}
+	for _, line := range strings.Split(result, "\r\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if found {
+			info[key] = value
+		}
+	}
+	return info
+}
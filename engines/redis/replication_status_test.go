@@ -0,0 +1,90 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/cast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReplicationStatus(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("master", func(t *testing.T) {
+		manager, _ := mockRedis(t, "# Replication\r\nrole:master\r\nconnected_slaves:1\r\nmaster_repl_offset:1024\r\n")
+
+		status, err := manager.GetReplicationStatus(ctx)
+		assert.Nil(t, err)
+		assert.False(t, status.IsReplica)
+		assert.Equal(t, "1024", status.AppliedPosition)
+	})
+
+	t.Run("replica", func(t *testing.T) {
+		replicaPort := "6380"
+		_, master := mockRedis(t, "# Replication\r\nrole:master\r\nconnected_slaves:2\r\n"+
+			"slave0:ip=127.0.0.2,port=6379,state=online,offset=1100,lag=0\r\n"+
+			"slave1:ip=127.0.0.1,port="+replicaPort+",state=online,offset=1000,lag=2\r\nmaster_repl_offset:1100\r\n")
+		manager, _ := mockRedis(t, "# Replication\r\nrole:slave\r\nmaster_host:"+master.Host()+"\r\nmaster_port:"+master.Port()+"\r\n"+
+			"master_link_status:up\r\nmaster_last_io_seconds_ago:1\r\nmaster_sync_in_progress:0\r\n"+
+			"slave_read_repl_offset:1024\r\nslave_repl_offset:1000\r\n")
+		manager.currentRedisHost = "127.0.0.1"
+		manager.currentRedisPort = replicaPort
+
+		status, err := manager.GetReplicationStatus(ctx)
+		assert.Nil(t, err)
+		assert.True(t, status.IsReplica)
+		assert.Equal(t, master.Host(), status.UpstreamHost)
+		assert.Equal(t, cast.ToInt(master.Port()), status.UpstreamPort)
+		assert.Equal(t, "up", status.IOThreadState)
+		// the lag is the one the master reports, master_last_io_seconds_ago isn't the lag
+		assert.Equal(t, int64(2), *status.LagSeconds)
+		assert.Equal(t, int64(100), *status.LagBytes)
+		assert.Equal(t, "1024", status.ReceivedPosition)
+		assert.Equal(t, "1000", status.AppliedPosition)
+	})
+
+	t.Run("replica with the master unreachable", func(t *testing.T) {
+		_, master := mockRedis(t, "")
+		host, port := master.Host(), master.Port()
+		master.Close()
+		manager, _ := mockRedis(t, "# Replication\r\nrole:slave\r\nmaster_host:"+host+"\r\nmaster_port:"+port+"\r\n"+
+			"master_link_status:up\r\nslave_repl_offset:1000\r\n")
+
+		status, err := manager.GetReplicationStatus(ctx)
+		assert.Nil(t, err)
+		assert.Nil(t, status.LagSeconds)
+		assert.Nil(t, status.LagBytes)
+		assert.Equal(t, "1000", status.AppliedPosition)
+	})
+
+	t.Run("replica with link down", func(t *testing.T) {
+		manager, _ := mockRedis(t, "# Replication\r\nrole:slave\r\nmaster_host:redis-0\r\nmaster_port:6379\r\n"+
+			"master_link_status:down\r\nmaster_last_io_seconds_ago:-1\r\nmaster_link_down_since_seconds:30\r\n")
+
+		status, err := manager.GetReplicationStatus(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, "down", status.IOThreadState)
+		assert.Nil(t, status.LagSeconds)
+		assert.Equal(t, "master link down since 30 seconds", status.LastError)
+	})
+}
@@ -0,0 +1,93 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package redis
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+var _ engines.SessionManager = &Manager{}
+
+// ListSessions lists the clients of CLIENT LIST, except the replication links and
+// the client running CLIENT LIST itself. A blocked client, e.g. of BLPOP, is in
+// the blocked state, the others are idle as Redis runs one command at a time.
+func (mgr *Manager) ListSessions(ctx context.Context) ([]models.Session, error) {
+	clients, err := mgr.getClient().ClientList(ctx).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "client list failed")
+	}
+	return parseClientList(clients), nil
+}
+
+func (mgr *Manager) KillSession(ctx context.Context, id string) error {
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return errors.Errorf("invalid session id %s", id)
+	}
+	killed, err := mgr.getClient().ClientKillByFilter(ctx, "ID", id).Result()
+	if err != nil {
+		return errors.Wrapf(err, "client kill %s failed", id)
+	}
+	if killed == 0 {
+		return errors.Errorf("client %s not found", id)
+	}
+	return nil
+}
+
+func parseClientList(clients string) []models.Session {
+	sessions := []models.Session{}
+	for _, line := range strings.Split(clients, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := map[string]string{}
+		for _, field := range strings.Fields(line) {
+			if key, value, ok := strings.Cut(field, "="); ok {
+				fields[key] = value
+			}
+		}
+		flags := fields["flags"]
+		if strings.ContainsAny(flags, "MS") || fields["cmd"] == "client" || fields["cmd"] == "client|list" {
+			continue
+		}
+		state := "idle"
+		if strings.Contains(flags, "b") {
+			state = "blocked"
+		}
+		sessions = append(sessions, models.Session{
+			ID:              fields["id"],
+			User:            fields["user"],
+			Database:        fields["db"],
+			Client:          fields["addr"],
+			State:           state,
+			DurationSeconds: cast.ToFloat64(fields["idle"]),
+			Query:           fields["cmd"],
+		})
+	}
+	return sessions
+}
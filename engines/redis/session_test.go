@@ -0,0 +1,76 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package redis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const clientList = `id=3 addr=10.0.0.3:51234 laddr=10.0.0.2:6379 fd=8 name= age=620 idle=600 flags=N db=0 sub=0 psub=0 ssub=0 multi=-1 qbuf=0 qbuf-free=0 argv-mem=0 multi-mem=0 rbs=1024 rbp=0 obl=0 oll=0 omem=0 tot-mem=1928 events=r cmd=get user=app redir=-1 resp=2
+id=4 addr=10.0.0.4:40112 laddr=10.0.0.2:6379 fd=9 name= age=95 idle=95 flags=b db=1 sub=0 psub=0 ssub=0 multi=-1 qbuf=0 qbuf-free=0 argv-mem=0 multi-mem=0 rbs=1024 rbp=0 obl=0 oll=0 omem=0 tot-mem=1928 events=r cmd=blpop user=app redir=-1 resp=2
+id=5 addr=10.0.0.5:6379 laddr=10.0.0.2:40000 fd=10 name= age=900 idle=1 flags=S db=0 sub=0 psub=0 ssub=0 multi=-1 qbuf=0 qbuf-free=0 argv-mem=0 multi-mem=0 rbs=1024 rbp=0 obl=0 oll=0 omem=0 tot-mem=1928 events=r cmd=replconf user=default redir=-1 resp=2
+id=6 addr=127.0.0.1:50000 laddr=127.0.0.1:6379 fd=11 name= age=0 idle=0 flags=N db=0 sub=0 psub=0 ssub=0 multi=-1 qbuf=26 qbuf-free=20448 argv-mem=10 multi-mem=0 rbs=1024 rbp=0 obl=0 oll=0 omem=0 tot-mem=22426 events=r cmd=client|list user=default redir=-1 resp=2
+`
+
+func TestSessions(t *testing.T) {
+	ctx := context.TODO()
+	manager, standIn := mockRedis(t, masterReplicationInfo)
+
+	// miniredis doesn't support client list and client kill
+	var killed []string
+	standIn.Server().SetPreHook(func(c *server.Peer, cmd string, args ...string) bool {
+		if !strings.EqualFold(cmd, "client") {
+			return false
+		}
+		switch strings.ToLower(args[0]) {
+		case "list":
+			c.WriteBulk(clientList)
+		case "kill":
+			if args[2] == "3" {
+				killed = append(killed, args[2])
+				c.WriteInt(1)
+			} else {
+				c.WriteInt(0)
+			}
+		default:
+			return false
+		}
+		return true
+	})
+
+	sessions, err := manager.ListSessions(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []models.Session{
+		{ID: "3", User: "app", Database: "0", Client: "10.0.0.3:51234", State: "idle", DurationSeconds: 600, Query: "get"},
+		{ID: "4", User: "app", Database: "1", Client: "10.0.0.4:40112", State: "blocked", DurationSeconds: 95, Query: "blpop"},
+	}, sessions)
+
+	assert.Nil(t, manager.KillSession(ctx, "3"))
+	assert.Equal(t, []string{"3"}, killed)
+	assert.NotNil(t, manager.KillSession(ctx, "7"))
+	assert.NotNil(t, manager.KillSession(ctx, "id 3"))
+}
@@ -83,6 +83,11 @@ type Settings struct {
 
 	// A flag to enables TLS by setting InsecureSkipVerify to true
 	EnableTLS bool `mapstructure:"enableTLS"`
+
+	// CredentialsProvider returns the latest username and password for new connections,
+	// Username and Password are used if it's nil. Failover clients don't support it,
+	// they are rebuilt when the credentials change instead.
+	CredentialsProvider func() (string, string) `mapstructure:"-"`
 }
 
 func (s *Settings) Decode(in interface{}) error {
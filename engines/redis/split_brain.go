@@ -0,0 +1,69 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package redis
+
+import (
+	"context"
+	"net"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// GetMemberView connects to the member with the local settings, redis replication
+// has no terms, and a master is always writable.
+func (mgr *Manager) GetMemberView(ctx context.Context, address string) (*models.MemberView, error) {
+	info, err := mgr.getReplicationInfo(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &models.MemberView{
+		Address: address,
+		Role:    models.PRIMARY,
+	}
+	if info["role"] == models.SLAVE {
+		view.Role = models.SECONDARY
+		view.Leader = net.JoinHostPort(info["master_host"], info["master_port"])
+		return view, nil
+	}
+	view.Writable = true
+	return view, nil
+}
+
+// getReplicationInfo reads INFO replication of the member at address, or of the
+// local redis if address is empty.
+func (mgr *Manager) getReplicationInfo(ctx context.Context, address string) (map[string]string, error) {
+	client := mgr.getClient()
+	if address != "" {
+		settings := *mgr.clientSettings
+		settings.Host = address
+		settings.RedisType = ""
+		client = newClient(&settings)
+		defer func() {
+			_ = client.Close()
+		}()
+	}
+
+	result, err := client.Info(ctx, "Replication").Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseInfo(result), nil
+}
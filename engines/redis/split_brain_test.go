@@ -0,0 +1,54 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestGetMemberView(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("master", func(t *testing.T) {
+		manager, _ := mockRedis(t, masterReplicationInfo)
+
+		view, err := manager.GetMemberView(ctx, "")
+		assert.Nil(t, err)
+		assert.Equal(t, models.PRIMARY, view.Role)
+		assert.True(t, view.Writable)
+	})
+
+	t.Run("replica by address", func(t *testing.T) {
+		manager, _ := mockRedis(t, masterReplicationInfo)
+		_, replica := mockRedis(t, "# Replication\r\nrole:slave\r\nmaster_host:redis-0\r\nmaster_port:6379\r\n")
+
+		view, err := manager.GetMemberView(ctx, replica.Addr())
+		assert.Nil(t, err)
+		assert.Equal(t, replica.Addr(), view.Address)
+		assert.Equal(t, models.SECONDARY, view.Role)
+		assert.False(t, view.Writable)
+		assert.Equal(t, "redis-0:6379", view.Leader)
+	})
+}
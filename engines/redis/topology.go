@@ -0,0 +1,142 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package redis
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// GetTopology lists the master and the replicas known by sentinel, which is the
+// source of truth of the roles as in GetReplicaRole, but has no lag of them.
+// Without sentinel, the members are read from INFO replication of the local redis.
+func (mgr *Manager) GetTopology(ctx context.Context) (*models.Topology, error) {
+	if mgr.sentinelClient != nil {
+		topology, err := mgr.getTopologyFromSentinel(ctx)
+		if err == nil {
+			return topology, nil
+		}
+		mgr.Logger.Info("failed to get topology from Sentinel, try to get from Redis", "error", err.Error())
+	}
+
+	result, err := mgr.getClient().Info(ctx, "Replication").Result()
+	if err != nil {
+		mgr.Logger.Info("Replication info query failed", "error", err.Error())
+		return nil, err
+	}
+	return mgr.topologyFromInfo(parseInfo(result)), nil
+}
+
+func (mgr *Manager) getTopologyFromSentinel(ctx context.Context) (*models.Topology, error) {
+	master, err := mgr.sentinelClient.Master(ctx, mgr.masterName).Result()
+	if err != nil {
+		return nil, err
+	}
+	replicas, err := mgr.sentinelClient.Replicas(ctx, mgr.masterName).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	topology := &models.Topology{
+		Members: []models.Member{mgr.sentinelMember(master, models.MASTER)},
+	}
+	for _, replica := range replicas {
+		member := mgr.sentinelMember(replica, models.SLAVE)
+		if replica["master-link-status"] == "err" && member.Health == models.HealthHealthy {
+			member.Health = models.HealthRecovering
+		}
+		topology.Members = append(topology.Members, member)
+	}
+	return topology, nil
+}
+
+// sentinelMember builds the member from the fields of SENTINEL MASTER or SENTINEL REPLICAS.
+func (mgr *Manager) sentinelMember(fields map[string]string, role string) models.Member {
+	member := models.NewMember(net.JoinHostPort(fields["ip"], fields["port"]), role)
+	member.Name = fields["name"]
+	member.Self = mgr.isCurrentRedis(fields["ip"], fields["port"])
+	for _, flag := range strings.Split(fields["flags"], ",") {
+		switch flag {
+		case "s_down", "o_down", "disconnected":
+			member.Health = models.HealthUnhealthy
+		}
+	}
+	return member
+}
+
+// topologyFromInfo builds the members from INFO replication, a master lists its
+// replicas in the `slave<n>` fields, e.g. `slave0:ip=10.0.0.2,port=6379,state=online,offset=100,lag=0`.
+func (mgr *Manager) topologyFromInfo(info map[string]string) *models.Topology {
+	topology := &models.Topology{}
+	self := models.NewMember(net.JoinHostPort(mgr.currentRedisHost, mgr.currentRedisPort), info["role"])
+	self.Self = true
+
+	if info["role"] == models.SLAVE {
+		if info["master_link_status"] != "up" {
+			self.Health = models.HealthRecovering
+		}
+		topology.Members = append(topology.Members, self)
+		master := models.NewMember(net.JoinHostPort(info["master_host"], info["master_port"]), models.MASTER)
+		master.Health = models.HealthUnknown
+		topology.Members = append(topology.Members, master)
+		return topology
+	}
+
+	topology.Members = append(topology.Members, self)
+	masterOffset := cast.ToInt64(info["master_repl_offset"])
+	for _, fields := range parseSlaves(info) {
+		member := models.NewMember(net.JoinHostPort(fields["ip"], fields["port"]), models.SLAVE)
+		if fields["state"] != "online" {
+			member.Health = models.HealthRecovering
+		}
+		lagBytes := max(masterOffset-cast.ToInt64(fields["offset"]), 0)
+		member.LagBytes = &lagBytes
+		lagSeconds := cast.ToInt64(fields["lag"])
+		member.LagSeconds = &lagSeconds
+		topology.Members = append(topology.Members, member)
+	}
+	return topology
+}
+
+// parseSlaves parses the `slave<n>` fields of INFO replication on a master.
+func parseSlaves(info map[string]string) []map[string]string {
+	var slaves []map[string]string
+	for i := 0; i < cast.ToInt(info["connected_slaves"]); i++ {
+		fields := map[string]string{}
+		for _, field := range strings.Split(info["slave"+cast.ToString(i)], ",") {
+			if key, value, found := strings.Cut(field, "="); found {
+				fields[key] = value
+			}
+		}
+		if len(fields) != 0 {
+			slaves = append(slaves, fields)
+		}
+	}
+	return slaves
+}
+
+func (mgr *Manager) isCurrentRedis(host, port string) bool {
+	return strings.HasPrefix(host, mgr.currentRedisHost) && port == mgr.currentRedisPort
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package redis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/alicebob/miniredis/v2/server"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func writeFields(c *server.Peer, fields ...string) {
+	c.WriteLen(len(fields))
+	for _, field := range fields {
+		c.WriteBulk(field)
+	}
+}
+
+func TestGetTopology(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("master", func(t *testing.T) {
+		manager, _ := mockRedis(t, "# Replication\r\nrole:master\r\nconnected_slaves:2\r\n"+
+			"slave0:ip=10.0.0.2,port=6379,state=online,offset=1000,lag=0\r\n"+
+			"slave1:ip=10.0.0.3,port=6379,state=wait_bgsave,offset=0,lag=3\r\n"+
+			"master_repl_offset:1024\r\n")
+
+		topology, err := manager.GetTopology(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, topology.Members, 3)
+		assert.True(t, topology.Members[0].Self)
+		assert.Equal(t, models.PRIMARY, topology.Members[0].Role)
+		assert.Equal(t, "10.0.0.2:6379", topology.Members[1].Address)
+		assert.Equal(t, models.SECONDARY, topology.Members[1].Role)
+		assert.Equal(t, int64(24), *topology.Members[1].LagBytes)
+		assert.Equal(t, models.HealthHealthy, topology.Members[1].Health)
+		assert.Equal(t, models.HealthRecovering, topology.Members[2].Health)
+		assert.Equal(t, int64(3), *topology.Members[2].LagSeconds)
+	})
+
+	t.Run("replica", func(t *testing.T) {
+		manager, _ := mockRedis(t, "# Replication\r\nrole:slave\r\nmaster_host:redis-0\r\nmaster_port:6379\r\n"+
+			"master_link_status:down\r\n")
+
+		topology, err := manager.GetTopology(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, topology.Members, 2)
+		assert.Equal(t, models.SECONDARY, topology.Members[0].Role)
+		assert.Equal(t, models.HealthRecovering, topology.Members[0].Health)
+		assert.Equal(t, "redis-0:6379", topology.Members[1].Address)
+		assert.Equal(t, models.PRIMARY, topology.Members[1].Role)
+	})
+
+	t.Run("sentinel", func(t *testing.T) {
+		manager, _ := mockRedis(t, masterReplicationInfo)
+		manager.currentRedisHost = "10.0.0.2"
+		manager.currentRedisPort = "6379"
+
+		sentinel := miniredis.RunT(t)
+		sentinel.Server().SetPreHook(func(c *server.Peer, cmd string, args ...string) bool {
+			if !strings.EqualFold(cmd, "sentinel") || len(args) != 2 {
+				return false
+			}
+			switch strings.ToLower(args[0]) {
+			case "master":
+				writeFields(c, "name", args[1], "ip", "10.0.0.1", "port", "6379", "flags", "master")
+			case "replicas":
+				c.WriteLen(2)
+				writeFields(c, "name", "10.0.0.2:6379", "ip", "10.0.0.2", "port", "6379", "flags", "slave",
+					"master-link-status", "ok")
+				writeFields(c, "name", "10.0.0.3:6379", "ip", "10.0.0.3", "port", "6379", "flags", "s_down,slave",
+					"master-link-status", "err")
+			default:
+				return false
+			}
+			return true
+		})
+		manager.sentinelClient = redis.NewSentinelClient(&redis.Options{Addr: sentinel.Addr()})
+		defer func() {
+			_ = manager.sentinelClient.Close()
+			manager.sentinelClient = nil
+		}()
+
+		topology, err := manager.GetTopology(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, topology.Members, 3)
+		assert.Equal(t, "10.0.0.1:6379", topology.Members[0].Address)
+		assert.Equal(t, models.PRIMARY, topology.Members[0].Role)
+		assert.False(t, topology.Members[0].Self)
+		assert.True(t, topology.Members[1].Self)
+		assert.Equal(t, models.HealthHealthy, topology.Members[1].Health)
+		assert.Equal(t, models.HealthUnhealthy, topology.Members[2].Health)
+	})
+}
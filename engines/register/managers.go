@@ -21,6 +21,7 @@ package register
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -28,14 +29,20 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/clickhouse"
+	"github.com/apecloud/dbctl/engines/elasticsearch"
 	"github.com/apecloud/dbctl/engines/etcd"
+	"github.com/apecloud/dbctl/engines/fake"
 	"github.com/apecloud/dbctl/engines/foxlake"
+	"github.com/apecloud/dbctl/engines/kafka"
 	"github.com/apecloud/dbctl/engines/models"
 	"github.com/apecloud/dbctl/engines/mongodb"
 	"github.com/apecloud/dbctl/engines/mysql"
 	"github.com/apecloud/dbctl/engines/nebula"
+	"github.com/apecloud/dbctl/engines/oceanbase"
 	"github.com/apecloud/dbctl/engines/opengauss"
 	"github.com/apecloud/dbctl/engines/oracle"
+	"github.com/apecloud/dbctl/engines/plugin"
 	"github.com/apecloud/dbctl/engines/polardbx"
 	"github.com/apecloud/dbctl/engines/postgres"
 	"github.com/apecloud/dbctl/engines/postgres/apecloudpostgres"
@@ -43,15 +50,21 @@ import (
 	"github.com/apecloud/dbctl/engines/pulsar"
 	"github.com/apecloud/dbctl/engines/redis"
 	"github.com/apecloud/dbctl/engines/wesql"
+	"github.com/apecloud/dbctl/engines/zookeeper"
+	utilconfig "github.com/apecloud/dbctl/util/config"
 )
 
 type ManagerNewFunc func() (engines.DBManager, error)
 
 var managerNewFunctions = make(map[string]ManagerNewFunc)
 
-// dbctl runs with a single database engine instance at a time,
-// so only one dbManager is initialized and cached here during execution.
+// dbManager is the manager of the default engine instance, which is served by the
+// routes without instance, e.g. /v1.0/getrole.
 var dbManager engines.DBManager
+
+// instanceManagers are the managers of the named engine instances running in the
+// same pod, e.g. the sentinel of redis, which are served by /v1.0/<instance>/<op>.
+var instanceManagers = map[string]engines.DBManager{}
 var fs = afero.NewOsFs()
 
 func init() {
@@ -64,12 +77,19 @@ func init() {
 	EngineRegister(models.PostgreSQL, vanillapostgres.NewManager, postgres.NewCommands)
 	EngineRegister(models.VanillaPostgreSQL, vanillapostgres.NewManager, postgres.NewCommands)
 	EngineRegister(models.ApecloudPostgreSQL, apecloudpostgres.NewManager, postgres.NewCommands)
-	EngineRegister(models.FoxLake, nil, foxlake.NewCommands)
-	EngineRegister(models.Nebula, nil, nebula.NewCommands)
-	EngineRegister(models.PulsarProxy, nil, pulsar.NewProxyCommands)
-	EngineRegister(models.PulsarBroker, nil, pulsar.NewBrokerCommands)
-	EngineRegister(models.Oracle, nil, oracle.NewCommands)
-	EngineRegister(models.OpenGauss, nil, opengauss.NewCommands)
+	EngineRegister(models.FoxLake, foxlake.NewManager, foxlake.NewCommands)
+	EngineRegister(models.Nebula, nebula.NewManager, nebula.NewCommands)
+	EngineRegister(models.Oceanbase, oceanbase.NewManager, oceanbase.NewCommands)
+	EngineRegister(models.PulsarProxy, pulsar.NewProxyManager, pulsar.NewProxyCommands)
+	EngineRegister(models.PulsarBroker, pulsar.NewBrokerManager, pulsar.NewBrokerCommands)
+	EngineRegister(models.Oracle, oracle.NewManager, oracle.NewCommands)
+	EngineRegister(models.OpenGauss, opengauss.NewManager, opengauss.NewCommands)
+	EngineRegister(models.Kafka, kafka.NewManager, nil)
+	EngineRegister(models.ZooKeeper, zookeeper.NewManager, nil)
+	EngineRegister(models.ClickHouse, clickhouse.NewManager, nil)
+	EngineRegister(models.Elasticsearch, elasticsearch.NewManager, nil)
+	EngineRegister(models.OpenSearch, elasticsearch.NewManager, nil)
+	EngineRegister(models.Fake, fake.NewManager, nil)
 }
 
 func EngineRegister(characterType models.EngineType, newFunc ManagerNewFunc, newCommand engines.NewCommandFunc) {
@@ -78,6 +98,27 @@ func EngineRegister(characterType models.EngineType, newFunc ManagerNewFunc, new
 	engines.NewCommandFuncs[string(characterType)] = newCommand
 }
 
+// LoadPlugins registers the engine plugins under the plugin dir, and returns their
+// engine types. A plugin can't replace the engine built in dbctl.
+func LoadPlugins(pluginDir string) ([]string, error) {
+	plugins, err := plugin.Discover(pluginDir)
+	if err != nil {
+		return nil, err
+	}
+
+	engineTypes := make([]string, 0, len(plugins))
+	for engineType, engineDir := range plugins {
+		if _, ok := managerNewFunctions[strings.ToLower(engineType)]; ok {
+			return nil, errors.Errorf("plugin %s conflicts with the built-in engine", engineType)
+		}
+		ctrl.Log.Info("Load engine plugin", "engine", engineType, "dir", engineDir)
+		EngineRegister(models.EngineType(engineType), plugin.NewManagerFunc(engineType, engineDir), nil)
+		engineTypes = append(engineTypes, engineType)
+	}
+	sort.Strings(engineTypes)
+	return engineTypes, nil
+}
+
 func GetManagerNewFunc(characterType string) ManagerNewFunc {
 	key := strings.ToLower(characterType)
 	return managerNewFunctions[key]
@@ -125,3 +166,53 @@ func InitDBManager(engineType string) error {
 	dbManager = mgr
 	return nil
 }
+
+// InitInstanceDBManager initializes the manager of a named instance, with the envs
+// prefixed with the instance name and the `instances.<instance>` config file section.
+func InitInstanceDBManager(instance, engineType string) error {
+	if err := utilconfig.ValidateInstanceName(instance); err != nil {
+		return err
+	}
+	if _, ok := instanceManagers[instance]; ok {
+		return nil
+	}
+	if engineType == "" {
+		return errors.Errorf("engine type of instance %s not set", instance)
+	}
+
+	ctrl.Log.Info("Initialize DB manager", "instance", instance, "engine", engineType)
+	newFunc := GetManagerNewFunc(engineType)
+	if newFunc == nil {
+		return errors.Errorf("no db manager for engine %s", engineType)
+	}
+	return utilconfig.WithInstance(instance, func() error {
+		mgr, err := newFunc()
+		if err != nil {
+			return errors.Wrapf(err, "initialize instance %s failed", instance)
+		}
+		instanceManagers[instance] = mgr
+		return nil
+	})
+}
+
+// GetInstanceDBManager returns the manager of a named instance,
+// the default manager is returned if instance is empty.
+func GetInstanceDBManager(instance string) (engines.DBManager, error) {
+	if instance == "" {
+		return GetDBManager()
+	}
+	if mgr, ok := instanceManagers[instance]; ok {
+		return mgr, nil
+	}
+	return nil, errors.Errorf("no db manager for instance %s", instance)
+}
+
+// GetInstances returns the names of the named instances in order.
+func GetInstances() []string {
+	instances := make([]string, 0, len(instanceManagers))
+	for instance := range instanceManagers {
+		instances = append(instances, instance)
+	}
+	sort.Strings(instances)
+	return instances
+}
@@ -108,3 +108,44 @@ func TestInitDBManager(t *testing.T) {
 		assert.Nil(t, err)
 	})
 }
+
+func TestInitInstanceDBManager(t *testing.T) {
+	defer func() {
+		viper.Reset()
+		instanceManagers = map[string]engines.DBManager{}
+	}()
+
+	t.Setenv("SENTINEL_FAKE_PORT", "26379")
+	viper.AutomaticEnv()
+	var port string
+	EngineRegister(fakeEngine, func() (engines.DBManager, error) {
+		port = viper.GetString("FAKE_PORT")
+		return &engines.MockManager{}, nil
+	}, nil)
+
+	t.Run("invalid instance name", func(t *testing.T) {
+		err := InitInstanceDBManager("Sentinel_1", fakeEngine)
+		assert.NotNil(t, err)
+		assert.ErrorContains(t, err, "invalid instance name")
+	})
+
+	t.Run("engine type not set", func(t *testing.T) {
+		err := InitInstanceDBManager("sentinel", "")
+		assert.NotNil(t, err)
+		assert.ErrorContains(t, err, "engine type of instance sentinel not set")
+	})
+
+	t.Run("instance with prefixed envs", func(t *testing.T) {
+		err := InitInstanceDBManager("sentinel", fakeEngine)
+		assert.Nil(t, err)
+		assert.Equal(t, "26379", port)
+		assert.False(t, viper.IsSet("FAKE_PORT"))
+		assert.Equal(t, []string{"sentinel"}, GetInstances())
+
+		_, err = GetInstanceDBManager("sentinel")
+		assert.Nil(t, err)
+		_, err = GetInstanceDBManager("not-exist")
+		assert.NotNil(t, err)
+		assert.ErrorContains(t, err, "no db manager for instance not-exist")
+	})
+}
@@ -1,5 +1,15 @@
 package engines
 
+import "regexp"
+
+var parameterNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
 func AddSingleQuote(str string) string {
 	return "'" + str + "'"
 }
+
+// IsValidParameterName checks the name of a parameter to reconfigure, which is
+// put into the statements as it is.
+func IsValidParameterName(name string) bool {
+	return parameterNamePattern.MatchString(name)
+}
@@ -27,6 +27,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/conformance"
 	"github.com/apecloud/dbctl/engines/mysql"
 )
 
@@ -63,3 +64,26 @@ func TestNewManager(t *testing.T) {
 		assert.NotNil(t, manager)
 	})
 }
+
+func TestConformance(t *testing.T) {
+	manager, mock, _ := mockDatabase(t)
+
+	conformance.Run(t, conformance.Suite{
+		Manager: manager,
+		Prepare: func(method conformance.Method) {
+			switch method {
+			case conformance.IsDBStartupReady:
+				mock.ExpectPing()
+			case conformance.GetReplicaRole, conformance.GetReplicaRoleInfo:
+				mock.ExpectQuery("select CURRENT_LEADER, ROLE, SERVER_ID from information_schema.wesql_cluster_local").
+					WillReturnRows(sqlmock.NewRows([]string{"CURRENT_LEADER", "ROLE", "SERVER_ID"}).AddRow(fakePodName, "Leader", "1"))
+			case conformance.Exec:
+				mock.ExpectExec("create database test").WillReturnResult(sqlmock.NewResult(0, 1))
+			case conformance.Query:
+				mock.ExpectQuery("select 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+			}
+		},
+		ExecSQL:  "create database test",
+		QuerySQL: "select 1",
+	})
+}
@@ -0,0 +1,68 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package wesql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const memberViewSQL = "select ROLE, CURRENT_TERM, CURRENT_LEADER, SERVER_READY_FOR_RW from information_schema.wesql_cluster_local"
+
+// GetMemberView connects to the member by the MySQL port, since the address in
+// the topology is the consensus address of the member.
+func (mgr *Manager) GetMemberView(ctx context.Context, address string) (*models.MemberView, error) {
+	db, err := mgr.MemberDB(consensusHost(address))
+	if err != nil {
+		return nil, err
+	}
+
+	var role, leader, readyForRW string
+	var term int64
+	if err = db.QueryRowContext(ctx, memberViewSQL).Scan(&role, &term, &leader, &readyForRW); err != nil {
+		return nil, errors.Wrapf(err, "query member %s failed", address)
+	}
+
+	info := models.NewRoleInfo(role)
+	view := &models.MemberView{
+		Address:  address,
+		Role:     info.Role,
+		Writable: info.Writable && strings.EqualFold(readyForRW, "yes"),
+		Term:     term,
+	}
+	if info.Role != models.LEADER {
+		view.Leader = leader
+	}
+	return view, nil
+}
+
+// FenceMember connects to the member by the MySQL port, see GetMemberView.
+func (mgr *Manager) FenceMember(ctx context.Context, address string) error {
+	return mgr.Manager.FenceMember(ctx, consensusHost(address))
+}
+
+func consensusHost(address string) string {
+	host, _, _ := strings.Cut(address, ":")
+	return host
+}
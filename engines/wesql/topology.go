@@ -0,0 +1,84 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package wesql
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const (
+	clusterLocalSQL  = "select SERVER_ID, CURRENT_TERM, CURRENT_LEADER, ROLE, COMMIT_INDEX from information_schema.wesql_cluster_local"
+	clusterGlobalSQL = "select SERVER_ID, IP_PORT, ROLE, APPLIED_INDEX from information_schema.wesql_cluster_global"
+)
+
+// GetTopology reads wesql_cluster_global, which is only filled on the leader, so
+// a follower reports itself and the leader it follows. The lag is in the log
+// entries applied behind the commit index of the leader.
+func (mgr *Manager) GetTopology(ctx context.Context) (*models.Topology, error) {
+	var serverID, curLeader, role string
+	var term, commitIndex int64
+	err := mgr.DB.QueryRowContext(ctx, clusterLocalSQL).Scan(&serverID, &term, &curLeader, &role, &commitIndex)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error executing %s", clusterLocalSQL)
+	}
+
+	rows, err := mgr.DB.QueryContext(ctx, clusterGlobalSQL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error executing %s", clusterGlobalSQL)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	topology := &models.Topology{Term: term}
+	for rows.Next() {
+		var id, address, memberRole string
+		var appliedIndex int64
+		if err = rows.Scan(&id, &address, &memberRole, &appliedIndex); err != nil {
+			return nil, err
+		}
+		member := models.NewMember(address, memberRole)
+		member.Self = id == serverID
+		lagEntries := max(commitIndex-appliedIndex, 0)
+		member.LagEntries = &lagEntries
+		topology.Members = append(topology.Members, member)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(topology.Members) > 0 {
+		return topology, nil
+	}
+
+	self := models.NewMember("", role)
+	self.Name = mgr.CurrentMemberName
+	self.Self = true
+	topology.Members = append(topology.Members, self)
+	if curLeader != "" {
+		leader := models.NewMember(curLeader, models.LEADER)
+		leader.Health = models.HealthUnknown
+		topology.Members = append(topology.Members, leader)
+	}
+	return topology, nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package wesql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+func TestGetTopology(t *testing.T) {
+	ctx := context.TODO()
+	manager, mock, _ := mockDatabase(t)
+	localColumns := []string{"SERVER_ID", "CURRENT_TERM", "CURRENT_LEADER", "ROLE", "COMMIT_INDEX"}
+	globalColumns := []string{"SERVER_ID", "IP_PORT", "ROLE", "APPLIED_INDEX"}
+
+	t.Run("leader", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(clusterLocalSQL)).
+			WillReturnRows(sqlmock.NewRows(localColumns).AddRow("1", 3, "wesql-0:13306", "Leader", 100))
+		mock.ExpectQuery(regexp.QuoteMeta(clusterGlobalSQL)).
+			WillReturnRows(sqlmock.NewRows(globalColumns).
+				AddRow("1", "wesql-0:13306", "Leader", 100).
+				AddRow("2", "wesql-1:13306", "Follower", 90).
+				AddRow("3", "wesql-2:13306", "Learner", 100))
+
+		topology, err := manager.GetTopology(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), topology.Term)
+		assert.Len(t, topology.Members, 3)
+		assert.Equal(t, models.LEADER, topology.Members[0].Role)
+		assert.True(t, topology.Members[0].Self)
+		assert.Equal(t, models.FOLLOWER, topology.Members[1].Role)
+		assert.Equal(t, "Follower", topology.Members[1].NativeRole)
+		assert.Equal(t, int64(10), *topology.Members[1].LagEntries)
+		assert.Equal(t, models.LEARNER, topology.Members[2].Role)
+	})
+
+	t.Run("follower", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(clusterLocalSQL)).
+			WillReturnRows(sqlmock.NewRows(localColumns).AddRow("2", 3, "wesql-0:13306", "Follower", 90))
+		mock.ExpectQuery(regexp.QuoteMeta(clusterGlobalSQL)).
+			WillReturnRows(sqlmock.NewRows(globalColumns))
+
+		topology, err := manager.GetTopology(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, topology.Members, 2)
+		assert.True(t, topology.Members[0].Self)
+		assert.Equal(t, models.FOLLOWER, topology.Members[0].Role)
+		assert.Equal(t, "wesql-0:13306", topology.Members[1].Address)
+		assert.Equal(t, models.LEADER, topology.Members[1].Role)
+		assert.Equal(t, models.HealthUnknown, topology.Members[1].Health)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
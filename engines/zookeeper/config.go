@@ -0,0 +1,88 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package zookeeper
+
+import (
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/apecloud/dbctl/constant"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+const (
+	// configSection is the config file section of ZooKeeper, the address is of the
+	// client port, which serves the four-letter words too.
+	configSection = "zookeeper"
+
+	defaultHost    = "127.0.0.1"
+	defaultPort    = 2181
+	defaultTimeout = 5 * time.Second
+
+	// EnvAdminServerPort is the port of the AdminServer, the commands are sent to
+	// the AdminServer instead of the four-letter words if it's set, which is the
+	// choice if the four-letter words are not in 4lw.commands.whitelist.
+	EnvAdminServerPort = "ZOOKEEPER_ADMIN_SERVER_PORT"
+	// EnvBitnamiAdminServerPort is the port of the AdminServer of the bitnami images.
+	EnvBitnamiAdminServerPort = "ZOO_ADMIN_SERVER_PORT_NUMBER"
+)
+
+type Config struct {
+	Addr string
+	// AdminURL is the base URL of the AdminServer, empty to send the four-letter
+	// words to the client port.
+	AdminURL  string
+	TLSConfig *tls.Config
+	Timeout   time.Duration
+
+	*utilconfig.EngineCredentials
+}
+
+func NewConfig() (*Config, error) {
+	engineConfig, err := utilconfig.GetEngineConfig(configSection)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{
+		EngineCredentials: utilconfig.NewEngineCredentials(configSection,
+			utilconfig.CredentialLookup{Name: constant.ConfigKeyUserName, Envs: []string{constant.KBEnvServiceUser}},
+			utilconfig.CredentialLookup{Name: constant.ConfigKeyPassword, Envs: []string{constant.KBEnvServicePassword}}),
+		Addr:    engineConfig.Addr(defaultHost, defaultPort),
+		Timeout: defaultTimeout,
+	}
+	if config.TLSConfig, err = engineConfig.TLS.ClientConfig(); err != nil {
+		return nil, err
+	}
+	if engineConfig.ReadTimeout != 0 {
+		config.Timeout = engineConfig.ReadTimeout
+	}
+	for _, env := range []string{EnvAdminServerPort, EnvBitnamiAdminServerPort} {
+		if viper.IsSet(env) {
+			config.AdminURL = fmt.Sprintf("http://%s:%d", defaultHost, viper.GetInt(env))
+			break
+		}
+	}
+	config.LoadCredentials(engineConfig)
+	return config, nil
+}
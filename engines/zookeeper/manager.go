@@ -0,0 +1,113 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package zookeeper
+
+import (
+	"context"
+	"net/http"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+// Manager probes the local server by the four-letter words or the AdminServer,
+// and reads and writes the znodes by a session to the client port.
+type Manager struct {
+	engines.DBManagerBase
+	config *Config
+	client *http.Client
+	// dial opens a session for the znode operations, it's replaced in tests.
+	dial func() (znodeConn, error)
+}
+
+var _ engines.DBManager = &Manager{}
+var _ engines.RoleInfoGetter = &Manager{}
+
+func NewManager() (engines.DBManager, error) {
+	logger := ctrl.Log.WithName("ZooKeeper")
+	config, err := NewConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	managerBase, err := engines.NewDBManagerBase(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := &Manager{
+		DBManagerBase: *managerBase,
+		config:        config,
+		client:        &http.Client{Timeout: config.Timeout},
+	}
+	mgr.dial = mgr.dialZooKeeper
+
+	utilconfig.OnCredentialsChange(mgr.reloadCredentials)
+	return mgr, nil
+}
+
+// reloadCredentials reloads the credentials, which are read for every session.
+func (mgr *Manager) reloadCredentials() {
+	if err := mgr.config.ReloadCredentials(); err != nil {
+		mgr.Logger.Info("reload credentials failed", "error", err.Error())
+		return
+	}
+	mgr.Logger.Info("credentials reloaded")
+}
+
+// IsDBStartupReady checks the server is serving requests, which is after it
+// joins the quorum, or starts as a standalone server.
+func (mgr *Manager) IsDBStartupReady() bool {
+	if mgr.DBStartupReady {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mgr.config.Timeout)
+	defer cancel()
+	if _, err := mgr.getServerState(ctx); err != nil {
+		mgr.Logger.Info("DB is not ready", "error", err.Error())
+		return false
+	}
+
+	mgr.DBStartupReady = true
+	mgr.Logger.Info("DB startup ready")
+	return true
+}
+
+// GetReplicaRole returns the server state, one of leader, follower, observer,
+// standalone and read-only.
+func (mgr *Manager) GetReplicaRole(ctx context.Context) (string, error) {
+	return mgr.getServerState(ctx)
+}
+
+func (mgr *Manager) GetReplicaRoleInfo(ctx context.Context) (*models.RoleInfo, error) {
+	state, err := mgr.getServerState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewRoleInfo(state), nil
+}
+
+func (mgr *Manager) ShutDownWithWait() {
+	mgr.client.CloseIdleConnections()
+}
@@ -0,0 +1,326 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package zookeeper
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/conformance"
+	"github.com/apecloud/dbctl/engines/models"
+	utilconfig "github.com/apecloud/dbctl/util/config"
+)
+
+const srvrLeader = `Zookeeper version: 3.8.4-9316c2a7a97e1666d8f4593f34dd6fc36ecc436c, built on 2024-02-12 22:16 UTC
+Latency min/avg/max: 0/0.0/0
+Received: 12
+Sent: 11
+Connections: 1
+Outstanding: 0
+Zxid: 0x100000002
+Mode: leader
+Node count: 5
+Proposal sizes last/min/max: -1/-1/-1
+`
+
+// mockFourLetterWords serves the four-letter words on a local port, the responses
+// are by the words.
+type mockFourLetterWords struct {
+	sync.Mutex
+	responses map[string]string
+}
+
+func (m *mockFourLetterWords) set(word, resp string) {
+	m.Lock()
+	defer m.Unlock()
+	m.responses[word] = resp
+}
+
+func (m *mockFourLetterWords) serve(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			word := make([]byte, 4)
+			if _, err = conn.Read(word); err == nil {
+				m.Lock()
+				resp, ok := m.responses[string(word)]
+				m.Unlock()
+				if !ok {
+					resp = string(word) + " is not executed because it is not in the whitelist.\n"
+				}
+				_, _ = conn.Write([]byte(resp))
+			}
+			_ = conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func mockManager(addr string) *Manager {
+	return &Manager{
+		DBManagerBase: engines.DBManagerBase{
+			CurrentMemberName: "zookeeper-0",
+			Logger:            ctrl.Log.WithName("ZooKeeper-TEST"),
+		},
+		config: &Config{
+			Addr:              addr,
+			Timeout:           time.Second,
+			EngineCredentials: utilconfig.NewEngineCredentials(configSection, utilconfig.CredentialLookup{}, utilconfig.CredentialLookup{}),
+		},
+		client: http.DefaultClient,
+	}
+}
+
+func TestFourLetterWords(t *testing.T) {
+	ctx := context.TODO()
+	words := &mockFourLetterWords{responses: map[string]string{"srvr": srvrLeader}}
+	mgr := mockManager(words.serve(t))
+
+	assert.True(t, mgr.IsDBStartupReady())
+	role, err := mgr.GetReplicaRole(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.LEADER, role)
+
+	words.set("srvr", strings.Replace(srvrLeader, "Mode: leader", "Mode: observer", 1))
+	info, err := engines.GetReplicaRoleInfo(ctx, mgr)
+	require.NoError(t, err)
+	assert.Equal(t, &models.RoleInfo{Role: models.LEARNER, NativeRole: "observer", Health: models.HealthHealthy}, info)
+
+	words.set("srvr", strings.Replace(srvrLeader, "Mode: leader", "Mode: read-only", 1))
+	info, err = engines.GetReplicaRoleInfo(ctx, mgr)
+	require.NoError(t, err)
+	assert.Equal(t, &models.RoleInfo{Role: models.FOLLOWER, NativeRole: "read-only", Health: models.HealthUnhealthy}, info)
+
+	words.set("srvr", "This ZooKeeper instance "+notServing+"\n")
+	_, err = mgr.GetReplicaRole(ctx)
+	assert.ErrorContains(t, err, notServing)
+
+	mgr = mockManager(mgr.config.Addr)
+	assert.False(t, mgr.IsDBStartupReady())
+
+	words.Lock()
+	delete(words.responses, "srvr")
+	words.Unlock()
+	_, err = mgr.GetReplicaRole(ctx)
+	assert.ErrorContains(t, err, notWhitelisted)
+}
+
+func TestAdminServer(t *testing.T) {
+	ctx := context.TODO()
+	state := "follower"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != srvrCommandPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if state == "" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"command":"server_stats","error":"This ZooKeeper instance is not currently serving requests"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"version":"3.8.4","read_only":false,"server_stats":{"server_state":"` + state + `","node_count":5},"command":"server_stats","error":null}`))
+	}))
+	t.Cleanup(server.Close)
+
+	mgr := mockManager("127.0.0.1:1")
+	mgr.config.AdminURL = server.URL
+	role, err := mgr.GetReplicaRole(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.FOLLOWER, role)
+
+	state = ""
+	_, err = mgr.GetReplicaRole(ctx)
+	assert.ErrorContains(t, err, "not currently serving requests")
+	assert.False(t, mgr.IsDBStartupReady())
+}
+
+// mockZnodes is a session on the znodes in memory.
+type mockZnodes struct {
+	data map[string]string
+	auth string
+}
+
+func (m *mockZnodes) AddAuth(scheme string, auth []byte) error {
+	m.auth = scheme + ":" + string(auth)
+	return nil
+}
+
+func (m *mockZnodes) Get(p string) ([]byte, *zk.Stat, error) {
+	data, ok := m.data[p]
+	if !ok {
+		return nil, nil, zk.ErrNoNode
+	}
+	return []byte(data), &zk.Stat{DataLength: int32(len(data))}, nil
+}
+
+func (m *mockZnodes) Children(p string) ([]string, *zk.Stat, error) {
+	if _, ok := m.data[p]; !ok {
+		return nil, nil, zk.ErrNoNode
+	}
+	var children []string
+	for child := range m.data {
+		if child != "/" && path.Dir(child) == p {
+			children = append(children, path.Base(child))
+		}
+	}
+	return children, &zk.Stat{NumChildren: int32(len(children))}, nil
+}
+
+func (m *mockZnodes) Exists(p string) (bool, *zk.Stat, error) {
+	_, ok := m.data[p]
+	return ok, &zk.Stat{}, nil
+}
+
+func (m *mockZnodes) Create(p string, data []byte, flags int32, acl []zk.ACL) (string, error) {
+	if _, ok := m.data[p]; ok {
+		return "", zk.ErrNodeExists
+	}
+	if flags&zk.FlagSequence != 0 {
+		p += "0000000001"
+	}
+	m.data[p] = string(data)
+	return p, nil
+}
+
+func (m *mockZnodes) Set(p string, data []byte, version int32) (*zk.Stat, error) {
+	if _, ok := m.data[p]; !ok {
+		return nil, zk.ErrNoNode
+	}
+	m.data[p] = string(data)
+	return &zk.Stat{}, nil
+}
+
+func (m *mockZnodes) Delete(p string, version int32) error {
+	if _, ok := m.data[p]; !ok {
+		return zk.ErrNoNode
+	}
+	delete(m.data, p)
+	return nil
+}
+
+func (m *mockZnodes) Close() {}
+
+func mockZnodeManager() (*Manager, *mockZnodes) {
+	znodes := &mockZnodes{data: map[string]string{
+		"/":               "",
+		"/brokers":        "",
+		"/brokers/ids":    "",
+		"/brokers/topics": "",
+	}}
+	mgr := mockManager("127.0.0.1:1")
+	mgr.dial = func() (znodeConn, error) {
+		return znodes, nil
+	}
+	return mgr, znodes
+}
+
+func TestQuery(t *testing.T) {
+	ctx := context.TODO()
+	mgr, znodes := mockZnodeManager()
+
+	data, err := mgr.Query(ctx, "ls /brokers")
+	require.NoError(t, err)
+	node := &znode{}
+	require.NoError(t, json.Unmarshal(data, node))
+	assert.Equal(t, []string{"ids", "topics"}, node.Children)
+	assert.Equal(t, int32(2), node.Stat.NumChildren)
+
+	znodes.data["/brokers/ids/0"] = `{"host":"kafka-0","port":9092}`
+	data, err = mgr.Query(ctx, "get /brokers/ids/0")
+	require.NoError(t, err)
+	node = &znode{}
+	require.NoError(t, json.Unmarshal(data, node))
+	assert.Equal(t, `{"host":"kafka-0","port":9092}`, *node.Data)
+
+	_, err = mgr.Query(ctx, "stat /missing")
+	assert.ErrorIs(t, err, zk.ErrNoNode)
+	_, err = mgr.Query(ctx, "get")
+	assert.ErrorContains(t, err, "the usage is")
+	_, err = mgr.Query(ctx, "rmr /brokers")
+	assert.ErrorContains(t, err, "unknown command")
+
+	// the session is authenticated by the digest
+	mgr.config.SetCredentials("admin", "secret")
+	_, err = mgr.Query(ctx, "stat /brokers")
+	require.NoError(t, err)
+	assert.Equal(t, "digest:admin:secret", znodes.auth)
+}
+
+func TestExec(t *testing.T) {
+	ctx := context.TODO()
+	mgr, znodes := mockZnodeManager()
+
+	n, err := mgr.Exec(ctx, "create /config {\"quota\": 100}")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	assert.Equal(t, `{"quota": 100}`, znodes.data["/config"])
+
+	_, err = mgr.Exec(ctx, "create /config")
+	assert.ErrorIs(t, err, zk.ErrNodeExists)
+	_, err = mgr.Exec(ctx, "create -e -s /lock")
+	require.NoError(t, err)
+	assert.Contains(t, znodes.data, "/lock0000000001")
+
+	_, err = mgr.Exec(ctx, "set /config quota 200")
+	require.NoError(t, err)
+	assert.Equal(t, "quota 200", znodes.data["/config"])
+	_, err = mgr.Exec(ctx, "set /config")
+	assert.ErrorContains(t, err, "the usage is")
+
+	_, err = mgr.Exec(ctx, "delete /config")
+	require.NoError(t, err)
+	assert.NotContains(t, znodes.data, "/config")
+	_, err = mgr.Exec(ctx, "delete /config")
+	assert.ErrorIs(t, err, zk.ErrNoNode)
+}
+
+func TestConformance(t *testing.T) {
+	words := &mockFourLetterWords{responses: map[string]string{"srvr": srvrLeader}}
+	mgr, _ := mockZnodeManager()
+	mgr.config.Addr = words.serve(t)
+
+	conformance.Run(t, conformance.Suite{
+		Manager:  mgr,
+		ExecSQL:  "set /brokers {}",
+		QuerySQL: "ls /brokers",
+	})
+}
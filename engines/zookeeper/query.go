@@ -0,0 +1,279 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package zookeeper
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-zookeeper/zk"
+	"github.com/pkg/errors"
+)
+
+// the znode reads of Query.
+const (
+	cmdGet  = "get"
+	cmdLs   = "ls"
+	cmdStat = "stat"
+)
+
+// the znode changes of Exec.
+const (
+	cmdCreate = "create"
+	cmdSet    = "set"
+	cmdDelete = "delete"
+)
+
+var (
+	queryUsages = []string{
+		cmdGet + " <path>",
+		cmdLs + " <path>",
+		cmdStat + " <path>",
+	}
+	execUsages = []string{
+		cmdCreate + " [-e] [-s] <path> [<data>]",
+		cmdSet + " <path> <data>",
+		cmdDelete + " <path>",
+	}
+)
+
+// znodeConn is the session to read and write the znodes, it's *zk.Conn.
+type znodeConn interface {
+	AddAuth(scheme string, auth []byte) error
+	Get(path string) ([]byte, *zk.Stat, error)
+	Children(path string) ([]string, *zk.Stat, error)
+	Exists(path string) (bool, *zk.Stat, error)
+	Create(path string, data []byte, flags int32, acl []zk.ACL) (string, error)
+	Set(path string, data []byte, version int32) (*zk.Stat, error)
+	Delete(path string, version int32) error
+	Close()
+}
+
+type znode struct {
+	Path     string     `json:"path"`
+	Data     *string    `json:"data,omitempty"`
+	Children []string   `json:"children,omitempty"`
+	Stat     *znodeStat `json:"stat"`
+}
+
+// znodeStat is the stat of a znode, the times are in milliseconds since the epoch.
+type znodeStat struct {
+	Czxid          int64 `json:"czxid"`
+	Mzxid          int64 `json:"mzxid"`
+	Pzxid          int64 `json:"pzxid"`
+	Ctime          int64 `json:"ctime"`
+	Mtime          int64 `json:"mtime"`
+	Version        int32 `json:"version"`
+	Cversion       int32 `json:"cversion"`
+	Aversion       int32 `json:"aversion"`
+	EphemeralOwner int64 `json:"ephemeralOwner"`
+	DataLength     int32 `json:"dataLength"`
+	NumChildren    int32 `json:"numChildren"`
+}
+
+func newZnodeStat(stat *zk.Stat) *znodeStat {
+	return &znodeStat{
+		Czxid:          stat.Czxid,
+		Mzxid:          stat.Mzxid,
+		Pzxid:          stat.Pzxid,
+		Ctime:          stat.Ctime,
+		Mtime:          stat.Mtime,
+		Version:        stat.Version,
+		Cversion:       stat.Cversion,
+		Aversion:       stat.Aversion,
+		EphemeralOwner: stat.EphemeralOwner,
+		DataLength:     stat.DataLength,
+		NumChildren:    stat.NumChildren,
+	}
+}
+
+// Query reads a znode, e.g. "get /brokers/ids/0", and returns it in JSON.
+func (mgr *Manager) Query(ctx context.Context, cmd string) ([]byte, error) {
+	name, path := nextArg(cmd)
+	path, rest := nextArg(path)
+	if name == "" {
+		return nil, errors.Errorf("the command is empty, the commands are: %s", strings.Join(queryUsages, "; "))
+	}
+	if !slices.Contains([]string{cmdGet, cmdLs, cmdStat}, name) {
+		return nil, errors.Errorf("unknown command %s, the commands are: %s", name, strings.Join(queryUsages, "; "))
+	}
+	if path == "" || rest != "" {
+		return nil, errors.Errorf("invalid command %s, the usage is: %s <path>", cmd, name)
+	}
+
+	node := &znode{Path: path}
+	err := mgr.withConn(ctx, func(conn znodeConn) error {
+		var stat *zk.Stat
+		var err error
+		switch name {
+		case cmdGet:
+			var data []byte
+			data, stat, err = conn.Get(path)
+			value := string(data)
+			node.Data = &value
+		case cmdLs:
+			node.Children, stat, err = conn.Children(path)
+			slices.Sort(node.Children)
+		case cmdStat:
+			var exists bool
+			exists, stat, err = conn.Exists(path)
+			if err == nil && !exists {
+				err = zk.ErrNoNode
+			}
+		}
+		if err != nil {
+			return errors.Wrapf(err, "%s %s failed", name, path)
+		}
+		node.Stat = newZnodeStat(stat)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(node)
+}
+
+// Exec creates, sets or deletes a znode, e.g. "set /config/quota 100", the data
+// is the rest of the command after the path. A set or delete applies to any
+// version of the znode, so one znode is affected if it succeeds.
+func (mgr *Manager) Exec(ctx context.Context, cmd string) (int64, error) {
+	name, args := nextArg(cmd)
+	var op func(conn znodeConn) error
+	switch name {
+	case "":
+		return 0, errors.Errorf("the command is empty, the commands are: %s", strings.Join(execUsages, "; "))
+	case cmdCreate:
+		var flags int32
+		var path string
+		for path, args = nextArg(args); ; path, args = nextArg(args) {
+			if path == "-e" {
+				flags |= zk.FlagEphemeral
+			} else if path == "-s" {
+				flags |= zk.FlagSequence
+			} else {
+				break
+			}
+		}
+		if path == "" {
+			return 0, errors.Errorf("invalid command %s, the usage is: %s", cmd, execUsages[0])
+		}
+		op = func(conn znodeConn) error {
+			_, err := conn.Create(path, []byte(args), flags, zk.WorldACL(zk.PermAll))
+			return errors.Wrapf(err, "create %s failed", path)
+		}
+	case cmdSet:
+		path, data := nextArg(args)
+		if path == "" || data == "" {
+			return 0, errors.Errorf("invalid command %s, the usage is: %s", cmd, execUsages[1])
+		}
+		op = func(conn znodeConn) error {
+			_, err := conn.Set(path, []byte(data), -1)
+			return errors.Wrapf(err, "set %s failed", path)
+		}
+	case cmdDelete:
+		path, rest := nextArg(args)
+		if path == "" || rest != "" {
+			return 0, errors.Errorf("invalid command %s, the usage is: %s", cmd, execUsages[2])
+		}
+		op = func(conn znodeConn) error {
+			return errors.Wrapf(conn.Delete(path, -1), "delete %s failed", path)
+		}
+	default:
+		return 0, errors.Errorf("unknown command %s, the commands are: %s", name, strings.Join(execUsages, "; "))
+	}
+
+	if err := mgr.withConn(ctx, op); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// nextArg splits the next space-separated arg from the rest of the command.
+func nextArg(cmd string) (string, string) {
+	arg, rest, _ := strings.Cut(strings.TrimSpace(cmd), " ")
+	return arg, strings.TrimSpace(rest)
+}
+
+// withConn runs the operation in a new session, which is authenticated by the
+// digest of the current credentials, if any. The session is closed on return,
+// which fails the pending request if the context is done first.
+func (mgr *Manager) withConn(ctx context.Context, op func(conn znodeConn) error) error {
+	// the op isn't sent once the context is done, even if the session is ready
+	if err := ctx.Err(); err != nil {
+		return errors.Wrapf(err, "request %s failed", mgr.config.Addr)
+	}
+	conn, err := mgr.dial()
+	if err != nil {
+		return errors.Wrapf(err, "connect to %s failed", mgr.config.Addr)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, mgr.config.Timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		if username, password := mgr.config.GetCredentials(); username != "" {
+			if err := conn.AddAuth("digest", []byte(username+":"+password)); err != nil {
+				done <- errors.Wrapf(err, "authenticate %s failed", username)
+				return
+			}
+		}
+		done <- op(conn)
+	}()
+
+	select {
+	case err = <-done:
+		return err
+	case <-ctx.Done():
+		return errors.Wrapf(ctx.Err(), "request %s failed", mgr.config.Addr)
+	}
+}
+
+func (mgr *Manager) dialZooKeeper() (znodeConn, error) {
+	dialer := func(network, address string, timeout time.Duration) (net.Conn, error) {
+		netDialer := &net.Dialer{Timeout: timeout}
+		if mgr.config.TLSConfig != nil {
+			return tls.DialWithDialer(netDialer, network, address, mgr.config.TLSConfig)
+		}
+		return netDialer.Dial(network, address)
+	}
+	conn, _, err := zk.Connect([]string{mgr.config.Addr}, mgr.config.Timeout,
+		zk.WithDialer(dialer), zk.WithLogger(zkLogger{mgr.Logger}), zk.WithLogInfo(false))
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// zkLogger logs the session errors of the zk client.
+type zkLogger struct {
+	logr.Logger
+}
+
+func (l zkLogger) Printf(format string, args ...any) {
+	l.Info(fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,179 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package zookeeper
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+const (
+	// notServing is in the response of the four-letter words before the server
+	// joins the quorum, ClickHouse Keeper responds alike.
+	notServing = "is not currently serving requests"
+	// notWhitelisted is in the response of the four-letter words which are not in
+	// 4lw.commands.whitelist, only srvr is whitelisted by default.
+	notWhitelisted = "is not in the whitelist"
+
+	srvrCommandPath = "/commands/srvr"
+)
+
+// serverStates maps the server states other than leader and follower, an
+// observer doesn't vote, and a server is read-only once it's partitioned from
+// the quorum with readonlymode.enabled.
+var serverStates = map[string]models.RoleInfo{
+	"observer":   {Role: models.LEARNER, Health: models.HealthHealthy},
+	"standalone": {Role: models.LEADER, Writable: true, Health: models.HealthHealthy},
+	"read-only":  {Role: models.FOLLOWER, Health: models.HealthUnhealthy},
+}
+
+// NewRoleInfo normalizes the server state of a ZooKeeper or a ClickHouse Keeper
+// server.
+func NewRoleInfo(state string) *models.RoleInfo {
+	info, ok := serverStates[state]
+	if !ok {
+		return models.NewRoleInfo(state)
+	}
+	info.NativeRole = state
+	return &info
+}
+
+// adminResponse is the response of the srvr command of the AdminServer.
+type adminResponse struct {
+	ServerStats *struct {
+		ServerState string `json:"server_state"`
+	} `json:"server_stats"`
+	Error *string `json:"error"`
+}
+
+// getServerState returns the server state by srvr, from the AdminServer if it's
+// configured, or the four-letter word otherwise.
+func (mgr *Manager) getServerState(ctx context.Context) (string, error) {
+	if mgr.config.AdminURL != "" {
+		return mgr.getServerStateByAdmin(ctx)
+	}
+
+	resp, err := mgr.fourLetterWord(ctx, "srvr")
+	if err != nil {
+		return "", err
+	}
+	mode, ok := parseSrvr(resp)["Mode"]
+	if !ok {
+		return "", errors.Errorf("mode is not found in the response of srvr: %s", resp)
+	}
+	return mode, nil
+}
+
+func (mgr *Manager) getServerStateByAdmin(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mgr.config.AdminURL+srvrCommandPath, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := mgr.client.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "GET %s failed", srvrCommandPath)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrapf(err, "read the response of %s failed", srvrCommandPath)
+	}
+
+	// the AdminServer responds 503 with the error before the server is serving
+	adminResp := &adminResponse{}
+	if err = json.Unmarshal(data, adminResp); err != nil {
+		return "", errors.Errorf("GET %s failed, status %s: %s", srvrCommandPath, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if adminResp.Error != nil {
+		return "", errors.Errorf("GET %s failed: %s", srvrCommandPath, *adminResp.Error)
+	}
+	if adminResp.ServerStats == nil || adminResp.ServerStats.ServerState == "" {
+		return "", errors.Errorf("server state is not found in the response of %s: %s", srvrCommandPath, data)
+	}
+	return adminResp.ServerStats.ServerState, nil
+}
+
+func (mgr *Manager) fourLetterWord(ctx context.Context, word string) (string, error) {
+	return FourLetterWord(ctx, mgr.config.Addr, mgr.config.TLSConfig, mgr.config.Timeout, word)
+}
+
+// FourLetterWord sends the four-letter word to the client port of a ZooKeeper or
+// a ClickHouse Keeper server, the server responds and closes the connection. The
+// response telling the server is not serving or the word is not whitelisted is
+// returned as the error.
+func FourLetterWord(ctx context.Context, addr string, tlsConfig *tls.Config, timeout time.Duration, word string) (string, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", errors.Wrapf(err, "connect to %s failed", addr)
+	}
+	if tlsConfig != nil {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err = conn.SetDeadline(deadline); err != nil {
+		return "", err
+	}
+	if _, err = conn.Write([]byte(word)); err != nil {
+		return "", errors.Wrapf(err, "send %s failed", word)
+	}
+	data, err := io.ReadAll(conn)
+	if err != nil {
+		return "", errors.Wrapf(err, "read the response of %s failed", word)
+	}
+
+	resp := strings.TrimSpace(string(data))
+	if strings.Contains(resp, notServing) || strings.Contains(resp, notWhitelisted) {
+		return "", errors.New(resp)
+	}
+	return resp, nil
+}
+
+// parseSrvr parses the "key: value" lines of srvr.
+func parseSrvr(resp string) map[string]string {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(resp))
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), ":"); ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return values
+}
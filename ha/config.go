@@ -0,0 +1,90 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ha
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/constant"
+)
+
+type Config struct {
+	Enabled   bool
+	Endpoints []string
+	Address   string
+	// HeadlessService resolves the pods of the component, the advertised address
+	// is the FQDN of the pod by it unless Address is set.
+	HeadlessService string
+	LeaseTTL        time.Duration
+	Interval        time.Duration
+	MaxLag          time.Duration
+}
+
+var config Config
+var logger = ctrl.Log.WithName("HA")
+
+func InitFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&config.Enabled, "ha", false, "Run the HA controller, which elects the primary by the etcd lease and fails over the database.")
+	fs.StringSliceVar(&config.Endpoints, "ha-etcd-endpoints", []string{"127.0.0.1:2379"}, "The etcd endpoints to elect the leader.")
+	fs.StringVar(&config.Address, "ha-address", "", "The address of the database advertised to the other members, the FQDN of the pod by the headless service by default.")
+	fs.StringVar(&config.HeadlessService, "ha-headless-service", "", "The headless service of the pods to build the advertised address, <cluster component>-headless by default.")
+	fs.DurationVar(&config.LeaseTTL, "ha-lease-ttl", 30*time.Second, "The TTL of the leader lease, the leader is failed over if it's not renewed in time.")
+	fs.DurationVar(&config.Interval, "ha-interval", 5*time.Second, "The interval to check the database and renew the leader lease.")
+	fs.DurationVar(&config.MaxLag, "ha-max-lag", 10*time.Second, "The max replication lag of a replica to take the leadership, 0 to promote a replica regardless of its lag.")
+}
+
+// Enabled returns whether the HA controller is enabled by the flags.
+func Enabled() bool {
+	return config.Enabled
+}
+
+// reconcileTimeout bounds a reconcile well below the lease TTL, so that a primary
+// which can't reach the DCS fences itself before its lease expires.
+func reconcileTimeout(interval, leaseTTL time.Duration) time.Duration {
+	return min(interval, leaseTTL/3)
+}
+
+// keyPrefix isolates the keys of the cluster component in the namespace.
+func keyPrefix() string {
+	return "/dbctl/" + constant.GetNamespace() + "/" + constant.GetClusterCompName()
+}
+
+// advertisedAddress returns the address the other members connect to, which is
+// the FQDN of the pod by the headless service unless it's set by the flag, as
+// the bare pod name isn't resolved by the other pods.
+func advertisedAddress() (string, error) {
+	if config.Address != "" {
+		return config.Address, nil
+	}
+	service := config.HeadlessService
+	if service == "" && constant.GetClusterCompName() != "" {
+		service = constant.GetClusterCompName() + "-headless"
+	}
+	namespace := constant.GetNamespace()
+	if service == "" || namespace == "" {
+		return "", errors.New("the advertised address is unknown, set --ha-address, or the namespace and the cluster component name by the env")
+	}
+	return fmt.Sprintf("%s.%s.%s.svc", constant.GetPodName(), service, namespace), nil
+}
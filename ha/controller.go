@@ -0,0 +1,254 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ha
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// fenceTimeout bounds fencing the local primary on a DCS failure, which has its
+// own deadline since the one of the reconcile has likely expired on the DCS call.
+const fenceTimeout = 5 * time.Second
+
+// supportedEngines are failed over by the controller, the other engines either
+// have their own consensus, e.g. wesql and apecloud-postgresql, or are not supported yet.
+var supportedEngines = map[string]bool{
+	string(models.MySQL):             true,
+	string(models.PostgreSQL):        true,
+	string(models.VanillaPostgreSQL): true,
+}
+
+// Controller keeps the database in the role decided by the leader election: the
+// leader promotes the local member and fences the other primaries, and the other
+// members demote themselves to replicate from the leader.
+type Controller struct {
+	logger   logr.Logger
+	dcs      DCS
+	mgr      engines.DBManager
+	switcher engines.Switcher
+	address  string
+	maxLag   time.Duration
+}
+
+func NewController(mgr engines.DBManager, dcs DCS, address string) (*Controller, error) {
+	switcher, ok := mgr.(engines.Switcher)
+	if !ok {
+		return nil, errors.New("the engine can't be switched over")
+	}
+	return &Controller{
+		logger:   logger.WithValues("member", address),
+		dcs:      dcs,
+		mgr:      mgr,
+		switcher: switcher,
+		address:  address,
+		maxLag:   config.MaxLag,
+	}, nil
+}
+
+// Start runs the controller with the flags until the context is done.
+func Start(ctx context.Context, engineType string, mgr engines.DBManager) error {
+	if !supportedEngines[strings.ToLower(engineType)] {
+		return errors.Errorf("HA is not supported for engine %s", engineType)
+	}
+	address, err := advertisedAddress()
+	if err != nil {
+		return err
+	}
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   config.Endpoints,
+		DialTimeout: config.Interval,
+	})
+	if err != nil {
+		return errors.Wrap(err, "connect to etcd failed")
+	}
+	controller, err := NewController(mgr, NewEtcdDCS(client, keyPrefix(), config.LeaseTTL), address)
+	if err != nil {
+		_ = client.Close()
+		return err
+	}
+	go func() {
+		controller.Run(ctx, config.Interval, reconcileTimeout(config.Interval, config.LeaseTTL))
+		_ = client.Close()
+	}()
+	return nil
+}
+
+// Run reconciles at the interval, each reconcile is bounded by the timeout. The
+// lease is revoked when the context is done, so that another member takes over at once.
+func (c *Controller) Run(ctx context.Context, interval, timeout time.Duration) {
+	c.logger.Info("HA controller started")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		reconcileCtx, cancel := context.WithTimeout(ctx, timeout)
+		if err := c.Reconcile(reconcileCtx); err != nil {
+			c.logger.Info("reconcile failed", "error", err.Error())
+		}
+		cancel()
+		select {
+		case <-ctx.Done():
+			if err := c.dcs.Close(); err != nil {
+				c.logger.Info("close DCS failed", "error", err.Error())
+			}
+			c.logger.Info("HA controller stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile checks the local database and acts on the leader election once.
+func (c *Controller) Reconcile(ctx context.Context) error {
+	view, err := c.localView(ctx)
+	if err != nil {
+		// an unhealthy member gives up the leadership, another member is promoted
+		if releaseErr := c.dcs.ReleaseLeader(ctx, c.address); releaseErr != nil {
+			c.logger.Info("release leader failed", "error", releaseErr.Error())
+		}
+		return err
+	}
+
+	if err = c.dcs.Register(ctx, c.address); err != nil {
+		return c.fenceOnDCSFailure(ctx, view, err)
+	}
+	var leader string
+	if c.isEligible(ctx, view) {
+		leader, err = c.dcs.AcquireLeader(ctx, c.address)
+	} else {
+		leader, err = c.dcs.GetLeader(ctx)
+	}
+	if err != nil {
+		return c.fenceOnDCSFailure(ctx, view, err)
+	}
+
+	switch leader {
+	case "":
+		return nil
+	case c.address:
+		return c.lead(ctx, view)
+	default:
+		return c.follow(ctx, view, leader)
+	}
+}
+
+func (c *Controller) localView(ctx context.Context) (*models.MemberView, error) {
+	if !c.mgr.IsDBStartupReady() {
+		return nil, errors.New("database is not ready")
+	}
+	view, err := c.mgr.GetMemberView(ctx, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "database is unhealthy")
+	}
+	return view, nil
+}
+
+// isEligible tells whether the local member may take the leadership. A replica
+// lagging behind more than the max lag doesn't acquire the lease, so that a more
+// up-to-date member is promoted instead, and the changes it hasn't applied are not
+// lost. The lag is unknown once the upstream is gone and the received changes are
+// applied, and such a replica is eligible.
+func (c *Controller) isEligible(ctx context.Context, view *models.MemberView) bool {
+	if view.Role == models.PRIMARY || c.maxLag <= 0 {
+		return true
+	}
+	status, err := c.mgr.GetReplicationStatus(ctx)
+	if err != nil {
+		c.logger.Info("get replication status failed", "error", err.Error())
+		return false
+	}
+	if status.LagSeconds == nil || time.Duration(*status.LagSeconds)*time.Second <= c.maxLag {
+		return true
+	}
+	c.logger.Info("replica is lagging, don't take the leadership", "lagSeconds", *status.LagSeconds)
+	return false
+}
+
+// fenceOnDCSFailure fences the local primary, the leadership can't be confirmed,
+// and another member may have been promoted.
+func (c *Controller) fenceOnDCSFailure(ctx context.Context, view *models.MemberView, err error) error {
+	if view.Role == models.PRIMARY && view.Writable {
+		c.logger.Info("DCS is unavailable, fence the local primary")
+		fenceCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fenceTimeout)
+		defer cancel()
+		if fenceErr := c.mgr.FenceMember(fenceCtx, ""); fenceErr != nil {
+			c.logger.Info("fence local primary failed", "error", fenceErr.Error())
+		}
+	}
+	return err
+}
+
+func (c *Controller) lead(ctx context.Context, view *models.MemberView) error {
+	if view.Role != models.PRIMARY || !view.Writable {
+		c.logger.Info("promote the local member")
+		if err := c.switcher.Promote(ctx); err != nil {
+			return errors.Wrap(err, "promote failed")
+		}
+	}
+	c.fenceStalePrimaries(ctx)
+	return nil
+}
+
+// fenceStalePrimaries fences the other writable primaries, they are demoted by
+// their own controllers after they see the leader.
+func (c *Controller) fenceStalePrimaries(ctx context.Context) {
+	members, err := c.dcs.GetMembers(ctx)
+	if err != nil {
+		c.logger.Info("get members failed", "error", err.Error())
+		return
+	}
+	for _, member := range members {
+		if member == c.address {
+			continue
+		}
+		view, err := c.mgr.GetMemberView(ctx, member)
+		if err != nil {
+			c.logger.Info("get member view failed", "address", member, "error", err.Error())
+			continue
+		}
+		if view.Role != models.PRIMARY || !view.Writable {
+			continue
+		}
+		if err = c.mgr.FenceMember(ctx, member); err != nil {
+			c.logger.Info("fence stale primary failed", "address", member, "error", err.Error())
+			continue
+		}
+		c.logger.Info("stale primary fenced", "address", member)
+	}
+}
+
+func (c *Controller) follow(ctx context.Context, view *models.MemberView, leader string) error {
+	if view.Role == models.SECONDARY && models.IsSameMember(view.Leader, leader) {
+		return nil
+	}
+	c.logger.Info("demote the local member", "leader", leader)
+	if err := c.switcher.Demote(ctx, leader); err != nil {
+		return errors.Wrap(err, "demote failed")
+	}
+	return nil
+}
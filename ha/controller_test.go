@@ -0,0 +1,301 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ha
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/constant"
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+)
+
+// fakeMember is the database state of a member, shared by the fake managers to
+// view and fence the other members.
+type fakeMember struct {
+	healthy  bool
+	role     string
+	writable bool
+	leader   string
+	// lagSeconds is the replication lag of a replica, nil if it's unknown
+	lagSeconds *int64
+}
+
+type fakeManager struct {
+	engines.DBManagerBase
+	address string
+	cluster map[string]*fakeMember
+}
+
+var _ engines.Switcher = &fakeManager{}
+
+func (mgr *fakeManager) member(address string) (*fakeMember, error) {
+	if address == "" {
+		address = mgr.address
+	}
+	member := mgr.cluster[address]
+	if !member.healthy {
+		return nil, errors.Errorf("member %s is down", address)
+	}
+	return member, nil
+}
+
+func (mgr *fakeManager) IsDBStartupReady() bool {
+	return mgr.cluster[mgr.address].healthy
+}
+
+func (mgr *fakeManager) GetMemberView(_ context.Context, address string) (*models.MemberView, error) {
+	member, err := mgr.member(address)
+	if err != nil {
+		return nil, err
+	}
+	return &models.MemberView{Address: address, Role: member.role, Writable: member.writable, Leader: member.leader}, nil
+}
+
+func (mgr *fakeManager) GetReplicationStatus(context.Context) (*models.ReplicationStatus, error) {
+	member, err := mgr.member("")
+	if err != nil {
+		return nil, err
+	}
+	return &models.ReplicationStatus{IsReplica: member.role != models.PRIMARY, LagSeconds: member.lagSeconds}, nil
+}
+
+func (mgr *fakeManager) FenceMember(ctx context.Context, address string) error {
+	// the database can't be fenced on a context which is done, as a real connection
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	member, err := mgr.member(address)
+	if err != nil {
+		return err
+	}
+	member.writable = false
+	return nil
+}
+
+func (mgr *fakeManager) Promote(context.Context) error {
+	member, err := mgr.member("")
+	if err != nil {
+		return err
+	}
+	member.role, member.writable, member.leader = models.PRIMARY, true, ""
+	return nil
+}
+
+func (mgr *fakeManager) Demote(_ context.Context, leader string) error {
+	member, err := mgr.member("")
+	if err != nil {
+		return err
+	}
+	member.role, member.writable, member.leader = models.SECONDARY, false, leader
+	return nil
+}
+
+func TestController(t *testing.T) {
+	ctx := context.TODO()
+	prefix := "/dbctl/test/" + t.Name()
+	// both members start as writable primaries, e.g. from the same backup
+	cluster := map[string]*fakeMember{
+		"pod-0": {healthy: true, role: models.PRIMARY, writable: true},
+		"pod-1": {healthy: true, role: models.PRIMARY, writable: true},
+	}
+	newController := func(address string) *Controller {
+		mgr := &fakeManager{
+			DBManagerBase: engines.DBManagerBase{Logger: ctrl.Log},
+			address:       address,
+			cluster:       cluster,
+		}
+		controller, err := NewController(mgr, NewEtcdDCS(etcdClient, prefix, 5*time.Second), address)
+		assert.Nil(t, err)
+		return controller
+	}
+	c0, c1 := newController("pod-0"), newController("pod-1")
+	defer func() {
+		_ = c0.dcs.Close()
+		_ = c1.dcs.Close()
+	}()
+
+	t.Run("elect leader", func(t *testing.T) {
+		assert.Nil(t, c0.Reconcile(ctx))
+		assert.Nil(t, c1.Reconcile(ctx))
+		// pod-1 registers after pod-0 leads
+		assert.Nil(t, c0.Reconcile(ctx))
+
+		assert.Equal(t, &fakeMember{healthy: true, role: models.PRIMARY, writable: true}, cluster["pod-0"])
+		assert.Equal(t, &fakeMember{healthy: true, role: models.SECONDARY, leader: "pod-0"}, cluster["pod-1"])
+	})
+
+	t.Run("fail over", func(t *testing.T) {
+		cluster["pod-0"].healthy = false
+		assert.NotNil(t, c0.Reconcile(ctx))
+		assert.Nil(t, c1.Reconcile(ctx))
+
+		assert.Equal(t, &fakeMember{healthy: true, role: models.PRIMARY, writable: true}, cluster["pod-1"])
+	})
+
+	t.Run("fence old primary", func(t *testing.T) {
+		cluster["pod-0"].healthy = true
+		assert.Nil(t, c1.Reconcile(ctx))
+		assert.False(t, cluster["pod-0"].writable)
+
+		assert.Nil(t, c0.Reconcile(ctx))
+		assert.Equal(t, &fakeMember{healthy: true, role: models.SECONDARY, leader: "pod-1"}, cluster["pod-0"])
+	})
+
+	t.Run("fence on DCS failure", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		assert.NotNil(t, c1.Reconcile(canceled))
+		assert.False(t, cluster["pod-1"].writable)
+
+		// the leader is promoted again
+		assert.Nil(t, c1.Reconcile(ctx))
+		assert.True(t, cluster["pod-1"].writable)
+	})
+}
+
+// blockingDCS blocks the calls until the context is done, as etcd does during a
+// network partition.
+type blockingDCS struct{}
+
+func (blockingDCS) AcquireLeader(ctx context.Context, _ string) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func (blockingDCS) ReleaseLeader(ctx context.Context, _ string) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (blockingDCS) GetLeader(ctx context.Context) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func (blockingDCS) Register(ctx context.Context, _ string) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (blockingDCS) GetMembers(ctx context.Context) ([]string, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (blockingDCS) Close() error {
+	return nil
+}
+
+func TestRunFencesOnBlockingDCS(t *testing.T) {
+	cluster := map[string]*fakeMember{
+		"pod-0": {healthy: true, role: models.PRIMARY, writable: true},
+	}
+	mgr := &fakeManager{
+		DBManagerBase: engines.DBManagerBase{Logger: ctrl.Log},
+		address:       "pod-0",
+		cluster:       cluster,
+	}
+	controller, err := NewController(mgr, blockingDCS{}, "pod-0")
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	// the reconcile times out on the DCS long before the context is done
+	controller.Run(ctx, time.Minute, 100*time.Millisecond)
+	assert.False(t, cluster["pod-0"].writable)
+}
+
+func TestControllerMaxLag(t *testing.T) {
+	ctx := context.TODO()
+	prefix := "/dbctl/test/" + t.Name()
+	lagSeconds := int64(60)
+	cluster := map[string]*fakeMember{
+		"pod-0": {healthy: true, role: models.SECONDARY, lagSeconds: &lagSeconds},
+	}
+	mgr := &fakeManager{
+		DBManagerBase: engines.DBManagerBase{Logger: ctrl.Log},
+		address:       "pod-0",
+		cluster:       cluster,
+	}
+	controller, err := NewController(mgr, NewEtcdDCS(etcdClient, prefix, 5*time.Second), "pod-0")
+	assert.Nil(t, err)
+	controller.maxLag = 10 * time.Second
+	defer func() {
+		_ = controller.dcs.Close()
+	}()
+
+	// the lagging replica doesn't take the leadership
+	assert.Nil(t, controller.Reconcile(ctx))
+	assert.Equal(t, models.SECONDARY, cluster["pod-0"].role)
+	leader, err := controller.dcs.GetLeader(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "", leader)
+
+	// it's promoted once it catches up
+	lagSeconds = 0
+	assert.Nil(t, controller.Reconcile(ctx))
+	assert.Equal(t, models.PRIMARY, cluster["pod-0"].role)
+	assert.True(t, cluster["pod-0"].writable)
+}
+
+func TestReconcileTimeout(t *testing.T) {
+	assert.Equal(t, 5*time.Second, reconcileTimeout(5*time.Second, 30*time.Second))
+	assert.Equal(t, 10*time.Second, reconcileTimeout(time.Minute, 30*time.Second))
+}
+
+func TestAdvertisedAddress(t *testing.T) {
+	defer func(saved Config) {
+		config = saved
+		viper.Reset()
+	}(config)
+
+	_, err := advertisedAddress()
+	assert.ErrorContains(t, err, "set --ha-address")
+
+	viper.Set(constant.KBEnvPodName, "mysql-0")
+	viper.Set(constant.KBEnvNamespace, "default")
+	viper.Set(constant.KBEnvClusterCompName, "mycluster-mysql")
+	address, err := advertisedAddress()
+	assert.NoError(t, err)
+	assert.Equal(t, "mysql-0.mycluster-mysql-headless.default.svc", address)
+
+	config.HeadlessService = "mysql-peers"
+	address, err = advertisedAddress()
+	assert.NoError(t, err)
+	assert.Equal(t, "mysql-0.mysql-peers.default.svc", address)
+
+	config.Address = "10.0.0.1:3306"
+	address, err = advertisedAddress()
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1:3306", address)
+}
+
+func TestNewController(t *testing.T) {
+	_, err := NewController(&engines.DBManagerBase{}, nil, "pod-0")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,198 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ha
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+// DCS is the distributed configuration store to elect the leader, and to discover
+// the members to be fenced.
+type DCS interface {
+	// AcquireLeader takes or renews the leader lease for the member, and returns
+	// the current leader, which is another member if the lease is held by it.
+	AcquireLeader(ctx context.Context, member string) (string, error)
+	// ReleaseLeader gives up the lease if it's held by the member.
+	ReleaseLeader(ctx context.Context, member string) error
+	// GetLeader returns the current leader without taking the lease, it's empty if
+	// the lease is not held by any member.
+	GetLeader(ctx context.Context) (string, error)
+	// Register keeps the member in the member list until its lease expires.
+	Register(ctx context.Context, member string) error
+	GetMembers(ctx context.Context) ([]string, error)
+	// Close revokes the lease, the leadership and registration are dropped at once.
+	Close() error
+}
+
+type etcdDCS struct {
+	client  *clientv3.Client
+	prefix  string
+	ttl     int
+	session *concurrency.Session
+}
+
+var _ DCS = &etcdDCS{}
+
+// NewEtcdDCS stores the leader and the members under the prefix, with a lease of
+// the TTL which is kept alive in the background.
+func NewEtcdDCS(client *clientv3.Client, prefix string, ttl time.Duration) DCS {
+	return &etcdDCS{
+		client: client,
+		prefix: prefix,
+		ttl:    int(ttl.Seconds()),
+	}
+}
+
+// lease returns the lease of the session, a new session is created if the lease
+// is lost, e.g. it's not kept alive during a network partition. The lease is
+// granted with the context, so that it doesn't block the reconcile past its deadline.
+func (dcs *etcdDCS) lease(ctx context.Context) (clientv3.LeaseID, error) {
+	if dcs.session != nil {
+		select {
+		case <-dcs.session.Done():
+			dcs.session = nil
+		default:
+			return dcs.session.Lease(), nil
+		}
+	}
+
+	grant, err := dcs.client.Grant(ctx, int64(dcs.ttl))
+	if err != nil {
+		return clientv3.NoLease, errors.Wrap(err, "grant etcd lease failed")
+	}
+	session, err := concurrency.NewSession(dcs.client, concurrency.WithTTL(dcs.ttl), concurrency.WithLease(grant.ID))
+	if err != nil {
+		return clientv3.NoLease, errors.Wrap(err, "create etcd session failed")
+	}
+	dcs.session = session
+	return session.Lease(), nil
+}
+
+func (dcs *etcdDCS) leaderKey() string {
+	return dcs.prefix + "/leader"
+}
+
+func (dcs *etcdDCS) memberKey(member string) string {
+	return dcs.prefix + "/members/" + member
+}
+
+func (dcs *etcdDCS) AcquireLeader(ctx context.Context, member string) (string, error) {
+	lease, err := dcs.lease(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	key := dcs.leaderKey()
+	resp, err := dcs.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, member, clientv3.WithLease(lease))).
+		Else(clientv3.OpGet(key)).
+		Commit()
+	if err != nil {
+		return "", errors.Wrap(err, "acquire leader failed")
+	}
+	if resp.Succeeded {
+		return member, nil
+	}
+
+	kvs := resp.Responses[0].GetResponseRange().Kvs
+	if len(kvs) == 0 {
+		// the lease expired just now, it's acquired in the next round
+		return "", nil
+	}
+	leader := kvs[0]
+	if string(leader.Value) != member || leader.Lease == int64(lease) {
+		return string(leader.Value), nil
+	}
+
+	// the key is held by the lease of a previous session of the member, e.g. before
+	// dbctl restarts, and it's taken over by the current lease.
+	resp, err = dcs.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", leader.ModRevision)).
+		Then(clientv3.OpPut(key, member, clientv3.WithLease(lease))).
+		Commit()
+	if err != nil {
+		return "", errors.Wrap(err, "take over leader failed")
+	}
+	if !resp.Succeeded {
+		return "", nil
+	}
+	return member, nil
+}
+
+func (dcs *etcdDCS) ReleaseLeader(ctx context.Context, member string) error {
+	key := dcs.leaderKey()
+	_, err := dcs.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.Value(key), "=", member)).
+		Then(clientv3.OpDelete(key)).
+		Commit()
+	if err != nil {
+		return errors.Wrap(err, "release leader failed")
+	}
+	return nil
+}
+
+func (dcs *etcdDCS) GetLeader(ctx context.Context) (string, error) {
+	resp, err := dcs.client.Get(ctx, dcs.leaderKey())
+	if err != nil {
+		return "", errors.Wrap(err, "get leader failed")
+	}
+	if len(resp.Kvs) == 0 {
+		return "", nil
+	}
+	return string(resp.Kvs[0].Value), nil
+}
+
+func (dcs *etcdDCS) Register(ctx context.Context, member string) error {
+	lease, err := dcs.lease(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err = dcs.client.Put(ctx, dcs.memberKey(member), member, clientv3.WithLease(lease)); err != nil {
+		return errors.Wrap(err, "register member failed")
+	}
+	return nil
+}
+
+func (dcs *etcdDCS) GetMembers(ctx context.Context) ([]string, error) {
+	resp, err := dcs.client.Get(ctx, dcs.memberKey(""), clientv3.WithPrefix())
+	if err != nil {
+		return nil, errors.Wrap(err, "get members failed")
+	}
+	members := make([]string, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		members = append(members, string(kv.Value))
+	}
+	return members, nil
+}
+
+func (dcs *etcdDCS) Close() error {
+	if dcs.session == nil {
+		return nil
+	}
+	err := dcs.session.Close()
+	dcs.session = nil
+	return err
+}
@@ -0,0 +1,106 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ha
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEtcdDCS(t *testing.T) {
+	ctx := context.TODO()
+	prefix := "/dbctl/test/" + t.Name()
+	dcs0 := NewEtcdDCS(etcdClient, prefix, 5*time.Second)
+	dcs1 := NewEtcdDCS(etcdClient, prefix, 5*time.Second)
+	defer func() {
+		_ = dcs0.Close()
+		_ = dcs1.Close()
+	}()
+
+	t.Run("acquire leader", func(t *testing.T) {
+		leader, err := dcs0.AcquireLeader(ctx, "pod-0")
+		assert.Nil(t, err)
+		assert.Equal(t, "pod-0", leader)
+
+		leader, err = dcs1.AcquireLeader(ctx, "pod-1")
+		assert.Nil(t, err)
+		assert.Equal(t, "pod-0", leader)
+
+		leader, err = dcs1.GetLeader(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, "pod-0", leader)
+
+		// renew
+		leader, err = dcs0.AcquireLeader(ctx, "pod-0")
+		assert.Nil(t, err)
+		assert.Equal(t, "pod-0", leader)
+	})
+
+	t.Run("release leader", func(t *testing.T) {
+		// not the leader
+		assert.Nil(t, dcs1.ReleaseLeader(ctx, "pod-1"))
+		leader, _ := dcs1.AcquireLeader(ctx, "pod-1")
+		assert.Equal(t, "pod-0", leader)
+
+		assert.Nil(t, dcs0.ReleaseLeader(ctx, "pod-0"))
+		leader, err := dcs1.AcquireLeader(ctx, "pod-1")
+		assert.Nil(t, err)
+		assert.Equal(t, "pod-1", leader)
+	})
+
+	t.Run("take over the lease of a previous session", func(t *testing.T) {
+		restarted := NewEtcdDCS(etcdClient, prefix, 5*time.Second)
+		defer func() {
+			_ = restarted.Close()
+		}()
+
+		leader, err := restarted.AcquireLeader(ctx, "pod-1")
+		assert.Nil(t, err)
+		assert.Equal(t, "pod-1", leader)
+
+		// the leader is kept after the previous session is closed
+		assert.Nil(t, dcs1.Close())
+		leader, err = dcs0.AcquireLeader(ctx, "pod-0")
+		assert.Nil(t, err)
+		assert.Equal(t, "pod-1", leader)
+	})
+
+	t.Run("members", func(t *testing.T) {
+		assert.Nil(t, dcs0.Register(ctx, "pod-0"))
+		assert.Nil(t, dcs1.Register(ctx, "pod-1"))
+
+		members, err := dcs0.GetMembers(ctx)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"pod-0", "pod-1"}, members)
+
+		// the member is dropped with its lease
+		assert.Nil(t, dcs1.Close())
+		members, err = dcs0.GetMembers(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"pod-0"}, members)
+
+		resp, err := etcdClient.Get(ctx, prefix+"/leader")
+		assert.Nil(t, err)
+		assert.Empty(t, resp.Kvs)
+	})
+}
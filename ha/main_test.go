@@ -0,0 +1,71 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ha
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3client"
+)
+
+const etcdStartTimeout = 30 * time.Second
+
+var etcdClient *clientv3.Client
+
+// TestMain runs the tests against an embedded etcd.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "ETCD")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	cfg := embed.NewConfig()
+	cfg.Dir = dir
+	cfg.LogLevel = "error"
+	localURL, _ := url.Parse("http://localhost:0")
+	cfg.ListenPeerUrls = []url.URL{*localURL}
+	cfg.ListenClientUrls = []url.URL{*localURL}
+	server, err := embed.StartEtcd(cfg)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	select {
+	case <-server.Server.ReadyNotify():
+	case <-time.After(etcdStartTimeout):
+		fmt.Println("start embedded etcd server timeout")
+		os.Exit(1)
+	}
+	etcdClient = v3client.New(server.Server)
+
+	code := m.Run()
+
+	_ = etcdClient.Close()
+	server.Close()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
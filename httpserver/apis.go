@@ -23,6 +23,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/valyala/fasthttp"
@@ -39,6 +40,15 @@ const (
 
 type option = func(ctx *fasthttp.RequestCtx)
 
+// operationFailureHandlers are called when an operation changing the database fails.
+var operationFailureHandlers []func(ctx context.Context, operation string, err error)
+
+// OnOperationFailure registers a handler called when an operation which is not
+// readonly fails, with the operation route, e.g. createuser or sentinel/createuser.
+func OnOperationFailure(handler func(ctx context.Context, operation string, err error)) {
+	operationFailureHandlers = append(operationFailureHandlers, handler)
+}
+
 type OperationAPI interface {
 	Endpoints() []Endpoint
 	RegisterOperations(map[string]operations.Operation)
@@ -47,6 +57,9 @@ type OperationAPI interface {
 type api struct {
 	endpoints []Endpoint
 	ready     bool
+	// instances are the named engine instances, whose operations are served by
+	// /v1.0/<instance>/<op> besides the default instance's /v1.0/<op>.
+	instances []string
 }
 
 func (a *api) Endpoints() []Endpoint {
@@ -77,12 +90,24 @@ func (a *api) RegisterOperations(ops map[string]operations.Operation) {
 		endpoint.Route = key
 		endpoint.Handler = OperationWrapper(op)
 		endpoints = append(endpoints, endpoint)
+
+		for _, instance := range a.instances {
+			instanceEndpoint := endpoint
+			instanceEndpoint.Route = instance + "/" + key
+			instanceEndpoint.Handler = InstanceOperationWrapper(op, instance)
+			endpoints = append(endpoints, instanceEndpoint)
+		}
 	}
 	a.endpoints = endpoints
 	a.ready = true
 }
 
 func OperationWrapper(op operations.Operation) fasthttp.RequestHandler {
+	return InstanceOperationWrapper(op, "")
+}
+
+// InstanceOperationWrapper wraps the operation of a named engine instance.
+func InstanceOperationWrapper(op operations.Operation, instance string) fasthttp.RequestHandler {
 	return func(reqCtx *fasthttp.RequestCtx) {
 		ctx := context.Background()
 		body := reqCtx.PostBody()
@@ -107,6 +132,13 @@ func OperationWrapper(op operations.Operation) fasthttp.RequestHandler {
 		opsReq := &operations.OpsRequest{
 			Parameters: req.Parameters,
 			Data:       b,
+			Instance:   instance,
+		}
+		if format := requestFormat(reqCtx); format != "" && opsReq.GetString(operations.FormatParameter) == "" {
+			if opsReq.Parameters == nil {
+				opsReq.Parameters = map[string]any{}
+			}
+			opsReq.Parameters[operations.FormatParameter] = format
 		}
 
 		if err := op.PreCheck(ctx, opsReq); err != nil {
@@ -128,6 +160,12 @@ func OperationWrapper(op operations.Operation) fasthttp.RequestHandler {
 				} else {
 					statusCode = fasthttp.StatusInternalServerError
 					logger.Info("operation exec failed", "error", err.Error())
+					if !op.IsReadonly(ctx) {
+						route := strings.TrimPrefix(string(reqCtx.Path()), "/"+version+"/")
+						for _, handler := range operationFailureHandlers {
+							handler(ctx, route, err)
+						}
+					}
 				}
 				msg := NewErrorResponse("ERR_OPERATION_FAILED", fmt.Sprintf("operation exec failed: %v", err))
 				respond(reqCtx, withError(statusCode, msg))
@@ -148,6 +186,13 @@ func OperationWrapper(op operations.Operation) fasthttp.RequestHandler {
 	}
 }
 
+// requestFormat returns the result format the client asks for by the `format`
+// query arg, empty for the default format of the operation. The Accept header
+// isn't taken, the clients send application/json for the plain role too.
+func requestFormat(reqCtx *fasthttp.RequestCtx) string {
+	return string(reqCtx.QueryArgs().Peek(operations.FormatParameter))
+}
+
 // withJSON overrides the content-type with application/json.
 func withJSON(code int, obj []byte) option {
 	return func(ctx *fasthttp.RequestCtx) {
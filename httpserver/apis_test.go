@@ -25,6 +25,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
 
 	"github.com/apecloud/dbctl/operations"
 )
@@ -46,3 +47,72 @@ func TestRegisterOperations(t *testing.T) {
 	assert.Equal(t, 2, len(fakeAPI.endpoints))
 	assert.Equal(t, "v1.0", fakeAPI.endpoints[0].Version)
 }
+
+func TestRegisterInstanceOperations(t *testing.T) {
+	fakeAPI := &api{
+		instances: []string{"sentinel"},
+	}
+	var instance string
+	fakeOps := map[string]operations.Operation{
+		"fake": operations.NewFakeOperations(operations.FakeDo, func(ctx context.Context, request *operations.OpsRequest) (*operations.OpsResponse, error) {
+			instance = request.Instance
+			return nil, nil
+		}),
+	}
+
+	fakeAPI.RegisterOperations(fakeOps)
+	assert.Equal(t, 2, len(fakeAPI.endpoints))
+	assert.Equal(t, "fake", fakeAPI.endpoints[0].Route)
+	assert.Equal(t, "sentinel/fake", fakeAPI.endpoints[1].Route)
+
+	reqCtx := &fasthttp.RequestCtx{}
+	fakeAPI.endpoints[1].Handler(reqCtx)
+	assert.Equal(t, fasthttp.StatusNoContent, reqCtx.Response.StatusCode())
+	assert.Equal(t, "sentinel", instance)
+}
+
+func TestRequestFormat(t *testing.T) {
+	fakeAPI := &api{}
+	var format string
+	fakeOps := map[string]operations.Operation{
+		"fake": operations.NewFakeOperations(operations.FakeDo, func(ctx context.Context, request *operations.OpsRequest) (*operations.OpsResponse, error) {
+			format = request.GetString(operations.FormatParameter)
+			return nil, nil
+		}),
+	}
+	fakeAPI.RegisterOperations(fakeOps)
+
+	reqCtx := &fasthttp.RequestCtx{}
+	fakeAPI.endpoints[0].Handler(reqCtx)
+	assert.Equal(t, "", format)
+
+	// the existing clients accept JSON for the plain role
+	reqCtx = &fasthttp.RequestCtx{}
+	reqCtx.Request.Header.Set(fasthttp.HeaderAccept, "application/json")
+	fakeAPI.endpoints[0].Handler(reqCtx)
+	assert.Equal(t, "", format)
+
+	reqCtx = &fasthttp.RequestCtx{}
+	reqCtx.Request.SetRequestURI("/v1.0/fake?format=json")
+	fakeAPI.endpoints[0].Handler(reqCtx)
+	assert.Equal(t, operations.FormatJSON, format)
+}
+
+func TestOperationFailure(t *testing.T) {
+	defer func() {
+		operationFailureHandlers = nil
+	}()
+	var failed string
+	OnOperationFailure(func(_ context.Context, operation string, err error) {
+		failed = operation
+	})
+
+	op := operations.NewFakeOperations(operations.FakeDo, func(ctx context.Context, request *operations.OpsRequest) (*operations.OpsResponse, error) {
+		return nil, fmt.Errorf("some error")
+	})
+	reqCtx := &fasthttp.RequestCtx{}
+	reqCtx.Request.SetRequestURI("/v1.0/sentinel/fake")
+	InstanceOperationWrapper(op, "sentinel")(reqCtx)
+	assert.Equal(t, fasthttp.StatusInternalServerError, reqCtx.Response.StatusCode())
+	assert.Equal(t, "sentinel/fake", failed)
+}
@@ -29,6 +29,7 @@ import (
 	fasthttprouter "github.com/fasthttp/router"
 	"github.com/valyala/fasthttp"
 
+	"github.com/apecloud/dbctl/engines/register"
 	"github.com/apecloud/dbctl/operations"
 )
 
@@ -47,7 +48,9 @@ type server struct {
 
 // NewServer returns a new HTTP server.
 func NewServer(ops map[string]operations.Operation) Server {
-	a := &api{}
+	a := &api{
+		instances: register.GetInstances(),
+	}
 	a.RegisterOperations(ops)
 	return &server{
 		api:    a,
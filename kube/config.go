@@ -0,0 +1,47 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package kube
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+type Config struct {
+	RoleLabel     bool
+	Events        bool
+	ProbeInterval time.Duration
+}
+
+var config Config
+var logger = ctrl.Log.WithName("Kubernetes")
+
+func InitFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&config.RoleLabel, "role-label", false, "Patch the role label "+RoleLabelKey+" of the pod on role change, it requires the in-cluster config.")
+	fs.BoolVar(&config.Events, "k8s-events", false, "Emit the Kubernetes events of the pod for the role and readiness changes and the failed operations.")
+	fs.DurationVar(&config.ProbeInterval, "role-probe-interval", 2*time.Second, "The interval to probe the role and readiness for the role label and events.")
+}
+
+// Enabled returns whether the pod is reported to Kubernetes by the flags.
+func Enabled() bool {
+	return config.RoleLabel || config.Events
+}
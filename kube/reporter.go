@@ -0,0 +1,315 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/apecloud/dbctl/constant"
+	"github.com/apecloud/dbctl/engines"
+)
+
+const (
+	RoleLabelKey = "kubeblocks.io/role"
+
+	component = "dbctl"
+
+	// maxPendingEvents bounds the events of the failed operations being emitted in
+	// the background, the others are dropped while the API server is slow.
+	maxPendingEvents = 16
+	eventTimeout     = 10 * time.Second
+)
+
+// probeTimeout bounds a probe of an instance, so that a hung database or API
+// server doesn't hold up the probes of the other instances.
+var probeTimeout = 10 * time.Second
+
+// The reasons of the events.
+const (
+	ReasonRoleChanged     = "RoleChanged"
+	ReasonReady           = "DBReady"
+	ReasonNotReady        = "DBNotReady"
+	ReasonOperationFailed = "OperationFailed"
+)
+
+// Reporter labels the pod with the role of the database, and emits the events of
+// the pod for the role and readiness transitions and the failed operations. The
+// named instances are reported by the events only, the role label is the role of
+// the default instance.
+type Reporter struct {
+	logger    logr.Logger
+	client    client.Client
+	namespace string
+	podName   string
+	roleLabel bool
+	events    bool
+
+	// lock guards the last reported states of the instances, keyed by the instance
+	// name, empty for the default one, which are compared to report the
+	// transitions only.
+	lock   sync.Mutex
+	states map[string]*instanceState
+
+	// pending holds a slot for every event being emitted in the background.
+	pending chan struct{}
+}
+
+type instanceState struct {
+	role  string
+	ready *bool
+}
+
+func NewReporter(c client.Client, namespace, podName string, roleLabel, events bool) *Reporter {
+	return &Reporter{
+		logger:    logger.WithValues("pod", podName),
+		client:    c,
+		namespace: namespace,
+		podName:   podName,
+		roleLabel: roleLabel,
+		events:    events,
+		states:    map[string]*instanceState{},
+		pending:   make(chan struct{}, maxPendingEvents),
+	}
+}
+
+// Run probes the role and readiness of the instances at the interval until the
+// context is done, the managers are keyed by the instance name, empty for the
+// default one. Every probe is bounded by probeTimeout.
+func (r *Reporter) Run(ctx context.Context, mgrs map[string]engines.DBManager, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for instance, mgr := range mgrs {
+			probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+			r.probe(probeCtx, instance, mgr)
+			cancel()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Reporter) probe(ctx context.Context, instance string, mgr engines.DBManager) {
+	ready := isDBAlive(ctx, mgr)
+	r.ReportReadiness(ctx, instance, ready)
+	if !ready {
+		return
+	}
+	role, err := mgr.GetReplicaRole(ctx)
+	if err != nil {
+		r.logger.Info("get role failed", "instance", instance, "error", err.Error())
+		return
+	}
+	if err = r.ReportRole(ctx, instance, role); err != nil {
+		r.logger.Info("report role failed", "instance", instance, "error", err.Error())
+	}
+}
+
+// isDBAlive checks the database on every probe, IsDBStartupReady is kept once the
+// database is ready, so the database is pinged after it's ready. The startup
+// readiness is taken for the managers which can't be pinged.
+func isDBAlive(ctx context.Context, mgr engines.DBManager) bool {
+	if !mgr.IsDBStartupReady() {
+		return false
+	}
+	pinger, ok := mgr.(engines.Pinger)
+	if !ok {
+		return true
+	}
+	return pinger.Ping(ctx) == nil
+}
+
+func (r *Reporter) state(instance string) *instanceState {
+	state, ok := r.states[instance]
+	if !ok {
+		state = &instanceState{}
+		r.states[instance] = state
+	}
+	return state
+}
+
+// ReportRole patches the role label of the pod if the role of the default instance
+// is changed, the role is reported again next time if the patch fails. The role
+// changes of a named instance are reported by the events only. The lock is not
+// held across the calls of the API server.
+func (r *Reporter) ReportRole(ctx context.Context, instance, role string) error {
+	if role == "" || role == r.lastRole(instance) {
+		return nil
+	}
+
+	if r.roleLabel && instance == "" {
+		pod := &corev1.Pod{}
+		if err := r.client.Get(ctx, client.ObjectKey{Namespace: r.namespace, Name: r.podName}, pod); err != nil {
+			return errors.Wrap(err, "get pod failed")
+		}
+		if pod.Labels[RoleLabelKey] != role {
+			patch := client.MergeFrom(pod.DeepCopy())
+			if pod.Labels == nil {
+				pod.Labels = map[string]string{}
+			}
+			pod.Labels[RoleLabelKey] = role
+			if err := r.client.Patch(ctx, pod, patch); err != nil {
+				return errors.Wrap(err, "patch role label failed")
+			}
+		}
+	}
+
+	r.lock.Lock()
+	state := r.state(instance)
+	// reported by another caller while the label is patched
+	if role == state.role {
+		r.lock.Unlock()
+		return nil
+	}
+	message := fmt.Sprintf("role changed to %s", role)
+	if state.role != "" {
+		message = fmt.Sprintf("role changed from %s to %s", state.role, role)
+	}
+	state.role = role
+	r.lock.Unlock()
+
+	message = withInstance(instance, message)
+	r.logger.Info(message)
+	r.emit(ctx, corev1.EventTypeNormal, ReasonRoleChanged, message)
+	return nil
+}
+
+func (r *Reporter) lastRole(instance string) string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.state(instance).role
+}
+
+// ReportReadiness emits an event when the instance becomes ready or not ready.
+func (r *Reporter) ReportReadiness(ctx context.Context, instance string, ready bool) {
+	r.lock.Lock()
+	state := r.state(instance)
+	if state.ready != nil && *state.ready == ready {
+		r.lock.Unlock()
+		return
+	}
+	// a database starting up is not reported as not ready
+	first := state.ready == nil
+	state.ready = &ready
+	r.lock.Unlock()
+
+	switch {
+	case ready:
+		r.emit(ctx, corev1.EventTypeNormal, ReasonReady, withInstance(instance, "database is ready"))
+	case !first:
+		r.emit(ctx, corev1.EventTypeWarning, ReasonNotReady, withInstance(instance, "database is not ready"))
+	}
+}
+
+// ReportOperationFailure emits a warning event for the failed operation in the
+// background, so that the response of the operation is not held up by the API
+// server. The event is dropped if too many events are pending.
+func (r *Reporter) ReportOperationFailure(_ context.Context, operation string, err error) {
+	if !r.events {
+		return
+	}
+	message := fmt.Sprintf("operation %s failed: %v", operation, err)
+	select {
+	case r.pending <- struct{}{}:
+	default:
+		r.logger.Info("too many pending events, the event is dropped", "reason", ReasonOperationFailed, "message", message)
+		return
+	}
+	go func() {
+		defer func() { <-r.pending }()
+		ctx, cancel := context.WithTimeout(context.Background(), eventTimeout)
+		defer cancel()
+		r.emit(ctx, corev1.EventTypeWarning, ReasonOperationFailed, message)
+	}()
+}
+
+// withInstance prefixes the message with the named instance.
+func withInstance(instance, message string) string {
+	if instance == "" {
+		return message
+	}
+	return fmt.Sprintf("instance %s: %s", instance, message)
+}
+
+func (r *Reporter) emit(ctx context.Context, eventType, reason, message string) {
+	if !r.events {
+		return
+	}
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: r.podName + ".",
+			Namespace:    r.namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Namespace:  r.namespace,
+			Name:       r.podName,
+		},
+		Reason:              reason,
+		Message:             message,
+		Type:                eventType,
+		Source:              corev1.EventSource{Component: component},
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+		ReportingController: component,
+		ReportingInstance:   r.podName,
+	}
+	if err := r.client.Create(ctx, event); err != nil {
+		r.logger.Info("emit event failed", "reason", reason, "error", err.Error())
+	}
+}
+
+// Start runs the reporter of the pod with the in-cluster config and the flags
+// until the context is done, the managers are keyed by the instance name, empty
+// for the default one.
+func Start(ctx context.Context, mgrs map[string]engines.DBManager) (*Reporter, error) {
+	namespace := constant.GetNamespace()
+	if namespace == "" {
+		return nil, errors.New("namespace of the pod is not set")
+	}
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "get kubernetes config failed")
+	}
+	c, err := client.New(restConfig, client.Options{})
+	if err != nil {
+		return nil, errors.Wrap(err, "create kubernetes client failed")
+	}
+
+	reporter := NewReporter(c, namespace, constant.GetPodName(), config.RoleLabel, config.Events)
+	go reporter.Run(ctx, mgrs, config.ProbeInterval)
+	return reporter, nil
+}
@@ -0,0 +1,252 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package kube
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/apecloud/dbctl/engines"
+)
+
+const (
+	fakeNamespace = "default"
+	fakePodName   = "mysql-0"
+)
+
+func newFakeClient() client.Client {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: fakeNamespace,
+			Name:      fakePodName,
+			Labels:    map[string]string{"app": "mysql"},
+		},
+	}
+	return fake.NewClientBuilder().WithObjects(pod).Build()
+}
+
+func getPod(t *testing.T, c client.Client) *corev1.Pod {
+	pod := &corev1.Pod{}
+	assert.Nil(t, c.Get(context.TODO(), client.ObjectKey{Namespace: fakeNamespace, Name: fakePodName}, pod))
+	return pod
+}
+
+func listEvents(t *testing.T, c client.Client) []corev1.Event {
+	events := &corev1.EventList{}
+	assert.Nil(t, c.List(context.TODO(), events, client.InNamespace(fakeNamespace)))
+	return events.Items
+}
+
+func TestReportRole(t *testing.T) {
+	ctx := context.TODO()
+	c := newFakeClient()
+	reporter := NewReporter(c, fakeNamespace, fakePodName, true, true)
+
+	assert.Nil(t, reporter.ReportRole(ctx, "", "secondary"))
+	pod := getPod(t, c)
+	assert.Equal(t, "secondary", pod.Labels[RoleLabelKey])
+	assert.Equal(t, "mysql", pod.Labels["app"])
+
+	// not changed
+	assert.Nil(t, reporter.ReportRole(ctx, "", "secondary"))
+	assert.Len(t, listEvents(t, c), 1)
+
+	assert.Nil(t, reporter.ReportRole(ctx, "", "primary"))
+	assert.Equal(t, "primary", getPod(t, c).Labels[RoleLabelKey])
+
+	events := listEvents(t, c)
+	assert.Len(t, events, 2)
+	var messages []string
+	for _, event := range events {
+		assert.Equal(t, ReasonRoleChanged, event.Reason)
+		assert.Equal(t, corev1.EventTypeNormal, event.Type)
+		assert.Equal(t, fakePodName, event.InvolvedObject.Name)
+		messages = append(messages, event.Message)
+	}
+	assert.ElementsMatch(t, []string{"role changed to secondary", "role changed from secondary to primary"}, messages)
+}
+
+func TestReportRoleWithoutLabel(t *testing.T) {
+	c := newFakeClient()
+	reporter := NewReporter(c, fakeNamespace, fakePodName, false, true)
+
+	assert.Nil(t, reporter.ReportRole(context.TODO(), "", "primary"))
+	assert.NotContains(t, getPod(t, c).Labels, RoleLabelKey)
+	assert.Len(t, listEvents(t, c), 1)
+}
+
+func TestReportRolePodNotFound(t *testing.T) {
+	ctx := context.TODO()
+	reporter := NewReporter(fake.NewClientBuilder().Build(), fakeNamespace, fakePodName, true, false)
+
+	assert.NotNil(t, reporter.ReportRole(ctx, "", "primary"))
+	// reported again
+	assert.NotNil(t, reporter.ReportRole(ctx, "", "primary"))
+}
+
+func TestReportReadiness(t *testing.T) {
+	ctx := context.TODO()
+	c := newFakeClient()
+	reporter := NewReporter(c, fakeNamespace, fakePodName, false, true)
+
+	// starting up
+	reporter.ReportReadiness(ctx, "", false)
+	assert.Empty(t, listEvents(t, c))
+
+	reporter.ReportReadiness(ctx, "", true)
+	reporter.ReportReadiness(ctx, "", true)
+	reporter.ReportReadiness(ctx, "", false)
+
+	reasons := map[string]string{}
+	for _, event := range listEvents(t, c) {
+		reasons[event.Reason] = event.Type
+	}
+	assert.Equal(t, map[string]string{ReasonReady: corev1.EventTypeNormal, ReasonNotReady: corev1.EventTypeWarning}, reasons)
+}
+
+func TestReportNamedInstance(t *testing.T) {
+	ctx := context.TODO()
+	c := newFakeClient()
+	reporter := NewReporter(c, fakeNamespace, fakePodName, true, true)
+
+	reporter.ReportReadiness(ctx, "sentinel", true)
+	assert.Nil(t, reporter.ReportRole(ctx, "sentinel", "leader"))
+	// the role label is the role of the default instance
+	assert.NotContains(t, getPod(t, c).Labels, RoleLabelKey)
+	assert.Nil(t, reporter.ReportRole(ctx, "", "primary"))
+	assert.Equal(t, "primary", getPod(t, c).Labels[RoleLabelKey])
+
+	var messages []string
+	for _, event := range listEvents(t, c) {
+		messages = append(messages, event.Message)
+	}
+	assert.ElementsMatch(t, []string{
+		"instance sentinel: database is ready",
+		"instance sentinel: role changed to leader",
+		"role changed to primary",
+	}, messages)
+}
+
+func TestReportOperationFailure(t *testing.T) {
+	c := newFakeClient()
+	reporter := NewReporter(c, fakeNamespace, fakePodName, false, true)
+
+	reporter.ReportOperationFailure(context.TODO(), "switchover", errors.New("no candidate"))
+	reporter.ReportOperationFailure(context.TODO(), "sentinel/createuser", errors.New("user exists"))
+	// the events are emitted in the background
+	assert.Eventually(t, func() bool {
+		return len(listEvents(t, c)) == 2
+	}, time.Second, 10*time.Millisecond)
+	var messages []string
+	for _, event := range listEvents(t, c) {
+		assert.Equal(t, ReasonOperationFailed, event.Reason)
+		assert.Equal(t, corev1.EventTypeWarning, event.Type)
+		messages = append(messages, event.Message)
+	}
+	assert.ElementsMatch(t, []string{"operation switchover failed: no candidate", "operation sentinel/createuser failed: user exists"}, messages)
+}
+
+// pingManager is ready once started up, and alive as long as the ping succeeds.
+type pingManager struct {
+	engines.DBManagerBase
+	pingErr error
+}
+
+func (mgr *pingManager) Ping(context.Context) error {
+	return mgr.pingErr
+}
+
+func TestProbe(t *testing.T) {
+	c := newFakeClient()
+	reporter := NewReporter(c, fakeNamespace, fakePodName, true, true)
+	mgr := &pingManager{}
+
+	// not ready
+	reporter.probe(context.TODO(), "", mgr)
+	assert.NotContains(t, getPod(t, c).Labels, RoleLabelKey)
+
+	// ready, but the role is not implemented
+	mgr.DBStartupReady = true
+	reporter.probe(context.TODO(), "", mgr)
+	assert.NotContains(t, getPod(t, c).Labels, RoleLabelKey)
+
+	// the startup readiness is kept, but the database is down
+	mgr.pingErr = errors.New("connection refused")
+	reporter.probe(context.TODO(), "", mgr)
+
+	reasons := map[string]string{}
+	for _, event := range listEvents(t, c) {
+		reasons[event.Reason] = event.Type
+	}
+	assert.Equal(t, map[string]string{ReasonReady: corev1.EventTypeNormal, ReasonNotReady: corev1.EventTypeWarning}, reasons)
+}
+
+// roleManager is ready with the role, and its ping hangs until the context is done
+// if hung is set.
+type roleManager struct {
+	engines.DBManagerBase
+	role string
+	hung bool
+}
+
+func (mgr *roleManager) Ping(ctx context.Context) error {
+	if mgr.hung {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (mgr *roleManager) GetReplicaRole(context.Context) (string, error) {
+	return mgr.role, nil
+}
+
+func TestRunWithHungProbe(t *testing.T) {
+	defer func(timeout time.Duration) { probeTimeout = timeout }(probeTimeout)
+	probeTimeout = 50 * time.Millisecond
+
+	c := newFakeClient()
+	reporter := NewReporter(c, fakeNamespace, fakePodName, true, false)
+	mgrs := map[string]engines.DBManager{
+		"":         &roleManager{DBManagerBase: engines.DBManagerBase{DBStartupReady: true}, role: "primary"},
+		"sentinel": &roleManager{DBManagerBase: engines.DBManagerBase{DBStartupReady: true}, hung: true},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		reporter.Run(ctx, mgrs, time.Hour)
+	}()
+
+	// the hung instance times out, and doesn't hold up the default one
+	assert.Eventually(t, func() bool {
+		return getPod(t, c).Labels[RoleLabelKey] == "primary"
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package fake
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines/fake"
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/register"
+	"github.com/apecloud/dbctl/operations"
+	"github.com/apecloud/dbctl/util"
+)
+
+// Control scripts the state of the fake engine at runtime, the parameters of the
+// request are a fake.Scenario, which replaces the running one. It's not implemented
+// for the named instances of the other engines.
+type Control struct {
+	operations.Base
+}
+
+var control operations.Operation = &Control{}
+
+// Register registers the fakecontrol operation, it's served only if the fake
+// engine runs, so the other engines don't expose it.
+func Register() error {
+	return operations.Register("fakecontrol", control)
+}
+
+func (s *Control) Init(context.Context) error {
+	s.Logger = ctrl.Log.WithName("fakecontrol")
+	_, err := register.GetDBManager()
+	if err != nil {
+		return errors.Wrap(err, "get manager failed")
+	}
+	return nil
+}
+
+func (s *Control) Do(ctx context.Context, req *operations.OpsRequest) (*operations.OpsResponse, error) {
+	resp := operations.NewOpsResponse(util.FakeControlOperation)
+
+	dbManager, err := register.GetInstanceDBManager(req.Instance)
+	if err != nil {
+		return resp, err
+	}
+	fakeManager, ok := dbManager.(*fake.Manager)
+	if !ok {
+		return resp, models.ErrNotImplemented
+	}
+
+	if len(req.Parameters) > 0 {
+		data, err := json.Marshal(req.Parameters)
+		if err != nil {
+			return resp.WithError(err)
+		}
+		scenario, err := fake.ParseScenario(data)
+		if err != nil {
+			return resp.WithError(err)
+		}
+		fakeManager.RunScenario(scenario)
+		s.Logger.Info("fake scenario applied", "steps", len(scenario.Steps))
+	}
+
+	resp.Data["state"] = fakeManager.GetState()
+	return resp.WithSuccess("")
+}
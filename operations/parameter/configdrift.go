@@ -0,0 +1,83 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package parameter
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/register"
+	"github.com/apecloud/dbctl/operations"
+	"github.com/apecloud/dbctl/util"
+)
+
+// ConfigDrift compares the config files with the parameters the database is
+// running with, to find the parameters which don't take effect. The config file
+// can be given by the `file` parameter.
+type ConfigDrift struct {
+	operations.Base
+}
+
+var configDrift operations.Operation = &ConfigDrift{}
+
+func init() {
+	err := operations.Register("configdrift", configDrift)
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+func (s *ConfigDrift) Init(context.Context) error {
+	s.Logger = ctrl.Log.WithName("configdrift")
+	_, err := register.GetDBManager()
+	if err != nil {
+		return errors.Wrap(err, "get manager failed")
+	}
+	return nil
+}
+
+func (s *ConfigDrift) IsReadonly(context.Context) bool {
+	return true
+}
+
+func (s *ConfigDrift) Do(ctx context.Context, req *operations.OpsRequest) (*operations.OpsResponse, error) {
+	resp := operations.NewOpsResponse(util.ConfigDriftOperation)
+
+	dbManager, err := register.GetInstanceDBManager(req.Instance)
+	if err != nil {
+		return resp, err
+	}
+	checker, ok := dbManager.(engines.ConfigDriftChecker)
+	if !ok {
+		return resp, models.ErrNotImplemented
+	}
+
+	report, err := checker.CheckConfigDrift(ctx, req.GetString("file"))
+	if err != nil {
+		s.Logger.Info("check config drift failed", "error", err.Error())
+		return resp, err
+	}
+	resp.Data["report"] = report
+	return resp.WithSuccess("")
+}
@@ -0,0 +1,145 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package parameter
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/register"
+	"github.com/apecloud/dbctl/operations"
+	"github.com/apecloud/dbctl/util"
+)
+
+// Reconfigure applies the dynamic parameters in the `parameters` parameter online,
+// and reports the static ones as requiring a restart.
+type Reconfigure struct {
+	operations.Base
+}
+
+var reconfigure operations.Operation = &Reconfigure{}
+
+func init() {
+	err := operations.Register("reconfigure", reconfigure)
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+func (s *Reconfigure) Init(context.Context) error {
+	s.Logger = ctrl.Log.WithName("reconfigure")
+	_, err := register.GetDBManager()
+	if err != nil {
+		return errors.Wrap(err, "get manager failed")
+	}
+	return nil
+}
+
+func (s *Reconfigure) PreCheck(_ context.Context, req *operations.OpsRequest) error {
+	if len(getParameters(req)) == 0 {
+		return errors.New("no parameters to reconfigure")
+	}
+	return nil
+}
+
+func (s *Reconfigure) Do(ctx context.Context, req *operations.OpsRequest) (*operations.OpsResponse, error) {
+	resp := operations.NewOpsResponse(util.ReconfigureOperation)
+
+	dbManager, err := register.GetInstanceDBManager(req.Instance)
+	if err != nil {
+		return resp, err
+	}
+	reconfigurer, ok := dbManager.(engines.Reconfigurer)
+	if !ok {
+		return resp, models.ErrNotImplemented
+	}
+
+	result, err := s.reconfigure(ctx, reconfigurer, getParameters(req))
+	if err != nil {
+		return resp, err
+	}
+	resp.Data["result"] = result
+	return resp.WithSuccess("")
+}
+
+// reconfigure handles every parameter on its own, a rejected parameter doesn't
+// stop the others to be applied. It fails only if the engine doesn't implement
+// the reconfiguration, e.g. it's inherited from the engine of the same protocol.
+func (s *Reconfigure) reconfigure(ctx context.Context, reconfigurer engines.Reconfigurer, parameters map[string]string) (*models.ReconfigureResult, error) {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &models.ReconfigureResult{
+		Applied:         []string{},
+		RestartRequired: []string{},
+		NotPersisted:    []string{},
+		Rejected:        []models.RejectedParameter{},
+	}
+	reject := func(name string, err error) {
+		s.Logger.Info("parameter rejected", "name", name, "error", err.Error())
+		result.Rejected = append(result.Rejected, models.RejectedParameter{Name: name, Reason: err.Error()})
+	}
+	for _, name := range names {
+		kind, err := reconfigurer.ClassifyParameter(ctx, name)
+		if errors.Is(err, models.ErrNotImplemented) {
+			return nil, err
+		}
+		if err != nil {
+			reject(name, err)
+			continue
+		}
+		// a static parameter is persisted to be taken at the next start
+		err = reconfigurer.SetParameter(ctx, name, parameters[name])
+		switch {
+		case errors.Is(err, models.ErrRestartRequired), err == nil && kind == models.ParameterStatic:
+			s.Logger.Info("parameter persisted, restart required", "name", name)
+			result.RestartRequired = append(result.RestartRequired, name)
+		case errors.Is(err, models.ErrNotPersisted):
+			s.Logger.Info("parameter not persisted, set it in the config file and restart", "name", name)
+			result.RestartRequired = append(result.RestartRequired, name)
+			result.NotPersisted = append(result.NotPersisted, name)
+		case err != nil:
+			reject(name, err)
+		default:
+			s.Logger.Info("parameter applied", "name", name)
+			result.Applied = append(result.Applied, name)
+		}
+	}
+	return result, nil
+}
+
+// getParameters returns the parameters to reconfigure, the values are formatted
+// as strings, e.g. 100 or true.
+func getParameters(req *operations.OpsRequest) map[string]string {
+	parameters := map[string]string{}
+	for name, value := range cast.ToStringMap(req.Parameters["parameters"]) {
+		parameters[name] = cast.ToString(value)
+	}
+	return parameters
+}
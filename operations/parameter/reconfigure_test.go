@@ -0,0 +1,117 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package parameter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/operations"
+)
+
+type fakeReconfigurer struct {
+	kinds   map[string]models.ParameterKind
+	applied map[string]string
+}
+
+func (f *fakeReconfigurer) ClassifyParameter(_ context.Context, name string) (models.ParameterKind, error) {
+	kind, ok := f.kinds[name]
+	if !ok {
+		return "", models.ErrUnknownParameter
+	}
+	return kind, nil
+}
+
+func (f *fakeReconfigurer) SetParameter(_ context.Context, name, value string) error {
+	switch name {
+	case "late_static":
+		return models.ErrRestartRequired
+	case "invalid":
+		return errors.New("invalid value")
+	case "databases":
+		return models.ErrNotPersisted
+	}
+	f.applied[name] = value
+	return nil
+}
+
+func TestReconfigure(t *testing.T) {
+	reconfigurer := &fakeReconfigurer{
+		kinds: map[string]models.ParameterKind{
+			"max_connections": models.ParameterDynamic,
+			"late_static":     models.ParameterDynamic,
+			"invalid":         models.ParameterDynamic,
+			"shared_buffers":  models.ParameterStatic,
+			"databases":       models.ParameterStatic,
+		},
+		applied: map[string]string{},
+	}
+	req := &operations.OpsRequest{Parameters: map[string]any{
+		"parameters": map[string]any{
+			"max_connections": float64(200),
+			"late_static":     "on",
+			"invalid":         "x",
+			"shared_buffers":  "1GB",
+			"databases":       32,
+			"unknown":         true,
+		},
+	}}
+	op := &Reconfigure{Base: operations.Base{Logger: ctrl.Log}}
+
+	result, err := op.reconfigure(context.TODO(), reconfigurer, getParameters(req))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"max_connections"}, result.Applied)
+	// the static parameter is persisted for the next start
+	assert.Equal(t, map[string]string{"max_connections": "200", "shared_buffers": "1GB"}, reconfigurer.applied)
+	assert.Equal(t, []string{"databases", "late_static", "shared_buffers"}, result.RestartRequired)
+	// the static parameter which can't be persisted requires a restart as well
+	assert.Equal(t, []string{"databases"}, result.NotPersisted)
+	assert.Equal(t, []models.RejectedParameter{
+		{Name: "invalid", Reason: "invalid value"},
+		{Name: "unknown", Reason: "unknown parameter"},
+	}, result.Rejected)
+
+	// the reconfiguration inherited from the engine of the same protocol is not implemented
+	_, err = op.reconfigure(context.TODO(), notImplementedReconfigurer{}, getParameters(req))
+	assert.ErrorIs(t, err, models.ErrNotImplemented)
+}
+
+type notImplementedReconfigurer struct{}
+
+func (notImplementedReconfigurer) ClassifyParameter(context.Context, string) (models.ParameterKind, error) {
+	return "", models.ErrNotImplemented
+}
+
+func (notImplementedReconfigurer) SetParameter(context.Context, string, string) error {
+	return models.ErrNotImplemented
+}
+
+func TestPreCheck(t *testing.T) {
+	op := &Reconfigure{}
+	assert.NotNil(t, op.PreCheck(context.TODO(), &operations.OpsRequest{}))
+	assert.Nil(t, op.PreCheck(context.TODO(), &operations.OpsRequest{Parameters: map[string]any{
+		"parameters": map[string]any{"maxmemory": "1gb"},
+	}}))
+}
@@ -21,7 +21,9 @@ package register
 
 import (
 	"github.com/apecloud/dbctl/operations"
+	_ "github.com/apecloud/dbctl/operations/parameter"
 	_ "github.com/apecloud/dbctl/operations/replica"
+	_ "github.com/apecloud/dbctl/operations/session"
 	_ "github.com/apecloud/dbctl/operations/sql"
 )
 
@@ -0,0 +1,248 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package replica
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/register"
+	"github.com/apecloud/dbctl/operations"
+	"github.com/apecloud/dbctl/util"
+)
+
+// CheckSplitBrain asks every member in the topology, or those of them in the
+// `members` parameter, for its own view of the roles, and reports more than one writable
+// primary, or the members following different leaders. With the `fence`
+// parameter, the stale primaries are locked read-only.
+type CheckSplitBrain struct {
+	operations.Base
+}
+
+var checkSplitBrain operations.Operation = &CheckSplitBrain{}
+
+func init() {
+	err := operations.Register("checksplitbrain", checkSplitBrain)
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+func (s *CheckSplitBrain) Init(context.Context) error {
+	s.Logger = ctrl.Log.WithName("checksplitbrain")
+	_, err := register.GetDBManager()
+	if err != nil {
+		return errors.Wrap(err, "get manager failed")
+	}
+	return nil
+}
+
+func (s *CheckSplitBrain) Do(ctx context.Context, req *operations.OpsRequest) (*operations.OpsResponse, error) {
+	resp := operations.NewOpsResponse(util.CheckSplitBrainOperation)
+
+	dbManager, err := register.GetInstanceDBManager(req.Instance)
+	if err != nil {
+		return resp, err
+	}
+
+	members, err := s.getMembers(ctx, dbManager, req)
+	if err != nil {
+		s.Logger.Info("get members failed", "error", err)
+		return resp, err
+	}
+
+	// the members are reported by their addresses, the local one may have no
+	// address in the topology, so it's reported by the name.
+	addresses := map[string]string{}
+	views := make([]models.MemberView, 0, len(members))
+	for _, member := range members {
+		view, err := dbManager.GetMemberView(ctx, member.Address)
+		if errors.Is(err, models.ErrNotImplemented) {
+			return resp, err
+		}
+		if err != nil {
+			view = &models.MemberView{Error: err.Error()}
+		}
+		view.Address = member.Address
+		if view.Address == "" {
+			view.Address = member.Name
+		}
+		addresses[view.Address] = member.Address
+		views = append(views, *view)
+	}
+
+	report := detectSplitBrain(views)
+	if report.SplitBrain {
+		s.Logger.Info("split brain detected", "reasons", report.Reasons, "primaries", report.Primaries)
+	}
+	if req.GetBool("fence") {
+		for _, stale := range report.Stale {
+			if err := dbManager.FenceMember(ctx, addresses[stale]); err != nil {
+				s.Logger.Info("fence member failed", "member", stale, "error", err)
+				report.Reasons = append(report.Reasons, fmt.Sprintf("fence %s failed: %v", stale, err))
+				continue
+			}
+			s.Logger.Info("stale primary fenced", "member", stale)
+			report.Fenced = append(report.Fenced, stale)
+		}
+	}
+
+	resp.Data["report"] = report
+	return resp.WithSuccess("")
+}
+
+// getMembers returns the members in the topology, or those of them given by the
+// `members` parameter. An address out of the topology is rejected, since the
+// members are connected with the local credentials and may be fenced.
+func (s *CheckSplitBrain) getMembers(ctx context.Context, dbManager engines.DBManager, req *operations.OpsRequest) ([]models.Member, error) {
+	topology, err := dbManager.GetTopology(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addresses := cast.ToStringSlice(req.Parameters["members"])
+	if len(addresses) == 0 {
+		return topology.Members, nil
+	}
+
+	members := make([]models.Member, 0, len(addresses))
+	for _, address := range addresses {
+		i := slices.IndexFunc(topology.Members, func(member models.Member) bool {
+			return member.Address != "" && models.IsSameMember(member.Address, address)
+		})
+		if i < 0 {
+			return nil, errors.Errorf("member %s is not in the topology", address)
+		}
+		members = append(members, topology.Members[i])
+	}
+	return members, nil
+}
+
+// detectSplitBrain compares the views of the reachable members.
+func detectSplitBrain(views []models.MemberView) *models.SplitBrainReport {
+	report := &models.SplitBrainReport{Members: views}
+
+	var primaries []models.MemberView
+	var leaders []string
+	for _, view := range views {
+		if view.Error != "" {
+			continue
+		}
+		if view.Writable {
+			primaries = append(primaries, view)
+			report.Primaries = append(report.Primaries, view.Address)
+		}
+		if view.Leader != "" && !containsMember(leaders, view.Leader) {
+			leaders = append(leaders, view.Leader)
+		}
+	}
+
+	if len(primaries) > 1 {
+		report.SplitBrain = true
+		report.Reasons = append(report.Reasons, fmt.Sprintf("%d writable primaries: %s",
+			len(primaries), strings.Join(report.Primaries, ", ")))
+	}
+	if len(leaders) > 1 {
+		report.SplitBrain = true
+		report.Reasons = append(report.Reasons, fmt.Sprintf("members follow different leaders: %s",
+			strings.Join(leaders, ", ")))
+	}
+	for i := range primaries {
+		for j := i + 1; j < len(primaries); j++ {
+			if primaries[i].Term != 0 && primaries[i].Term == primaries[j].Term {
+				report.Reasons = append(report.Reasons, fmt.Sprintf("%s and %s are primaries in the same term %d",
+					primaries[i].Address, primaries[j].Address, primaries[i].Term))
+			}
+		}
+	}
+
+	if len(primaries) > 1 {
+		report.Stale = stalePrimaries(primaries, views)
+		if len(report.Stale) == 0 {
+			report.Reasons = append(report.Reasons, "can't tell the stale primary")
+		}
+	}
+	return report
+}
+
+// stalePrimaries returns the primaries in the older terms, then the primaries
+// followed by fewer members among those in the latest term.
+func stalePrimaries(primaries, views []models.MemberView) []string {
+	var latestTerm int64
+	for _, primary := range primaries {
+		latestTerm = max(latestTerm, primary.Term)
+	}
+
+	var stale []string
+	var candidates []models.MemberView
+	for _, primary := range primaries {
+		if primary.Term < latestTerm {
+			stale = append(stale, primary.Address)
+		} else {
+			candidates = append(candidates, primary)
+		}
+	}
+	if len(candidates) == 1 {
+		return stale
+	}
+
+	followers := make([]int, len(candidates))
+	for _, view := range views {
+		for i, candidate := range candidates {
+			if view.Leader != "" && models.IsSameMember(view.Leader, candidate.Address) {
+				followers[i]++
+			}
+		}
+	}
+	winner, tie := 0, false
+	for i := 1; i < len(candidates); i++ {
+		switch {
+		case followers[i] > followers[winner]:
+			winner, tie = i, false
+		case followers[i] == followers[winner]:
+			tie = true
+		}
+	}
+	if tie {
+		return stale
+	}
+	for i, candidate := range candidates {
+		if i != winner {
+			stale = append(stale, candidate.Address)
+		}
+	}
+	return stale
+}
+
+func containsMember(addresses []string, address string) bool {
+	for _, a := range addresses {
+		if models.IsSameMember(a, address) {
+			return true
+		}
+	}
+	return false
+}
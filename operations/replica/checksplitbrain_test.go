@@ -0,0 +1,116 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package replica
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/operations"
+)
+
+type topologyManager struct {
+	engines.DBManagerBase
+	members []models.Member
+}
+
+func (mgr *topologyManager) GetTopology(context.Context) (*models.Topology, error) {
+	return &models.Topology{Members: mgr.members}, nil
+}
+
+func TestGetMembers(t *testing.T) {
+	ctx := context.TODO()
+	mgr := &topologyManager{members: []models.Member{
+		{Name: "mysql-0", Address: "mysql-0.mysql-headless:3306"},
+		{Name: "mysql-1", Address: "mysql-1.mysql-headless:3306"},
+	}}
+	s := &CheckSplitBrain{}
+
+	members, err := s.getMembers(ctx, mgr, &operations.OpsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, mgr.members, members)
+
+	members, err = s.getMembers(ctx, mgr, &operations.OpsRequest{Parameters: map[string]any{"members": []any{"mysql-1"}}})
+	require.NoError(t, err)
+	assert.Equal(t, mgr.members[1:], members)
+
+	// the members out of the topology are never connected
+	_, err = s.getMembers(ctx, mgr, &operations.OpsRequest{Parameters: map[string]any{"members": []any{"mysql-1", "attacker.example.com:3306"}}})
+	assert.ErrorContains(t, err, "member attacker.example.com:3306 is not in the topology")
+}
+
+func TestDetectSplitBrain(t *testing.T) {
+	t.Run("healthy", func(t *testing.T) {
+		report := detectSplitBrain([]models.MemberView{
+			{Address: "pg-0", Role: models.PRIMARY, Writable: true, Term: 2},
+			{Address: "10.0.0.2", Role: models.SECONDARY, Term: 2, Leader: "pg-0.pg-headless.default.svc"},
+			{Address: "10.0.0.3", Error: "connection refused"},
+		})
+		assert.False(t, report.SplitBrain)
+		assert.Equal(t, []string{"pg-0"}, report.Primaries)
+		assert.Empty(t, report.Stale)
+	})
+
+	t.Run("stale primary in an older term", func(t *testing.T) {
+		report := detectSplitBrain([]models.MemberView{
+			{Address: "pg-0", Role: models.PRIMARY, Writable: true, Term: 2},
+			{Address: "pg-1", Role: models.PRIMARY, Writable: true, Term: 3},
+			{Address: "pg-2", Role: models.SECONDARY, Term: 3, Leader: "pg-1:5432"},
+		})
+		assert.True(t, report.SplitBrain)
+		assert.Equal(t, []string{"pg-0", "pg-1"}, report.Primaries)
+		assert.Equal(t, []string{"pg-0"}, report.Stale)
+	})
+
+	t.Run("stale primary followed by fewer members", func(t *testing.T) {
+		report := detectSplitBrain([]models.MemberView{
+			{Address: "mysql-0:3306", Role: models.PRIMARY, Writable: true},
+			{Address: "mysql-1:3306", Role: models.PRIMARY, Writable: true},
+			{Address: "mysql-2:3306", Role: models.SECONDARY, Leader: "mysql-1.mysql-headless:3306"},
+		})
+		assert.True(t, report.SplitBrain)
+		assert.Equal(t, []string{"mysql-0:3306"}, report.Stale)
+	})
+
+	t.Run("primaries in the same term", func(t *testing.T) {
+		report := detectSplitBrain([]models.MemberView{
+			{Address: "wesql-0", Role: models.LEADER, Writable: true, Term: 5},
+			{Address: "wesql-1", Role: models.LEADER, Writable: true, Term: 5},
+		})
+		assert.True(t, report.SplitBrain)
+		assert.Empty(t, report.Stale)
+		assert.Contains(t, report.Reasons, "wesql-0 and wesql-1 are primaries in the same term 5")
+		assert.Contains(t, report.Reasons, "can't tell the stale primary")
+	})
+
+	t.Run("members follow different leaders", func(t *testing.T) {
+		report := detectSplitBrain([]models.MemberView{
+			{Address: "http://etcd-0:2379", Role: models.FOLLOWER, Term: 4, Leader: "http://etcd-1:2379"},
+			{Address: "http://etcd-2:2379", Role: models.FOLLOWER, Term: 4, Leader: "http://etcd-3:2379"},
+		})
+		assert.True(t, report.SplitBrain)
+		assert.Empty(t, report.Primaries)
+	})
+}
@@ -33,7 +33,6 @@ import (
 
 type GetRole struct {
 	operations.Base
-	DBManager engines.DBManager
 }
 
 var getrole operations.Operation = &GetRole{}
@@ -47,12 +46,10 @@ func init() {
 
 func (s *GetRole) Init(context.Context) error {
 	s.Logger = ctrl.Log.WithName("getrole")
-	dbManager, err := register.GetDBManager()
+	_, err := register.GetDBManager()
 	if err != nil {
 		return errors.Wrap(err, "get manager failed")
 	}
-
-	s.DBManager = dbManager
 	return nil
 }
 
@@ -66,7 +63,29 @@ func (s *GetRole) Do(ctx context.Context, req *operations.OpsRequest) (*operatio
 	}
 	resp.Data["operation"] = util.GetRoleOperation
 
-	role, err := s.DBManager.GetReplicaRole(ctx)
+	dbManager, err := register.GetInstanceDBManager(req.Instance)
+	if err != nil {
+		return resp, err
+	}
+
+	if req.GetString(operations.FormatParameter) == operations.FormatJSON {
+		info, err := engines.GetReplicaRoleInfo(ctx, dbManager)
+		if err != nil {
+			s.Logger.Info("executing getrole error", "error", err)
+			return resp, err
+		}
+		resp.Data["role"] = info.Role
+		resp.Data["nativeRole"] = info.NativeRole
+		resp.Data["writable"] = info.Writable
+		resp.Data["voter"] = info.Voter
+		resp.Data["health"] = info.Health
+		if info.Details != nil {
+			resp.Data["details"] = info.Details
+		}
+		return resp, nil
+	}
+
+	role, err := dbManager.GetReplicaRole(ctx)
 	if err != nil {
 		s.Logger.Info("executing getrole error", "error", err)
 		return resp, err
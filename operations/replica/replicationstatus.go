@@ -0,0 +1,77 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package replica
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines/register"
+	"github.com/apecloud/dbctl/operations"
+	"github.com/apecloud/dbctl/util"
+)
+
+// ReplicationStatus returns the upstream, the lag, the thread states and the
+// positions of the replication, see models.ReplicationStatus.
+type ReplicationStatus struct {
+	operations.Base
+}
+
+var replicationStatus operations.Operation = &ReplicationStatus{}
+
+func init() {
+	err := operations.Register("replicationstatus", replicationStatus)
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+func (s *ReplicationStatus) Init(context.Context) error {
+	s.Logger = ctrl.Log.WithName("replicationstatus")
+	_, err := register.GetDBManager()
+	if err != nil {
+		return errors.Wrap(err, "get manager failed")
+	}
+	return nil
+}
+
+func (s *ReplicationStatus) IsReadonly(context.Context) bool {
+	return true
+}
+
+func (s *ReplicationStatus) Do(ctx context.Context, req *operations.OpsRequest) (*operations.OpsResponse, error) {
+	resp := operations.NewOpsResponse(util.ReplicationStatusOperation)
+
+	dbManager, err := register.GetInstanceDBManager(req.Instance)
+	if err != nil {
+		return resp, err
+	}
+
+	status, err := dbManager.GetReplicationStatus(ctx)
+	if err != nil {
+		s.Logger.Info("executing replicationstatus error", "error", err)
+		return resp, err
+	}
+
+	resp.Data["status"] = status
+	return resp.WithSuccess("")
+}
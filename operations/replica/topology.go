@@ -0,0 +1,77 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package replica
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines/register"
+	"github.com/apecloud/dbctl/operations"
+	"github.com/apecloud/dbctl/util"
+)
+
+// Topology returns every member known by the local member, with the address,
+// the role, the health and the lag, see models.Topology.
+type Topology struct {
+	operations.Base
+}
+
+var topology operations.Operation = &Topology{}
+
+func init() {
+	err := operations.Register("topology", topology)
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+func (s *Topology) Init(context.Context) error {
+	s.Logger = ctrl.Log.WithName("topology")
+	_, err := register.GetDBManager()
+	if err != nil {
+		return errors.Wrap(err, "get manager failed")
+	}
+	return nil
+}
+
+func (s *Topology) IsReadonly(context.Context) bool {
+	return true
+}
+
+func (s *Topology) Do(ctx context.Context, req *operations.OpsRequest) (*operations.OpsResponse, error) {
+	resp := operations.NewOpsResponse(util.TopologyOperation)
+
+	dbManager, err := register.GetInstanceDBManager(req.Instance)
+	if err != nil {
+		return resp, err
+	}
+
+	result, err := dbManager.GetTopology(ctx)
+	if err != nil {
+		s.Logger.Info("executing topology error", "error", err)
+		return resp, err
+	}
+
+	resp.Data["topology"] = result
+	return resp.WithSuccess("")
+}
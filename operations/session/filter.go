@@ -0,0 +1,62 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package session
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/operations"
+)
+
+// getFilter returns the session filter of the request, minDuration is either a
+// duration like 30s or a number of seconds.
+func getFilter(req *operations.OpsRequest) (*models.SessionFilter, error) {
+	filter := &models.SessionFilter{
+		User:     req.GetString("user"),
+		Database: req.GetString("database"),
+		State:    req.GetString("state"),
+		Query:    req.GetString("query"),
+	}
+	value, ok := req.Parameters["minDuration"]
+	if !ok || value == nil || value == "" {
+		return filter, nil
+	}
+	if seconds, err := cast.ToFloat64E(value); err == nil {
+		filter.MinDuration = time.Duration(seconds * float64(time.Second))
+	} else {
+		filter.MinDuration, err = time.ParseDuration(cast.ToString(value))
+		if err != nil {
+			return nil, errors.Errorf("invalid minDuration %v", value)
+		}
+	}
+	if filter.MinDuration < 0 {
+		return nil, errors.Errorf("invalid minDuration %v", value)
+	}
+	return filter, nil
+}
+
+// getID returns the id parameter, which may be a number in JSON.
+func getID(req *operations.OpsRequest) string {
+	return cast.ToString(req.Parameters["id"])
+}
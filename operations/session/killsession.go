@@ -0,0 +1,125 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package session
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/register"
+	"github.com/apecloud/dbctl/operations"
+	"github.com/apecloud/dbctl/util"
+)
+
+// KillSession kills the session of the `id` parameter, or all the sessions
+// matching the filter parameters of listsessions. With the `dryRun` parameter
+// the sessions are only listed.
+type KillSession struct {
+	operations.Base
+}
+
+var killSession operations.Operation = &KillSession{}
+
+func init() {
+	err := operations.Register("killsession", killSession)
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+func (s *KillSession) Init(context.Context) error {
+	s.Logger = ctrl.Log.WithName("killsession")
+	_, err := register.GetDBManager()
+	if err != nil {
+		return errors.Wrap(err, "get manager failed")
+	}
+	return nil
+}
+
+func (s *KillSession) PreCheck(_ context.Context, req *operations.OpsRequest) error {
+	filter, err := getFilter(req)
+	if err != nil {
+		return err
+	}
+	// killing every session is never what is meant
+	if getID(req) == "" && filter.IsEmpty() {
+		return errors.New("id or filter of the sessions to kill is required")
+	}
+	return nil
+}
+
+func (s *KillSession) Do(ctx context.Context, req *operations.OpsRequest) (*operations.OpsResponse, error) {
+	resp := operations.NewOpsResponse(util.KillSessionOperation)
+
+	dbManager, err := register.GetInstanceDBManager(req.Instance)
+	if err != nil {
+		return resp, err
+	}
+	sessionManager, ok := dbManager.(engines.SessionManager)
+	if !ok {
+		return resp, models.ErrNotImplemented
+	}
+	filter, err := getFilter(req)
+	if err != nil {
+		return resp, err
+	}
+
+	result, err := s.killSessions(ctx, sessionManager, filter, getID(req), req.GetBool("dryRun"))
+	if err != nil {
+		s.Logger.Info("kill sessions failed", "error", err.Error())
+		return resp, err
+	}
+	resp.Data["result"] = result
+	return resp.WithSuccess("")
+}
+
+// killSessions kills the matched sessions one by one, a session which fails to be
+// killed, e.g. it has finished meanwhile, doesn't stop the others.
+func (s *KillSession) killSessions(ctx context.Context, sessionManager engines.SessionManager, filter *models.SessionFilter,
+	id string, dryRun bool) (*models.KillSessionResult, error) {
+	sessions, err := listMatchedSessions(ctx, sessionManager, filter, id)
+	if err != nil {
+		return nil, err
+	}
+	if id != "" && len(sessions) == 0 {
+		return nil, errors.Errorf("session %s not found", id)
+	}
+
+	result := &models.KillSessionResult{DryRun: dryRun, Killed: []models.Session{}}
+	if dryRun {
+		result.Killed = sessions
+		return result, nil
+	}
+	for _, session := range sessions {
+		err := sessionManager.KillSession(ctx, session.ID)
+		if err != nil {
+			s.Logger.Info("kill session failed", "id", session.ID, "error", err.Error())
+			result.Failed = append(result.Failed, models.FailedSessionKill{Session: session, Reason: err.Error()})
+			continue
+		}
+		s.Logger.Info("session killed", "id", session.ID, "user", session.User, "query", session.Query)
+		result.Killed = append(result.Killed, session)
+	}
+	return result, nil
+}
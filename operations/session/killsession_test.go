@@ -0,0 +1,115 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/operations"
+)
+
+type fakeSessionManager struct {
+	sessions []models.Session
+	killed   []string
+}
+
+func (f *fakeSessionManager) ListSessions(context.Context) ([]models.Session, error) {
+	return f.sessions, nil
+}
+
+func (f *fakeSessionManager) KillSession(_ context.Context, id string) error {
+	if id == "3" {
+		return errors.New("session not found")
+	}
+	f.killed = append(f.killed, id)
+	return nil
+}
+
+func newFakeSessionManager() *fakeSessionManager {
+	return &fakeSessionManager{sessions: []models.Session{
+		{ID: "1", User: "app", Database: "orders", State: "active", DurationSeconds: 120, Query: "SELECT * FROM orders"},
+		{ID: "2", User: "app", Database: "orders", State: "idle", DurationSeconds: 600, Query: "COMMIT"},
+		{ID: "3", User: "app", Database: "orders", State: "active", DurationSeconds: 90, Query: "select count(*) from orders"},
+		{ID: "4", User: "report", Database: "orders", State: "active", DurationSeconds: 5, Query: "select * from orders"},
+	}}
+}
+
+func TestGetFilter(t *testing.T) {
+	req := &operations.OpsRequest{Parameters: map[string]any{"user": "app", "minDuration": "1m"}}
+	filter, err := getFilter(req)
+	assert.Nil(t, err)
+	assert.Equal(t, &models.SessionFilter{User: "app", MinDuration: time.Minute}, filter)
+
+	req.Parameters["minDuration"] = float64(30)
+	filter, err = getFilter(req)
+	assert.Nil(t, err)
+	assert.Equal(t, 30*time.Second, filter.MinDuration)
+
+	req.Parameters["minDuration"] = "long"
+	_, err = getFilter(req)
+	assert.NotNil(t, err)
+}
+
+func TestKillSessions(t *testing.T) {
+	op := &KillSession{Base: operations.Base{Logger: ctrl.Log}}
+	filter := &models.SessionFilter{State: "ACTIVE", MinDuration: time.Minute, Query: "from orders"}
+
+	t.Run("dry run", func(t *testing.T) {
+		sessionManager := newFakeSessionManager()
+		result, err := op.killSessions(context.TODO(), sessionManager, filter, "", true)
+		assert.Nil(t, err)
+		assert.True(t, result.DryRun)
+		assert.Len(t, result.Killed, 2)
+		assert.Empty(t, sessionManager.killed)
+	})
+
+	t.Run("kill matched", func(t *testing.T) {
+		sessionManager := newFakeSessionManager()
+		result, err := op.killSessions(context.TODO(), sessionManager, filter, "", false)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"1"}, sessionManager.killed)
+		assert.Len(t, result.Failed, 1)
+		assert.Equal(t, "3", result.Failed[0].ID)
+	})
+
+	t.Run("kill by id", func(t *testing.T) {
+		sessionManager := newFakeSessionManager()
+		_, err := op.killSessions(context.TODO(), sessionManager, &models.SessionFilter{}, "4", false)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"4"}, sessionManager.killed)
+
+		_, err = op.killSessions(context.TODO(), sessionManager, &models.SessionFilter{}, "5", false)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestPreCheck(t *testing.T) {
+	op := &KillSession{}
+	assert.NotNil(t, op.PreCheck(context.TODO(), &operations.OpsRequest{Parameters: map[string]any{}}))
+	assert.Nil(t, op.PreCheck(context.TODO(), &operations.OpsRequest{Parameters: map[string]any{"id": float64(1)}}))
+	assert.Nil(t, op.PreCheck(context.TODO(), &operations.OpsRequest{Parameters: map[string]any{"user": "app"}}))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package session
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/apecloud/dbctl/engines"
+	"github.com/apecloud/dbctl/engines/models"
+	"github.com/apecloud/dbctl/engines/register"
+	"github.com/apecloud/dbctl/operations"
+	"github.com/apecloud/dbctl/util"
+)
+
+// ListSessions lists the sessions matching the user, database, state, minDuration
+// and query parameters.
+type ListSessions struct {
+	operations.Base
+}
+
+var listSessions operations.Operation = &ListSessions{}
+
+func init() {
+	err := operations.Register("listsessions", listSessions)
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+func (s *ListSessions) Init(context.Context) error {
+	s.Logger = ctrl.Log.WithName("listsessions")
+	_, err := register.GetDBManager()
+	if err != nil {
+		return errors.Wrap(err, "get manager failed")
+	}
+	return nil
+}
+
+func (s *ListSessions) IsReadonly(context.Context) bool {
+	return true
+}
+
+func (s *ListSessions) PreCheck(_ context.Context, req *operations.OpsRequest) error {
+	_, err := getFilter(req)
+	return err
+}
+
+func (s *ListSessions) Do(ctx context.Context, req *operations.OpsRequest) (*operations.OpsResponse, error) {
+	resp := operations.NewOpsResponse(util.ListSessionsOperation)
+
+	dbManager, err := register.GetInstanceDBManager(req.Instance)
+	if err != nil {
+		return resp, err
+	}
+	sessionManager, ok := dbManager.(engines.SessionManager)
+	if !ok {
+		return resp, models.ErrNotImplemented
+	}
+	filter, err := getFilter(req)
+	if err != nil {
+		return resp, err
+	}
+
+	sessions, err := listMatchedSessions(ctx, sessionManager, filter, "")
+	if err != nil {
+		s.Logger.Info("list sessions failed", "error", err.Error())
+		return resp, err
+	}
+	resp.Data["sessions"] = sessions
+	return resp.WithSuccess("")
+}
+
+// listMatchedSessions returns the sessions matching the filter, and the id if
+// it is not empty.
+func listMatchedSessions(ctx context.Context, sessionManager engines.SessionManager, filter *models.SessionFilter, id string) ([]models.Session, error) {
+	sessions, err := sessionManager.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matched := []models.Session{}
+	for i := range sessions {
+		if id != "" && sessions[i].ID != id {
+			continue
+		}
+		if filter.Match(&sessions[i]) {
+			matched = append(matched, sessions[i])
+		}
+	}
+	return matched, nil
+}
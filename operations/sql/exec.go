@@ -72,7 +72,12 @@ func (s *Exec) Do(ctx context.Context, req *operations.OpsRequest) (*operations.
 	}
 	resp.Data["operation"] = util.ExecOperation
 
-	count, err := s.dbManager.Exec(ctx, sql)
+	dbManager, err := register.GetInstanceDBManager(req.Instance)
+	if err != nil {
+		return resp, err
+	}
+
+	count, err := dbManager.Exec(ctx, sql)
 	if err != nil {
 		s.logger.Info("executing exec error", "error", err)
 		return resp, err
@@ -69,7 +69,12 @@ func (s *Query) Do(ctx context.Context, req *operations.OpsRequest) (*operations
 
 	resp := operations.NewOpsResponse(util.QueryOperation)
 
-	result, err := s.dbManager.Query(ctx, sql)
+	dbManager, err := register.GetInstanceDBManager(req.Instance)
+	if err != nil {
+		return resp, err
+	}
+
+	result, err := dbManager.Query(ctx, sql)
 	if err != nil {
 		s.logger.Info("executing query error", "error", err)
 		return resp, err
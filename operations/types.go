@@ -25,10 +25,19 @@ import (
 	"github.com/apecloud/dbctl/util"
 )
 
+const (
+	// FormatParameter asks the operation for the structured result in Data
+	// instead of the plain form, e.g. the role info instead of the role name.
+	FormatParameter = "format"
+	FormatJSON      = "json"
+)
+
 // OpsRequest is the request for Operation
 type OpsRequest struct {
 	Data       []byte         `json:"data,omitempty"`
 	Parameters map[string]any `json:"parameters,omitempty"`
+	// Instance is the named engine instance to operate, empty for the default instance.
+	Instance string `json:"instance,omitempty"`
 }
 
 func (r *OpsRequest) GetString(key string) string {
@@ -0,0 +1,237 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// FileEnvSuffix is the suffix of the env which points to a file holding the
+	// value instead of the value itself, e.g. KB_SERVICE_PASSWORD_FILE.
+	FileEnvSuffix = "_FILE"
+
+	// a secret update writes several files, wait for all of them before reloading.
+	credentialsReloadDelay = time.Second
+)
+
+type credentials struct {
+	lock     sync.Mutex
+	dir      string
+	files    map[string]struct{}
+	handlers []func()
+}
+
+var creds = &credentials{
+	files: map[string]struct{}{},
+}
+
+// SetCredentialsDir sets the directory of the mounted credentials, e.g. a
+// kubernetes secret with the `username` and `password` keys.
+func SetCredentialsDir(dir string) {
+	creds.lock.Lock()
+	defer creds.lock.Unlock()
+	creds.dir = dir
+}
+
+// LookupCredential returns the credential from the credentials dir if it is set,
+// otherwise from the first env set, where `<env>_FILE` is accepted for each env.
+// In the scope of a named instance, `<dir>/<instance>/<name>` is looked up first.
+func LookupCredential(name string, envs ...string) (string, bool) {
+	return LookupInstanceCredential(CurrentInstance(), name, envs...)
+}
+
+// LookupInstanceCredential is LookupCredential of the instance, empty for the
+// default one, it's used after the managers are initialized.
+func LookupInstanceCredential(instance, name string, envs ...string) (string, bool) {
+	creds.lock.Lock()
+	dir := creds.dir
+	creds.lock.Unlock()
+
+	if dir != "" {
+		if instance != "" {
+			if value, err := ReadCredentialFile(filepath.Join(dir, instance, name)); err == nil {
+				return value, true
+			}
+		}
+		if value, err := ReadCredentialFile(filepath.Join(dir, name)); err == nil {
+			return value, true
+		}
+	}
+	for _, env := range envs {
+		if value, ok := LookupInstanceEnv(instance, env); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// LookupEnv returns the value of the env, or the content of the file which
+// `<env>_FILE` points to. In the scope of a named instance, the env prefixed
+// with the instance name takes precedence.
+func LookupEnv(env string) (string, bool) {
+	return LookupInstanceEnv(CurrentInstance(), env)
+}
+
+// LookupInstanceEnv is LookupEnv of the instance, empty for the default one, it's
+// used after the managers are initialized.
+func LookupInstanceEnv(instance, env string) (string, bool) {
+	if instance != "" {
+		if value, ok := lookupEnv(InstanceEnvPrefix(instance) + env); ok {
+			return value, true
+		}
+	}
+	return lookupEnv(env)
+}
+
+func lookupEnv(env string) (string, bool) {
+	if viper.IsSet(env) {
+		return viper.GetString(env), true
+	}
+	if !viper.IsSet(env + FileEnvSuffix) {
+		return "", false
+	}
+
+	value, err := ReadCredentialFile(viper.GetString(env + FileEnvSuffix))
+	if err != nil {
+		ctrl.Log.WithName("credentials").Info("read env file failed", "env", env+FileEnvSuffix, "error", err.Error())
+		return "", false
+	}
+	return value, true
+}
+
+// ReadCredentialFile returns the content of a mounted secret file without the
+// trailing newline, the file is watched for rotation once WatchCredentials is started.
+func ReadCredentialFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "read credential file %s failed", path)
+	}
+
+	creds.lock.Lock()
+	creds.files[path] = struct{}{}
+	creds.lock.Unlock()
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// OnCredentialsChange registers a handler called after any credential file changed,
+// managers use it to refresh their connection pools. The handler of a named instance
+// looks up the credentials of the instance explicitly, e.g. by LookupInstanceCredential.
+func OnCredentialsChange(handler func()) {
+	creds.lock.Lock()
+	defer creds.lock.Unlock()
+	creds.handlers = append(creds.handlers, handler)
+}
+
+// WatchCredentials watches the credentials dir and the credential files read so far
+// until ctx is done, and calls the registered handlers when any of them changes.
+func WatchCredentials(ctx context.Context) error {
+	dirs := creds.watchDirs()
+	if len(dirs) == 0 {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "new credentials watcher failed")
+	}
+	for _, dir := range dirs {
+		// a kubernetes secret volume updates files by swapping symlinks,
+		// so the parent directory is watched instead of the files.
+		if err = watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return errors.Wrapf(err, "watch credentials dir %s failed", dir)
+		}
+	}
+
+	go creds.watch(ctx, watcher)
+	return nil
+}
+
+func (c *credentials) watchDirs() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	dirSet := map[string]struct{}{}
+	if c.dir != "" {
+		dirSet[c.dir] = struct{}{}
+	}
+	for file := range c.files {
+		dirSet[filepath.Dir(file)] = struct{}{}
+	}
+	dirs := make([]string, 0, len(dirSet))
+	for dir := range dirSet {
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+func (c *credentials) watch(ctx context.Context, watcher *fsnotify.Watcher) {
+	logger := ctrl.Log.WithName("credentials")
+	defer func() {
+		_ = watcher.Close()
+	}()
+
+	var reload <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			reload = time.After(credentialsReloadDelay)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			logger.Info("credentials watcher error", "error", err.Error())
+		case <-reload:
+			reload = nil
+			logger.Info("credentials changed, reload")
+			c.notify()
+		}
+	}
+}
+
+func (c *credentials) notify() {
+	c.lock.Lock()
+	handlers := make([]func(), len(c.handlers))
+	copy(handlers, c.handlers)
+	c.lock.Unlock()
+
+	for _, handler := range handlers {
+		handler()
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func resetCredentials(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Reset()
+		creds = &credentials{
+			files: map[string]struct{}{},
+		}
+	})
+}
+
+func TestLookupCredential(t *testing.T) {
+	resetCredentials(t)
+
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "root-password")
+	assert.Nil(t, os.WriteFile(passwordFile, []byte("secret\n"), 0600))
+
+	t.Run("env not set", func(t *testing.T) {
+		_, ok := LookupEnv("FAKE_PASSWORD")
+		assert.False(t, ok)
+	})
+
+	t.Run("env file", func(t *testing.T) {
+		viper.Set("FAKE_PASSWORD"+FileEnvSuffix, passwordFile)
+		password, ok := LookupEnv("FAKE_PASSWORD")
+		assert.True(t, ok)
+		assert.Equal(t, "secret", password)
+	})
+
+	t.Run("env takes precedence over env file", func(t *testing.T) {
+		viper.Set("FAKE_PASSWORD", "plain")
+		password, ok := LookupCredential("password", "FAKE_USER_PASSWORD", "FAKE_PASSWORD")
+		assert.True(t, ok)
+		assert.Equal(t, "plain", password)
+	})
+
+	t.Run("instance env takes precedence", func(t *testing.T) {
+		viper.Set("SENTINEL_FAKE_PASSWORD", "sentinel")
+		password, ok := LookupInstanceCredential("sentinel", "password", "FAKE_PASSWORD")
+		assert.True(t, ok)
+		assert.Equal(t, "sentinel", password)
+		// the default instance is not affected
+		password, ok = LookupCredential("password", "FAKE_PASSWORD")
+		assert.True(t, ok)
+		assert.Equal(t, "plain", password)
+	})
+
+	t.Run("credentials dir takes precedence over env", func(t *testing.T) {
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, "password"), []byte("mounted"), 0600))
+		SetCredentialsDir(dir)
+		password, ok := LookupCredential("password", "FAKE_PASSWORD")
+		assert.True(t, ok)
+		assert.Equal(t, "mounted", password)
+
+		_, ok = LookupCredential("username")
+		assert.False(t, ok)
+	})
+}
+
+func TestWatchCredentials(t *testing.T) {
+	resetCredentials(t)
+
+	dir := t.TempDir()
+	SetCredentialsDir(dir)
+	changed := make(chan struct{}, 1)
+	OnCredentialsChange(func() {
+		changed <- struct{}{}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.Nil(t, WatchCredentials(ctx))
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "password"), []byte("rotated"), 0600))
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("credentials change is not notified")
+	}
+	password, ok := LookupCredential("password")
+	assert.True(t, ok)
+	assert.Equal(t, "rotated", password)
+}
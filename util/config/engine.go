@@ -0,0 +1,160 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// EngineConfig is the per-engine section of the dbctl config file, keyed by the
+// engine type, e.g. `mysql:` or `redis:`. Every field is optional, values which
+// are not set keep the engine defaults, and environment variables still take
+// precedence over the file.
+type EngineConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Database string `mapstructure:"database"`
+
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// UsernameFile and PasswordFile point to files holding the credentials,
+	// they are used when Username or Password is empty.
+	UsernameFile string `mapstructure:"usernameFile"`
+	PasswordFile string `mapstructure:"passwordFile"`
+
+	TLS TLSConfig `mapstructure:"tls"`
+
+	MaxOpenConns int `mapstructure:"maxOpenConns"`
+	MaxIdleConns int `mapstructure:"maxIdleConns"`
+
+	ConnectTimeout time.Duration `mapstructure:"connectTimeout"`
+	ReadTimeout    time.Duration `mapstructure:"readTimeout"`
+	WriteTimeout   time.Duration `mapstructure:"writeTimeout"`
+}
+
+type TLSConfig struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	CAFile             string `mapstructure:"caFile"`
+	CertFile           string `mapstructure:"certFile"`
+	KeyFile            string `mapstructure:"keyFile"`
+	InsecureSkipVerify bool   `mapstructure:"insecureSkipVerify"`
+}
+
+// LoadFile reads the dbctl config file into viper, values in it have the
+// lowest precedence: credentials dir > env > file.
+func LoadFile(path string) error {
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		return errors.Wrapf(err, "read config file %s failed", path)
+	}
+	return nil
+}
+
+// GetEngineConfig returns the config file section of the engine, an empty
+// config is returned if the section does not exist. In the scope of a named
+// instance, the `instances.<instance>` section is returned instead.
+func GetEngineConfig(section string) (*EngineConfig, error) {
+	return GetInstanceEngineConfig(CurrentInstance(), section)
+}
+
+// GetInstanceEngineConfig is GetEngineConfig of the instance, empty for the default
+// one, it's used after the managers are initialized.
+func GetInstanceEngineConfig(instance, section string) (*EngineConfig, error) {
+	if instance != "" {
+		section = instancesSection + "." + instance
+	}
+
+	engineConfig := &EngineConfig{}
+	if !viper.IsSet(section) {
+		return engineConfig, nil
+	}
+
+	if err := Decode(viper.GetStringMap(section), engineConfig); err != nil {
+		return nil, errors.Wrapf(err, "decode config section %s failed", section)
+	}
+
+	if engineConfig.Username == "" && engineConfig.UsernameFile != "" {
+		username, err := ReadCredentialFile(engineConfig.UsernameFile)
+		if err != nil {
+			return nil, err
+		}
+		engineConfig.Username = username
+	}
+	if engineConfig.Password == "" && engineConfig.PasswordFile != "" {
+		password, err := ReadCredentialFile(engineConfig.PasswordFile)
+		if err != nil {
+			return nil, err
+		}
+		engineConfig.Password = password
+	}
+	return engineConfig, nil
+}
+
+// Addr returns host:port of the engine, the defaults are used for the unset parts.
+func (c *EngineConfig) Addr(defaultHost string, defaultPort int) string {
+	host := defaultHost
+	if c.Host != "" {
+		host = c.Host
+	}
+	port := defaultPort
+	if c.Port != 0 {
+		port = c.Port
+	}
+	return fmt.Sprintf("%s:%d", host, port)
+}
+
+// ClientConfig builds the tls.Config for the engine client, nil is returned if TLS is disabled.
+func (c *TLSConfig) ClientConfig() (*tls.Config, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+
+	/* #nosec */
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read CA file %s failed", c.CAFile)
+		}
+		rootCertPool := x509.NewCertPool()
+		if !rootCertPool.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("failed to append PEM from %s", c.CAFile)
+		}
+		tlsConfig.RootCAs = rootCertPool
+	}
+	if c.CertFile != "" && c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "load client certificate failed")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import (
+	"sync"
+)
+
+// CredentialLookup tells where a credential of an engine is looked up, after the
+// engine section of the config file.
+type CredentialLookup struct {
+	// Default is taken if the credential is not set anywhere.
+	Default string
+	// Name is the file in the credentials dir, and Envs are looked up in order
+	// after it, they take precedence over the config file.
+	Name string
+	Envs []string
+}
+
+// EngineCredentials is the username and password of an engine, which are reloaded
+// when the mounted secrets are rotated. It's embedded in the config of the engine.
+type EngineCredentials struct {
+	// instance is the instance whose scope the credentials are created in.
+	instance       string
+	section        string
+	usernameLookup CredentialLookup
+	passwordLookup CredentialLookup
+
+	// lock guards the credentials which are reloaded on rotation.
+	lock     sync.RWMutex
+	username string
+	password string
+}
+
+// NewEngineCredentials returns the credentials of the engine section, set to the
+// defaults until they are loaded.
+func NewEngineCredentials(section string, username, password CredentialLookup) *EngineCredentials {
+	return &EngineCredentials{
+		instance:       CurrentInstance(),
+		section:        section,
+		usernameLookup: username,
+		passwordLookup: password,
+		username:       username.Default,
+		password:       password.Default,
+	}
+}
+
+// LoadCredentials reads the credentials of the engine config, the credentials dir
+// and the envs take precedence over it. A credential set nowhere is kept.
+func (c *EngineCredentials) LoadCredentials(engineConfig *EngineConfig) {
+	username, usernameSet := c.lookup(engineConfig.Username, c.usernameLookup)
+	password, passwordSet := c.lookup(engineConfig.Password, c.passwordLookup)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if usernameSet {
+		c.username = username
+	}
+	if passwordSet {
+		c.password = password
+	}
+}
+
+// ReloadCredentials reads the credentials again, it's called when the mounted secrets are rotated.
+func (c *EngineCredentials) ReloadCredentials() error {
+	engineConfig, err := GetInstanceEngineConfig(c.instance, c.section)
+	if err != nil {
+		return err
+	}
+	c.LoadCredentials(engineConfig)
+	return nil
+}
+
+// GetCredentials returns the current username and password.
+func (c *EngineCredentials) GetCredentials() (string, string) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.username, c.password
+}
+
+// SetCredentials replaces the current username and password.
+func (c *EngineCredentials) SetCredentials(username, password string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.username, c.password = username, password
+}
+
+func (c *EngineCredentials) lookup(configValue string, lookup CredentialLookup) (string, bool) {
+	if lookup.Name != "" || len(lookup.Envs) > 0 {
+		if value, ok := LookupInstanceCredential(c.instance, lookup.Name, lookup.Envs...); ok {
+			return value, true
+		}
+	}
+	return configValue, configValue != ""
+}
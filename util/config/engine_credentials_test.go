@@ -0,0 +1,85 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngineCredentials(t *testing.T) {
+	resetCredentials(t)
+
+	credentials := NewEngineCredentials("fake",
+		CredentialLookup{Default: "admin", Name: "username", Envs: []string{"FAKE_USER"}},
+		CredentialLookup{Name: "password", Envs: []string{"FAKE_SERVICE_PASSWORD", "FAKE_PASSWORD"}})
+
+	t.Run("defaults", func(t *testing.T) {
+		credentials.LoadCredentials(&EngineConfig{})
+		username, password := credentials.GetCredentials()
+		assert.Equal(t, "admin", username)
+		assert.Equal(t, "", password)
+	})
+
+	t.Run("config file", func(t *testing.T) {
+		credentials.LoadCredentials(&EngineConfig{Username: "root", Password: "file"})
+		username, password := credentials.GetCredentials()
+		assert.Equal(t, "root", username)
+		assert.Equal(t, "file", password)
+	})
+
+	t.Run("env takes precedence over config file", func(t *testing.T) {
+		viper.Set("FAKE_PASSWORD", "env")
+		credentials.LoadCredentials(&EngineConfig{Username: "root", Password: "file"})
+		username, password := credentials.GetCredentials()
+		assert.Equal(t, "root", username)
+		assert.Equal(t, "env", password)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		viper.Set("fake.username", "reloaded")
+		viper.Set("FAKE_PASSWORD", "rotated")
+		assert.Nil(t, credentials.ReloadCredentials())
+		username, password := credentials.GetCredentials()
+		assert.Equal(t, "reloaded", username)
+		assert.Equal(t, "rotated", password)
+	})
+
+	t.Run("instance credentials reloaded out of the scope", func(t *testing.T) {
+		var instanceCredentials *EngineCredentials
+		assert.Nil(t, WithInstance("sentinel", func() error {
+			instanceCredentials = NewEngineCredentials("fake",
+				CredentialLookup{Name: "username", Envs: []string{"FAKE_USER"}},
+				CredentialLookup{Name: "password", Envs: []string{"FAKE_PASSWORD"}})
+			return nil
+		}))
+		viper.Set("SENTINEL_FAKE_PASSWORD", "sentinel")
+		assert.Nil(t, instanceCredentials.ReloadCredentials())
+		_, password := instanceCredentials.GetCredentials()
+		assert.Equal(t, "sentinel", password)
+
+		// the default instance is not affected
+		assert.Nil(t, credentials.ReloadCredentials())
+		_, password = credentials.GetCredentials()
+		assert.Equal(t, "rotated", password)
+	})
+}
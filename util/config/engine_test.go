@@ -0,0 +1,91 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const fakeConfigFile = `
+mysql:
+  host: 10.0.0.1
+  port: 3307
+  username: root
+  passwordFile: %s
+  maxOpenConns: 10
+  connectTimeout: 3s
+  tls:
+    enabled: true
+    insecureSkipVerify: true
+`
+
+func TestGetEngineConfig(t *testing.T) {
+	defer viper.Reset()
+
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	assert.Nil(t, os.WriteFile(passwordFile, []byte("secret\n"), 0600))
+	configFile := filepath.Join(dir, "dbctl.yaml")
+	content := strings.Replace(fakeConfigFile, "%s", passwordFile, 1)
+	assert.Nil(t, os.WriteFile(configFile, []byte(content), 0600))
+
+	t.Run("config file not exist", func(t *testing.T) {
+		err := LoadFile(filepath.Join(dir, "not-exist.yaml"))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("section not exist", func(t *testing.T) {
+		assert.Nil(t, LoadFile(configFile))
+		engineConfig, err := GetEngineConfig("redis")
+		assert.Nil(t, err)
+		assert.Equal(t, &EngineConfig{}, engineConfig)
+		assert.Equal(t, "127.0.0.1:6379", engineConfig.Addr("127.0.0.1", 6379))
+	})
+
+	t.Run("decode section successfully", func(t *testing.T) {
+		assert.Nil(t, LoadFile(configFile))
+		engineConfig, err := GetEngineConfig("mysql")
+		assert.Nil(t, err)
+		assert.Equal(t, "10.0.0.1:3307", engineConfig.Addr("127.0.0.1", 3306))
+		assert.Equal(t, "root", engineConfig.Username)
+		assert.Equal(t, "secret", engineConfig.Password)
+		assert.Equal(t, 10, engineConfig.MaxOpenConns)
+		assert.Equal(t, 3*time.Second, engineConfig.ConnectTimeout)
+		assert.True(t, engineConfig.TLS.Enabled)
+
+		tlsConfig, err := engineConfig.TLS.ClientConfig()
+		assert.Nil(t, err)
+		assert.True(t, tlsConfig.InsecureSkipVerify)
+	})
+
+	t.Run("credential file not exist", func(t *testing.T) {
+		viper.Set("postgresql.passwordFile", filepath.Join(dir, "not-exist"))
+		_, err := GetEngineConfig("postgresql")
+		assert.NotNil(t, err)
+		assert.ErrorContains(t, err, "read credential file")
+	})
+}
@@ -0,0 +1,130 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// instancesSection is the config file section of the named engine instances, e.g.
+//
+//	instances:
+//	  sentinel:
+//	    engine: redis
+//	    port: 26379
+const instancesSection = "instances"
+
+var instanceNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+type instanceScope struct {
+	// lock serializes the instance scopes, as they share the global viper.
+	lock sync.Mutex
+
+	currentLock sync.RWMutex
+	current     string
+}
+
+var scope = &instanceScope{}
+
+// ValidateInstanceName checks the name of an instance, which is used in the API routes.
+func ValidateInstanceName(instance string) error {
+	if !instanceNameRegexp.MatchString(instance) {
+		return errors.Errorf("invalid instance name %s, it must consist of lower case alphanumeric characters or '-'", instance)
+	}
+	return nil
+}
+
+// InstanceEnvPrefix returns the prefix of the envs of an instance, e.g. PGBOUNCER_ for pgbouncer.
+func InstanceEnvPrefix(instance string) string {
+	return strings.ToUpper(strings.ReplaceAll(instance, "-", "_")) + "_"
+}
+
+// GetInstanceEngines returns the engine type of each instance declared in the config file.
+func GetInstanceEngines() map[string]string {
+	instanceEngines := map[string]string{}
+	for instance := range viper.GetStringMap(instancesSection) {
+		instanceEngines[instance] = viper.GetString(instancesSection + "." + instance + ".engine")
+	}
+	return instanceEngines
+}
+
+// CurrentInstance returns the instance whose scope is entered, empty for the default
+// instance. It's only set while the managers are initialized by WithInstance, so a
+// manager keeps it to look up its envs and credentials later, e.g. on rotation.
+func CurrentInstance() string {
+	scope.currentLock.RLock()
+	defer scope.currentLock.RUnlock()
+	return scope.current
+}
+
+// WithInstance runs fn in the scope of an instance, where the `<INSTANCE>_` prefixed envs
+// override the unprefixed ones, and the engine config is read from `instances.<instance>`
+// of the config file. The default instance is used if instance is empty.
+//
+// The prefixed envs are set into viper while fn runs, so it's only meant for initializing
+// the engine managers before serving.
+func WithInstance(instance string, fn func() error) error {
+	if instance == "" {
+		return fn()
+	}
+
+	scope.lock.Lock()
+	defer scope.lock.Unlock()
+	scope.setCurrent(instance)
+	defer scope.setCurrent("")
+
+	restore := overrideEnvs(InstanceEnvPrefix(instance))
+	defer restore()
+	return fn()
+}
+
+func (s *instanceScope) setCurrent(instance string) {
+	s.currentLock.Lock()
+	defer s.currentLock.Unlock()
+	s.current = instance
+}
+
+// overrideEnvs sets the envs with the prefix into viper without the prefix,
+// and returns a function to restore the original values.
+func overrideEnvs(prefix string) func() {
+	origins := map[string]any{}
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		if !strings.HasPrefix(key, prefix) || key == prefix {
+			continue
+		}
+		key = strings.TrimPrefix(key, prefix)
+		origins[key] = viper.Get(key)
+		viper.Set(key, value)
+	}
+
+	return func() {
+		for key, origin := range origins {
+			// a nil override makes viper fall back to the env, config file and defaults
+			viper.Set(key, origin)
+		}
+	}
+}
@@ -25,9 +25,18 @@ const (
 	RespFieldEvent   = "event"
 	RespFieldMessage = "message"
 
-	ExecOperation    OperationKind = "exec"
-	QueryOperation   OperationKind = "query"
-	GetRoleOperation OperationKind = "getRole"
+	ExecOperation              OperationKind = "exec"
+	QueryOperation             OperationKind = "query"
+	GetRoleOperation           OperationKind = "getRole"
+	ReplicationStatusOperation OperationKind = "replicationStatus"
+	TopologyOperation          OperationKind = "topology"
+	CheckSplitBrainOperation   OperationKind = "checkSplitBrain"
+	ReconfigureOperation       OperationKind = "reconfigure"
+	ConfigDriftOperation       OperationKind = "configDrift"
+	ListSessionsOperation      OperationKind = "listSessions"
+	KillSessionOperation       OperationKind = "killSession"
+
+	FakeControlOperation OperationKind = "fakeControl"
 
 	OperationSuccess = "Success"
 	OperationFailed  = "Failed"